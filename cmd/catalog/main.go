@@ -0,0 +1,58 @@
+// Command catalog writes the catalog of the builtin metrics of the integration, checking which of them produce values
+// on the data recorded for each Kubernetes version.
+//
+//	go run ./cmd/catalog [-format json|markdown] [-data internal/testutil/data]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/recorded"
+	"github.com/newrelic/nri-kubernetes/v3/src/catalog"
+	"github.com/newrelic/nri-kubernetes/v3/src/catalog/coverage"
+	"github.com/newrelic/nri-kubernetes/v3/src/metric"
+)
+
+func main() {
+	logger := log.StandardLogger()
+
+	if err := run(os.Args[1:], os.Stdout, logger); err != nil {
+		logger.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+func run(args []string, w io.Writer, logger *log.Logger) error {
+	flags := flag.NewFlagSet("catalog", flag.ContinueOnError)
+	format := flags.String("format", catalog.FormatJSON, "Format of the catalog, either json or markdown.")
+	dataDir := flags.String("data", "internal/testutil/data", "Directory with the data recorded for each Kubernetes "+
+		"version, to check which metrics produce values on each of them. Set it empty to skip the check.")
+
+	if err := flags.Parse(args); err != nil {
+		return err //nolint: wrapcheck
+	}
+
+	if *format != catalog.FormatJSON && *format != catalog.FormatMarkdown {
+		return fmt.Errorf("%w %q", catalog.ErrUnknownFormat, *format)
+	}
+
+	c := catalog.New(metric.Builtin())
+
+	if *dataDir != "" {
+		if err := coverage.Check(context.Background(), c, recorded.New(os.DirFS(*dataDir)), logger); err != nil {
+			return fmt.Errorf("checking recorded data: %w", err)
+		}
+	}
+
+	if err := c.Write(w, *format); err != nil {
+		return fmt.Errorf("writing catalog: %w", err)
+	}
+
+	return nil
+}
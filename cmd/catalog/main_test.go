@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/src/catalog"
+)
+
+func TestRun(t *testing.T) {
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	var out bytes.Buffer
+	require.NoError(t, run([]string{"-format", "markdown", "-data", "../../internal/testutil/data"}, &out, logger))
+	assert.Contains(t, out.String(), "## pod")
+	assert.Regexp(t, `\| 1\.\d+ \|`, out.String(), "Versions of the recorded data must be listed")
+
+	assert.ErrorIs(t, run([]string{"-format", "yaml"}, &out, logger), catalog.ErrUnknownFormat)
+}
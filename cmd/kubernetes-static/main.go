@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -131,7 +132,7 @@ func main() {
 
 		logger.Infof("Starting job: %s", job.Name)
 
-		result := job.Populate(i, "test-cluster", logger, k8sVersion)
+		result := job.Populate(context.Background(), i, "test-cluster", logger, k8sVersion)
 
 		if result.Populated {
 			logger.Infof("Successfully populated job: %s", job.Name)
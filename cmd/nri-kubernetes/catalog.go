@@ -0,0 +1,36 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+
+	"github.com/newrelic/nri-kubernetes/v3/src/catalog"
+	"github.com/newrelic/nri-kubernetes/v3/src/metric"
+)
+
+const catalogCommand = "catalog"
+
+// runCatalog implements the catalog subcommand, which writes the catalog of the builtin metrics to w, and returns the
+// exit code of the integration. Checking the catalog against recorded data is left to the cmd/catalog tool, so the
+// integration does not link the fake clients it needs.
+//
+//	nri-kubernetes catalog [-format json|markdown]
+func runCatalog(args []string, w io.Writer) int {
+	flags := flag.NewFlagSet(catalogCommand, flag.ContinueOnError)
+	format := flags.String("format", catalog.FormatJSON, "Format of the catalog, either json or markdown.")
+
+	if err := flags.Parse(args); err != nil {
+		return exitConfig
+	}
+
+	if err := catalog.New(metric.Builtin()).Write(w, *format); err != nil {
+		logger.Errorf("Writing catalog: %v", err)
+		if errors.Is(err, catalog.ErrUnknownFormat) {
+			return exitConfig
+		}
+		return exitIntegration
+	}
+
+	return 0
+}
@@ -1,29 +1,40 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path"
 	"runtime"
 	"strings"
 	"time"
 
+	sdkMetric "github.com/newrelic/infra-integrations-sdk/data/metric"
 	sdk "github.com/newrelic/infra-integrations-sdk/integration"
 	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/util/homedir"
 
+	"github.com/newrelic/nri-kubernetes/v3/internal/attributes"
 	"github.com/newrelic/nri-kubernetes/v3/internal/config"
 	"github.com/newrelic/nri-kubernetes/v3/internal/discovery"
 	"github.com/newrelic/nri-kubernetes/v3/src/client"
 	"github.com/newrelic/nri-kubernetes/v3/src/controlplane"
+	"github.com/newrelic/nri-kubernetes/v3/src/dimensional"
 	"github.com/newrelic/nri-kubernetes/v3/src/integration"
+	"github.com/newrelic/nri-kubernetes/v3/src/integration/sink"
 	"github.com/newrelic/nri-kubernetes/v3/src/ksm"
 	ksmClient "github.com/newrelic/nri-kubernetes/v3/src/ksm/client"
 	"github.com/newrelic/nri-kubernetes/v3/src/kubelet"
 	kubeletClient "github.com/newrelic/nri-kubernetes/v3/src/kubelet/client"
+	"github.com/newrelic/nri-kubernetes/v3/src/metric"
 	"github.com/newrelic/nri-kubernetes/v3/src/prometheus"
 )
 
@@ -56,6 +67,10 @@ type clusterClients struct {
 func main() {
 	logger = log.StandardLogger()
 
+	if len(os.Args) > 1 && os.Args[1] == catalogCommand {
+		os.Exit(runCatalog(os.Args[2:], os.Stdout))
+	}
+
 	c, err := config.LoadConfig(config.DefaultConfigFolderName, config.DefaultConfigFileName)
 	if err != nil {
 		log.Error(err.Error())
@@ -75,6 +90,24 @@ func main() {
 		}
 	}
 
+	definitions, err := metric.LoadDefinitionFiles(metric.Builtin(), c.SpecFiles...)
+	if err != nil {
+		logger.Errorf("loading spec files: %v", err)
+		os.Exit(exitConfig)
+	}
+
+	definitions, err = metric.FilterDefinitions(definitions, c.Metrics)
+	if err != nil {
+		logger.Errorf("filtering metrics: %v", err)
+		os.Exit(exitConfig)
+	}
+
+	definitions, err = metric.ComputeDefinitions(definitions, c.Metrics)
+	if err != nil {
+		logger.Errorf("adding computed metrics: %v", err)
+		os.Exit(exitConfig)
+	}
+
 	integrationOptions := []integration.OptionFunc{
 		integration.WithLogger(logger),
 		integration.WithMetadata(integration.Metadata{
@@ -83,15 +116,29 @@ func main() {
 		}),
 	}
 
-	switch c.Sink.Type {
-	case config.SinkTypeHTTP:
-		integrationOptions = append(integrationOptions, integration.WithHTTPSink(c.Sink.HTTP))
-	case config.SinkTypeStdout:
-		// We don't need to do anything here to sink to stdout, as it's the default behavior of integration.Wrapper.
-		logger.Warn("Sinking metrics to stdout")
-	default:
-		log.Errorf("Unknown sink type %s", c.Sink.Type)
-		os.Exit(exitConfig)
+	for _, sinkDefinition := range c.Sink.Definitions() {
+		var sinkOption integration.OptionFunc
+		switch sinkDefinition.Type {
+		case config.SinkTypeHTTP:
+			sinkOption = integration.WithHTTPSink(sinkDefinition.HTTP)
+		case config.SinkTypeStdout:
+			logger.Warn("Sinking metrics to stdout")
+			sinkOption = integration.WithStdoutSink()
+		case config.SinkTypeFile:
+			logger.Warnf("Sinking metrics to file %q", sinkDefinition.File.Path)
+			sinkOption = integration.WithFileSink(sinkDefinition.File)
+		case config.SinkTypeStatsD:
+			sinkOption = integration.WithStatsDSink(sinkDefinition.StatsD, sourceTypes(definitions))
+		default:
+			log.Errorf("Unknown sink type %s", sinkDefinition.Type)
+			os.Exit(exitConfig)
+		}
+
+		integrationOptions = append(integrationOptions, integration.Named(sinkDefinition.Name, sinkOption))
+	}
+
+	if len(c.Sink.Routes) > 0 || c.Sink.DefaultRoute != "" {
+		integrationOptions = append(integrationOptions, integration.WithRoutes(c.Sink.Routes, c.Sink.DefaultRoute))
 	}
 
 	iw, err := integration.NewWrapper(integrationOptions...)
@@ -99,6 +146,11 @@ func main() {
 		logger.Errorf("creating integration wrapper: %v", err)
 		os.Exit(exitIntegration)
 	}
+	defer iw.Close()
+
+	if c.ReadinessAddr != "" {
+		go serveReadiness(c.ReadinessAddr, iw, logger)
+	}
 
 	i, err := iw.Integration()
 	if err != nil {
@@ -106,6 +158,18 @@ func main() {
 		os.Exit(exitIntegration)
 	}
 
+	// Dimensional metrics are written by their own emitter, and replace the payloads of the integration.
+	var emitter *dimensional.Emitter
+	publish := i.Publish
+	if c.Dimensional() {
+		emitter, err = iw.Emitter()
+		if err != nil {
+			logger.Errorf("creating dimensional metrics emitter: %v", err)
+			os.Exit(exitIntegration)
+		}
+		publish = emitter.Publish
+	}
+
 	logger.Infof(
 		"New Relic %s integration Version: %s, Platform: %s, GoVersion: %s, GitCommit: %s, BuildDate: %s\n",
 		strings.Title(strings.Replace(integrationName, "com.newrelic.", "", 1)),
@@ -121,11 +185,18 @@ func main() {
 		os.Exit(exitClients)
 	}
 
+	customAttributes, closeCustomAttributes, err := setupCustomAttributes(c, clients)
+	if err != nil {
+		logger.Errorf("setting up custom attributes: %v", err)
+		os.Exit(exitConfig)
+	}
+	defer closeCustomAttributes()
+
 	namespaceCache := discovery.NewNamespaceInMemoryStore(logger)
 
 	var kubeletScraper *kubelet.Scraper
 	if c.Kubelet.Enabled {
-		kubeletScraper, err = setupKubelet(c, clients, namespaceCache)
+		kubeletScraper, err = setupKubelet(c, clients, namespaceCache, definitions[metric.TargetKubelet], customAttributes, emitter)
 		if err != nil {
 			logger.Errorf("setting up kubelet scraper: %v", err)
 			os.Exit(exitSetup)
@@ -135,7 +206,7 @@ func main() {
 
 	var ksmScraper *ksm.Scraper
 	if c.KSM.Enabled {
-		ksmScraper, err = setupKSM(c, clients, namespaceCache)
+		ksmScraper, err = setupKSM(c, clients, namespaceCache, definitions[metric.TargetKSM], customAttributes, emitter)
 		if err != nil {
 			logger.Errorf("setting up ksm scraper: %v", err)
 			os.Exit(exitSetup)
@@ -145,7 +216,7 @@ func main() {
 
 	var controlplaneScraper *controlplane.Scraper
 	if c.ControlPlane.Enabled {
-		controlplaneScraper, err = setupControlPlane(c, clients)
+		controlplaneScraper, err = setupControlPlane(c, clients, definitions, customAttributes, emitter)
 		if err != nil {
 			logger.Errorf("setting up control plane scraper: %v", err)
 			os.Exit(exitSetup)
@@ -159,21 +230,32 @@ func main() {
 		logger.Debugf("scraping data from all the scrapers defined: KSM: %t, Kubelet: %t, ControlPlane: %t",
 			c.KSM.Enabled, c.Kubelet.Enabled, c.ControlPlane.Enabled)
 
-		// TODO think carefully to the signature of this function
+		// Requests still in flight when the cycle exceeds its deadline are aborted, so a slow endpoint cannot delay
+		// the following cycles. What was populated until then is published anyway.
+		ctx, cancel := cycleContext(c)
 		runScaperTime := measureTime(func() {
-			err = runScrapers(c, ksmScraper, kubeletScraper, controlplaneScraper, i)
+			err = runScrapers(ctx, c, ksmScraper, kubeletScraper, controlplaneScraper, i)
 		})
-		if err != nil {
+		deadlineExceeded := errors.Is(ctx.Err(), context.DeadlineExceeded)
+		cancel()
+		if err != nil && deadlineExceeded {
+			logger.Warnf("scrape cycle exceeded its deadline of %s, publishing partial data: %v", c.CycleTimeout(), err)
+		} else if err != nil {
 			logger.Errorf("retrieving scraper data: %v", err)
+			// os.Exit skips deferred calls, so sinks are flushed explicitly.
+			_ = iw.Close()
 			os.Exit(exitLoop)
 		}
 
 		logger.Debugf("publishing data")
 		publishTime := measureTime(func() {
-			err = i.Publish()
+			err = publish()
 		})
-		if err != nil {
+		if errors.Is(err, sink.ErrPaused) {
+			logger.Warnf("metrics were not published, waiting for sinks to be ready: %v", iw.Ready())
+		} else if err != nil {
 			logger.Errorf("publishing integration: %v", err)
+			_ = iw.Close()
 			os.Exit(exitLoop)
 		}
 
@@ -194,22 +276,59 @@ func main() {
 	}
 }
 
+// sourceTypes returns the source type of every metric the integration can report, indexed by event type and name.
+func sourceTypes(definitions map[string]metric.Definitions) map[string]map[string]sdkMetric.SourceType {
+	types := map[string]map[string]sdkMetric.SourceType{}
+	for _, d := range definitions {
+		for eventType, metrics := range d.Specs.SourceTypes() {
+			if types[eventType] == nil {
+				types[eventType] = map[string]sdkMetric.SourceType{}
+			}
+			for name, sourceType := range metrics {
+				types[eventType][name] = sourceType
+			}
+		}
+	}
+
+	return types
+}
+
+// serveReadiness serves at /ready whether the sinks of iw are ready to receive data, for health checks to observe it.
+func serveReadiness(addr string, iw *integration.Wrapper, logger *log.Logger) {
+	mux := http.NewServeMux()
+	mux.Handle("/ready", iw.ReadyHandler())
+
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	if err := server.ListenAndServe(); err != nil {
+		logger.Errorf("serving sink readiness at %s: %v", addr, err)
+	}
+}
+
+// cycleContext returns the context bounding a metric collection run, which has a deadline only if configured.
+func cycleContext(c *config.Config) (context.Context, context.CancelFunc) {
+	if timeout := c.CycleTimeout(); timeout > 0 {
+		return context.WithTimeout(context.Background(), timeout)
+	}
+
+	return context.WithCancel(context.Background())
+}
+
 func measureTime(fn func()) time.Duration {
 	start := time.Now()
 	fn()
 	return time.Since(start)
 }
 
-func runScrapers(c *config.Config, ksmScraper *ksm.Scraper, kubeletScraper *kubelet.Scraper, controlplaneScraper *controlplane.Scraper, i *sdk.Integration) error {
+func runScrapers(ctx context.Context, c *config.Config, ksmScraper *ksm.Scraper, kubeletScraper *kubelet.Scraper, controlplaneScraper *controlplane.Scraper, i *sdk.Integration) error {
 	if c.KSM.Enabled {
-		err := ksmScraper.Run(i)
+		err := ksmScraper.Run(ctx, i)
 		if err != nil {
 			return fmt.Errorf("retrieving ksm data: %w", err)
 		}
 	}
 
 	if c.Kubelet.Enabled {
-		err := kubeletScraper.Run(i)
+		err := kubeletScraper.Run(ctx, i)
 		if err != nil {
 			if kubeletScraper.IsMaxRerunReached() {
 				return fmt.Errorf("retrieving kubelet data: %w", err)
@@ -220,7 +339,7 @@ func runScrapers(c *config.Config, ksmScraper *ksm.Scraper, kubeletScraper *kube
 	}
 
 	if c.ControlPlane.Enabled {
-		err := controlplaneScraper.Run(i)
+		err := controlplaneScraper.Run(ctx, i)
 		if err != nil {
 			return fmt.Errorf("retrieving control plane data: %w", err)
 		}
@@ -229,22 +348,30 @@ func runScrapers(c *config.Config, ksmScraper *ksm.Scraper, kubeletScraper *kube
 	return nil
 }
 
-func setupKSM(c *config.Config, clients *clusterClients, namespaceCache *discovery.NamespaceInMemoryStore) (*ksm.Scraper, error) {
+func setupKSM(c *config.Config, clients *clusterClients, namespaceCache *discovery.NamespaceInMemoryStore, definitions metric.Definitions, customAttributes *attributes.Decorator, emitter *dimensional.Emitter) (*ksm.Scraper, error) {
 	providers := ksm.Providers{
 		K8s: clients.k8s,
 		KSM: clients.ksm,
 	}
 
-	scraperOpts := []ksm.ScraperOpt{ksm.WithLogger(logger)}
+	scraperOpts := []ksm.ScraperOpt{ksm.WithLogger(logger), ksm.WithDefinitions(definitions), ksm.WithCustomAttributes(customAttributes), ksm.WithDimensionalEmitter(emitter)}
+
+	var nsFilter *discovery.NamespaceFilter
+	if c.NamespaceSelector != nil || c.HonorScrapeAnnotation {
+		nsFilter = discovery.NewNamespaceFilter(c.NamespaceSelector, clients.k8s, logger)
+	}
 
 	if c.NamespaceSelector != nil {
-		nsFilter := discovery.NewNamespaceFilter(c.NamespaceSelector, clients.k8s, logger)
 		scraperOpts = append(
 			scraperOpts,
 			ksm.WithFilterer(discovery.NewCachedNamespaceFilter(nsFilter, namespaceCache)),
 		)
 	}
 
+	if c.HonorScrapeAnnotation {
+		scraperOpts = append(scraperOpts, ksm.WithOptOutFilter(discovery.NewOptOutFilter(nsFilter, clients.k8s)))
+	}
+
 	ksmScraper, err := ksm.NewScraper(c, providers, scraperOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("building KSM scraper: %w", err)
@@ -253,7 +380,7 @@ func setupKSM(c *config.Config, clients *clusterClients, namespaceCache *discove
 	return ksmScraper, nil
 }
 
-func setupControlPlane(c *config.Config, clients *clusterClients) (*controlplane.Scraper, error) {
+func setupControlPlane(c *config.Config, clients *clusterClients, definitions map[string]metric.Definitions, customAttributes *attributes.Decorator, emitter *dimensional.Emitter) (*controlplane.Scraper, error) {
 	providers := controlplane.Providers{
 		K8s: clients.k8s,
 	}
@@ -268,6 +395,9 @@ func setupControlPlane(c *config.Config, clients *clusterClients) (*controlplane
 		providers,
 		controlplane.WithLogger(logger),
 		controlplane.WithRestConfig(restConfig),
+		controlplane.WithDefinitions(definitions),
+		controlplane.WithCustomAttributes(customAttributes),
+		controlplane.WithDimensionalEmitter(emitter),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("building control plane scraper: %w", err)
@@ -276,23 +406,35 @@ func setupControlPlane(c *config.Config, clients *clusterClients) (*controlplane
 	return controlplaneScraper, nil
 }
 
-func setupKubelet(c *config.Config, clients *clusterClients, namespaceCache *discovery.NamespaceInMemoryStore) (*kubelet.Scraper, error) {
+func setupKubelet(c *config.Config, clients *clusterClients, namespaceCache *discovery.NamespaceInMemoryStore, definitions metric.Definitions, customAttributes *attributes.Decorator, emitter *dimensional.Emitter) (*kubelet.Scraper, error) {
 	providers := kubelet.Providers{
 		K8s:      clients.k8s,
 		Kubelet:  clients.kubelet,
 		CAdvisor: clients.cAdvisor,
 	}
 
-	scraperOpts := []kubelet.ScraperOpt{kubelet.WithLogger(logger)}
+	scraperOpts := []kubelet.ScraperOpt{kubelet.WithLogger(logger), kubelet.WithDefinitions(definitions), kubelet.WithCustomAttributes(customAttributes), kubelet.WithDimensionalEmitter(emitter)}
+
+	var nsFilter *discovery.NamespaceFilter
+	if c.NamespaceSelector != nil || c.HonorScrapeAnnotation {
+		nsFilter = discovery.NewNamespaceFilter(c.NamespaceSelector, clients.k8s, logger)
+	}
 
 	if c.NamespaceSelector != nil {
-		nsFilter := discovery.NewNamespaceFilter(c.NamespaceSelector, clients.k8s, logger)
 		scraperOpts = append(
 			scraperOpts,
 			kubelet.WithFilterer(discovery.NewCachedNamespaceFilter(nsFilter, namespaceCache)),
 		)
 	}
 
+	if c.HonorScrapeAnnotation {
+		// The kubelet only reports the pods of its node, so the informer only watches those.
+		nodePods := informers.WithTweakListOptions(func(options *metav1.ListOptions) {
+			options.FieldSelector = fields.OneTermEqualSelector("spec.nodeName", c.NodeName).String()
+		})
+		scraperOpts = append(scraperOpts, kubelet.WithOptOutFilter(discovery.NewOptOutFilter(nsFilter, clients.k8s, nodePods)))
+	}
+
 	ksmScraper, err := kubelet.NewScraper(c, providers, scraperOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("building kubelet scraper: %w", err)
@@ -301,6 +443,29 @@ func setupKubelet(c *config.Config, clients *clusterClients, namespaceCache *dis
 	return ksmScraper, nil
 }
 
+// setupCustomAttributes builds the decorator adding the configured custom attributes to entities, which reads the
+// labels of nodes and namespaces from informers. The returned function stops the informers.
+func setupCustomAttributes(c *config.Config, clients *clusterClients) (*attributes.Decorator, func(), error) {
+	if len(c.CustomAttributes) == 0 {
+		return nil, func() {}, nil
+	}
+
+	nodes, nodesCloser := discovery.NewNodeLister(clients.k8s)
+	namespaces, namespacesCloser := discovery.NewNamespaceLister(clients.k8s)
+	closeInformers := func() {
+		close(nodesCloser)
+		close(namespacesCloser)
+	}
+
+	decorator, err := attributes.NewDecorator(c.CustomAttributes, attributes.NewListerLabelsGetter(nodes, namespaces))
+	if err != nil {
+		closeInformers()
+		return nil, nil, fmt.Errorf("building custom attributes: %w", err)
+	}
+
+	return decorator, closeInformers, nil
+}
+
 func buildClients(c *config.Config) (*clusterClients, error) {
 	k8sConfig, err := getK8sConfig(c)
 	if err != nil {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -11,6 +12,7 @@ import (
 	"github.com/newrelic/nri-kubernetes/v3/internal/config"
 	"github.com/newrelic/nri-kubernetes/v3/internal/discovery"
 	"github.com/newrelic/nri-kubernetes/v3/internal/logutil"
+	"github.com/newrelic/nri-kubernetes/v3/src/metric"
 )
 
 func TestSetupKubelet(t *testing.T) {
@@ -29,7 +31,7 @@ func TestSetupKubelet(t *testing.T) {
 	providers := clusterClients{
 		k8s: fake.NewSimpleClientset(),
 	}
-	scraper, err := setupKSM(&c, &providers, namespaceCache)
+	scraper, err := setupKSM(&c, &providers, namespaceCache, metric.Builtin()[metric.TargetKSM], nil, nil)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, scraper)
 	assert.NotEmpty(t, scraper.Filterer)
@@ -51,7 +53,7 @@ func TestSetupKSM(t *testing.T) {
 	providers := clusterClients{
 		k8s: fake.NewSimpleClientset(),
 	}
-	scraper, err := setupKSM(&c, &providers, namespaceCache)
+	scraper, err := setupKSM(&c, &providers, namespaceCache, metric.Builtin()[metric.TargetKSM], nil, nil)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, scraper)
 	assert.NotEmpty(t, scraper.Filterer)
@@ -82,3 +84,28 @@ func TestMeasureTimeWithZeroDuration(t *testing.T) {
 	assert.True(t, duration >= 0, "duration should be non-negative")
 	assert.True(t, duration < 10*time.Millisecond, "duration should be very small for empty function")
 }
+
+func TestCycleContext(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := cycleContext(&config.Config{Interval: 15 * time.Second})
+	defer cancel()
+	_, hasDeadline := ctx.Deadline()
+	assert.False(t, hasDeadline, "cycles should have no deadline by default")
+
+	ctx, cancel = cycleContext(&config.Config{Interval: 15 * time.Second, ScrapeTimeout: 10 * time.Second})
+	defer cancel()
+	deadline, hasDeadline := ctx.Deadline()
+	assert.True(t, hasDeadline)
+	assert.WithinDuration(t, time.Now().Add(10*time.Second), deadline, time.Second)
+}
+
+func TestRunCatalog(t *testing.T) {
+	logger = logutil.Discard
+
+	var out bytes.Buffer
+	assert.Equal(t, 0, runCatalog([]string{"-format", "markdown"}, &out))
+	assert.Contains(t, out.String(), "## pod")
+
+	assert.Equal(t, exitConfig, runCatalog([]string{"-format", "yaml"}, &out))
+}
@@ -0,0 +1,178 @@
+// Package attributes decorates entities with the custom attributes configured by users, like the environment or the
+// team owning them, which can be templated from the labels of the node and namespace of each entity.
+package attributes
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+
+	"github.com/newrelic/infra-integrations-sdk/data/attribute"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/config"
+	"github.com/newrelic/nri-kubernetes/v3/internal/pattern"
+)
+
+var ErrInvalidTemplate = errors.New("invalid custom attribute template")
+
+// LabelsGetter returns the labels of the nodes and namespaces custom attributes are templated from.
+type LabelsGetter interface {
+	NodeLabels(name string) (map[string]string, error)
+	NamespaceLabels(name string) (map[string]string, error)
+}
+
+// NewListerLabelsGetter returns a LabelsGetter reading the labels of nodes and namespaces from informer listers.
+func NewListerLabelsGetter(nodes listersv1.NodeLister, namespaces listersv1.NamespaceLister) LabelsGetter {
+	return listerLabelsGetter{nodes: nodes, namespaces: namespaces}
+}
+
+type listerLabelsGetter struct {
+	nodes      listersv1.NodeLister
+	namespaces listersv1.NamespaceLister
+}
+
+func (g listerLabelsGetter) NodeLabels(name string) (map[string]string, error) {
+	node, err := g.nodes.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("getting node %q: %w", name, err)
+	}
+
+	return node.Labels, nil
+}
+
+func (g listerLabelsGetter) NamespaceLabels(name string) (map[string]string, error) {
+	namespace, err := g.namespaces.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("getting namespace %q: %w", name, err)
+	}
+
+	return namespace.Labels, nil
+}
+
+type customAttribute struct {
+	name        string
+	entityTypes pattern.EntityTypes
+	// value is nil for attributes that are not templated, which always have the static value.
+	value  *template.Template
+	static string
+}
+
+// Decorator computes the custom attributes of entities, as configured by config.CustomAttributes. A Decorator is safe
+// for concurrent use.
+type Decorator struct {
+	attributes []customAttribute
+	labels     LabelsGetter
+}
+
+// NewDecorator parses the values of attrs into a Decorator. labels is used to read the labels templated values refer
+// to, and can be nil if none of them do.
+func NewDecorator(attrs []config.CustomAttribute, labels LabelsGetter) (*Decorator, error) {
+	d := &Decorator{labels: labels}
+
+	for _, attr := range attrs {
+		ca := customAttribute{
+			name:        attr.Name,
+			entityTypes: attr.EntityTypes,
+			static:      attr.Value,
+		}
+
+		if strings.Contains(attr.Value, "{{") {
+			tmpl, err := template.New(attr.Name).Option("missingkey=zero").Parse(attr.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%w %q: %w", ErrInvalidTemplate, attr.Name, err)
+			}
+
+			// Execute the template once to catch references to anything other than the label getters, which parsing
+			// does not detect.
+			if err := tmpl.Execute(io.Discard, templateData{}); err != nil {
+				return nil, fmt.Errorf("%w %q: %w", ErrInvalidTemplate, attr.Name, err)
+			}
+
+			ca.value = tmpl
+		}
+
+		d.attributes = append(d.attributes, ca)
+	}
+
+	return d, nil
+}
+
+// Entity identifies an entity and the node and namespace it belongs to, whose labels can be used in custom
+// attributes. Node and Namespace are empty for entities that do not belong to any.
+type Entity struct {
+	Type      string
+	Node      string
+	Namespace string
+}
+
+// Attributes returns the custom attributes of the entity. Attributes with an empty value, like the ones templated from
+// labels the node or namespace of the entity do not have, are omitted. It is safe to call on a nil Decorator.
+func (d *Decorator) Attributes(e Entity) []attribute.Attribute {
+	if d == nil {
+		return nil
+	}
+
+	var attrs []attribute.Attribute
+	data := templateData{labels: d.labels, entity: e}
+
+	for _, ca := range d.attributes {
+		if !ca.entityTypes.Matches(e.Type) {
+			continue
+		}
+
+		value := ca.static
+		if ca.value != nil {
+			var sb strings.Builder
+			if err := ca.value.Execute(&sb, data); err != nil {
+				continue
+			}
+			value = sb.String()
+		}
+
+		if value == "" {
+			continue
+		}
+
+		attrs = append(attrs, attribute.Attr(ca.name, value))
+	}
+
+	return attrs
+}
+
+// templateData is what the values of custom attributes are templated with. Labels are only read when a template
+// refers to them.
+type templateData struct {
+	labels LabelsGetter
+	entity Entity
+}
+
+// NodeLabel returns the value of the label of the node of the entity, or an empty string if it has none.
+func (t templateData) NodeLabel(key string) string {
+	if t.labels == nil || t.entity.Node == "" {
+		return ""
+	}
+
+	labels, err := t.labels.NodeLabels(t.entity.Node)
+	if err != nil {
+		return ""
+	}
+
+	return labels[key]
+}
+
+// NamespaceLabel returns the value of the label of the namespace of the entity, or an empty string if it has none.
+func (t templateData) NamespaceLabel(key string) string {
+	if t.labels == nil || t.entity.Namespace == "" {
+		return ""
+	}
+
+	labels, err := t.labels.NamespaceLabels(t.entity.Namespace)
+	if err != nil {
+		return ""
+	}
+
+	return labels[key]
+}
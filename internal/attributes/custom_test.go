@@ -0,0 +1,118 @@
+package attributes_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/newrelic/infra-integrations-sdk/data/attribute"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/attributes"
+	"github.com/newrelic/nri-kubernetes/v3/internal/config"
+)
+
+var errNotFound = errors.New("not found")
+
+type fakeLabelsGetter struct {
+	nodes      map[string]map[string]string
+	namespaces map[string]map[string]string
+}
+
+func (f fakeLabelsGetter) NodeLabels(name string) (map[string]string, error) {
+	labels, ok := f.nodes[name]
+	if !ok {
+		return nil, errNotFound
+	}
+
+	return labels, nil
+}
+
+func (f fakeLabelsGetter) NamespaceLabels(name string) (map[string]string, error) {
+	labels, ok := f.namespaces[name]
+	if !ok {
+		return nil, errNotFound
+	}
+
+	return labels, nil
+}
+
+func TestDecorator(t *testing.T) {
+	t.Parallel()
+
+	getter := fakeLabelsGetter{
+		nodes:      map[string]map[string]string{"node-1": {"topology.kubernetes.io/region": "eu-west-1"}},
+		namespaces: map[string]map[string]string{"team-a": {"team": "a"}, "kube-system": {}},
+	}
+
+	decorator, err := attributes.NewDecorator([]config.CustomAttribute{
+		{Name: "environment", Value: "production"},
+		{Name: "region", Value: `{{ .NodeLabel "topology.kubernetes.io/region" }}`},
+		{Name: "team", Value: `team-{{ .NamespaceLabel "team" }}`, EntityTypes: []string{"namespace", "pod"}},
+		{Name: "owner", Value: `{{ .NamespaceLabel "team" }}`},
+	}, getter)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		entity   attributes.Entity
+		expected []attribute.Attribute
+	}{
+		"templates_node_and_namespace_labels": {
+			entity: attributes.Entity{Type: "pod", Node: "node-1", Namespace: "team-a"},
+			expected: []attribute.Attribute{
+				attribute.Attr("environment", "production"),
+				attribute.Attr("region", "eu-west-1"),
+				attribute.Attr("team", "team-a"),
+				attribute.Attr("owner", "a"),
+			},
+		},
+		"skips_attributes_for_other_entity_types": {
+			entity: attributes.Entity{Type: "node", Node: "node-1"},
+			expected: []attribute.Attribute{
+				attribute.Attr("environment", "production"),
+				attribute.Attr("region", "eu-west-1"),
+			},
+		},
+		"omits_empty_values": {
+			entity: attributes.Entity{Type: "pod", Node: "unknown", Namespace: "kube-system"},
+			expected: []attribute.Attribute{
+				attribute.Attr("environment", "production"),
+				attribute.Attr("team", "team-"),
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, decorator.Attributes(tc.entity))
+		})
+	}
+}
+
+func TestDecorator_Nil(t *testing.T) {
+	t.Parallel()
+
+	var decorator *attributes.Decorator
+	assert.Empty(t, decorator.Attributes(attributes.Entity{Type: "pod"}))
+}
+
+func TestNewDecorator_InvalidTemplate(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"syntax_error":    `{{ .NodeLabel "region" `,
+		"unknown_method":  `{{ .PodLabel "app" }}`,
+		"wrong_arguments": `{{ .NodeLabel }}`,
+	}
+
+	for name, value := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := attributes.NewDecorator([]config.CustomAttribute{{Name: "region", Value: value}}, nil)
+			assert.ErrorIs(t, err, attributes.ErrInvalidTemplate)
+		})
+	}
+}
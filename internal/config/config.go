@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/spf13/viper"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/pattern"
 )
 
 const (
@@ -22,8 +24,18 @@ const (
 
 	DefaultNetworkRouteFile = "/proc/net/route"
 
+	DefaultPopulateErrorsWarnRatioChange = 0.1
+
 	SinkTypeHTTP   = "http"
 	SinkTypeStdout = "stdout"
+	SinkTypeFile   = "file"
+	SinkTypeStatsD = "statsd"
+
+	// OutputModeEntities reports the metrics of each entity as a metric set, like K8sContainerSample.
+	OutputModeEntities = "entities"
+	// OutputModeDimensional reports every metric as a dimensional metric, with the attributes of its entity as
+	// dimensions.
+	OutputModeDimensional = "dimensional"
 )
 
 type Config struct {
@@ -44,15 +56,16 @@ type Config struct {
 	NodeName string `mapstructure:"nodeName"`
 	// Interval is the time the integration will wait between metric collection runs.
 	Interval time.Duration `mapstructure:"interval"`
+	// ScrapeTimeout is the maximum time a metric collection run can take. Requests still in flight and pending retries
+	// are aborted once it is exceeded, and the metrics populated until then are published. If zero, runs have no
+	// deadline.
+	ScrapeTimeout time.Duration `mapstructure:"scrapeTimeout"`
 
 	// Sink defines where the integration will report the metrics to.
-	Sink struct {
-		// Type allows selecting which of the supported sinks will be used by the integration.
-		// Supported values are `http` and `stdout`.
-		Type string `mapstructure:"type"`
-		// HTTP stores the configuration for the HTTP sink.
-		HTTP HTTPSink `mapstructure:"http"`
-	} `mapstructure:"sink"`
+	Sink Sink `mapstructure:"sink"`
+	// ReadinessAddr is the address, like `:8088`, of an HTTP server answering at `/ready` whether the HTTP sinks
+	// are ready to receive data, with a 200 status if they are and a 503 one otherwise. If empty, no server is started.
+	ReadinessAddr string `mapstructure:"readinessAddr"`
 
 	// ControlPlane defines config options for the control plane scraper.
 	ControlPlane `mapstructure:"controlPlane"`
@@ -63,12 +76,255 @@ type Config struct {
 
 	// NamespaceSelector defines custom monitoring filtering for namespaces.
 	NamespaceSelector *NamespaceSelector `mapstructure:"namespaceSelector"`
+
+	// HonorScrapeAnnotation skips the pods and namespaces annotated with `newrelic.com/scrape: "false"`, along with
+	// the containers and volumes of those pods. Disabled by default, as it requires watching pods through the API
+	// server.
+	HonorScrapeAnnotation bool `mapstructure:"honorScrapeAnnotation"`
+
+	// Labels limits the Kubernetes labels and annotations reported as entity attributes.
+	Labels Labels `mapstructure:"labels"`
+
+	// SpecFiles are YAML files with metric specs and queries, applied in order on top of the builtin ones to add
+	// metrics or override existing ones.
+	SpecFiles []string `mapstructure:"specFiles"`
+
+	// CustomAttributes are added to the entities reported by the integration.
+	CustomAttributes []CustomAttribute `mapstructure:"customAttributes"`
+
+	// Metrics limits the metrics reported for each entity type.
+	Metrics Metrics `mapstructure:"metrics"`
+
+	// Entities drops, reduces or samples entities matching certain attributes.
+	Entities Entities `mapstructure:"entities"`
+
+	// OutputMode is either `entities`, the default, or `dimensional`. Dimensional metrics are written using version 4
+	// of the integrations protocol, so they cannot be sent to StatsD sinks nor routed across sinks.
+	OutputMode string `mapstructure:"outputMode"`
+
+	// PopulateWorkers is the number of entities populated concurrently after each scrape. If zero, one per CPU the
+	// integration can use is started.
+	PopulateWorkers int `mapstructure:"populateWorkers"`
+
+	// PopulateErrors configures how the errors populating entities are logged.
+	PopulateErrors PopulateErrors `mapstructure:"populateErrors"`
+}
+
+// PopulateErrors configures the report of the entities failing to be populated logged after every scrape.
+type PopulateErrors struct {
+	// WarnRatioChange is the change in the ratio of entities failing a metric above which the failure is logged at
+	// Warn level again. Failures are logged at Warn level when they first show up, and at Debug level otherwise.
+	WarnRatioChange float64 `mapstructure:"warnRatioChange"`
+}
+
+// Actions an EntityRule can take on the entities it matches.
+const (
+	// EntityActionDrop drops the entity, which is not reported at all.
+	EntityActionDrop = "drop"
+	// EntityActionKeepMetrics reports only the metrics of the entity matching EntityRule.Metrics.
+	EntityActionKeepMetrics = "keepMetrics"
+	// EntityActionSample reports the entity with a probability of EntityRule.SampleRate. Entities are sampled by ID,
+	// so the same ones are reported on every run.
+	EntityActionSample = "sample"
+)
+
+// Entities holds the rules to drop, reduce or sample entities.
+type Entities struct {
+	// Rules are evaluated in order for every entity, and the first one matching it is applied. Entities matching no
+	// rule are reported as usual.
+	Rules []EntityRule `mapstructure:"rules"`
+}
+
+// EntityRule matches entities by type and attributes, and takes an action on them.
+type EntityRule struct {
+	// EntityTypes the rule applies to.
+	EntityTypes pattern.EntityTypes `mapstructure:"entityTypes"`
+	// Match is a list of conditions on the attributes of the entity, which must all hold for the rule to match.
+	Match []EntityMatcher `mapstructure:"match"`
+	// Action is one of `drop`, `keepMetrics` or `sample`.
+	Action string `mapstructure:"action"`
+	// Metrics is a list of patterns, like the ones of MetricRule, of the metrics reported by the `keepMetrics`
+	// action. Attributes are always reported.
+	Metrics []string `mapstructure:"metrics"`
+	// SampleRate is the fraction of entities, greater than 0 and up to 1, reported by the `sample` action.
+	SampleRate float64 `mapstructure:"sampleRate"`
+}
+
+// EntityMatcher matches the value of an attribute of an entity, like `containerName` or `label.app`, against a glob
+// or a regular expression prefixed with `regex:`. Entities without the attribute never match.
+type EntityMatcher struct {
+	Attribute string `mapstructure:"attribute"`
+	Value     string `mapstructure:"value"`
+}
+
+// Metrics limits the metrics the integration computes and reports.
+type Metrics struct {
+	// Rules include and exclude metrics of certain entity types. A metric is excluded if it matches an exclude
+	// pattern of any rule applying to its entity type, or if some of those rules have include patterns and it
+	// matches none. Excluded metrics are not computed, and the Prometheus series they are read from are not
+	// scraped unless other metrics need them.
+	Rules []MetricRule `mapstructure:"rules"`
+	// Computed are metrics derived from the values of other metrics of the same entity, computed after them.
+	Computed []ComputedMetric `mapstructure:"computed"`
+}
+
+// ComputedMetric is a metric computed with an expression over other metrics of the entities of the given types, like
+// `memoryWorkingSetBytes / memoryRequestedBytes * 100`. Expressions are described in the expression package.
+type ComputedMetric struct {
+	// Name of the metric. It cannot be the name of another metric of the entity types.
+	Name string `mapstructure:"name"`
+	// EntityTypes is a list of entity types, like `pod` or `container`, the metric is computed for. They match the
+	// groups metric specs are defined in, which must define all the metrics the expression reads.
+	EntityTypes []string `mapstructure:"entityTypes"`
+	// Expression computes the metric from the values of other gauge metrics, or gauges computed before this one.
+	// Entities missing any of the values the expression needs, or for which it is not a finite number, like after a
+	// division by zero, are reported without the metric.
+	Expression string `mapstructure:"expression"`
+	// Type is the source type of the metric, like `gauge`, the default, or `rate`.
+	Type string `mapstructure:"type"`
+}
+
+// MetricRule includes and excludes metrics by name, like `containerCpuCfsPeriodsTotal`. Patterns are globs where `*`
+// matches any sequence of characters, or regular expressions when prefixed with `regex:`. Both must match the whole
+// metric name.
+type MetricRule struct {
+	// EntityTypes the rule applies to.
+	EntityTypes pattern.EntityTypes `mapstructure:"entityTypes"`
+	// Include is a list of patterns metrics must match to be reported.
+	Include []string `mapstructure:"include"`
+	// Exclude is a list of patterns of metrics that are never reported.
+	Exclude []string `mapstructure:"exclude"`
+}
+
+// CustomAttribute is an attribute added to every entity, or to the entities of the given types.
+type CustomAttribute struct {
+	// Name of the attribute. It cannot be one of the attributes the integration adds to every entity, like
+	// `clusterName` or `displayName`.
+	Name string `mapstructure:"name"`
+	// Value of the attribute. It is a Go template which can read the labels of the node and namespace of the entity
+	// with `{{ .NodeLabel "topology.kubernetes.io/region" }}` and `{{ .NamespaceLabel "team" }}`. The attribute is not
+	// added to entities for which the value is empty.
+	Value string `mapstructure:"value"`
+	// EntityTypes the attribute is added to.
+	EntityTypes pattern.EntityTypes `mapstructure:"entityTypes"`
+}
+
+// Labels limits the `label.*` and `annotation.*` attributes entities are decorated with.
+type Labels struct {
+	// Rules allow and deny attributes for entities of certain types. An attribute is dropped if it matches a deny
+	// pattern of any rule applying to the entity, or if some of those rules have allow patterns and it matches none.
+	Rules []LabelRule `mapstructure:"rules"`
+	// MaxPerEntity is the maximum number of attributes reported for a single entity. Attributes are kept in
+	// alphabetical order until the limit is reached. If zero, there is no limit.
+	MaxPerEntity int `mapstructure:"maxPerEntity"`
+	// MaxValueLength drops attributes whose value is longer than this number of bytes. If zero, there is no limit.
+	MaxValueLength int `mapstructure:"maxValueLength"`
+}
+
+// LabelRule allows and denies `label.*` and `annotation.*` attributes by name, like `label.pod-template-hash`.
+// Patterns are globs where `*` matches any sequence of characters, including dots and slashes, or regular
+// expressions when prefixed with `regex:`. Both must match the whole attribute name.
+type LabelRule struct {
+	// EntityTypes the rule applies to.
+	EntityTypes pattern.EntityTypes `mapstructure:"entityTypes"`
+	// Allow is a list of patterns attributes must match to be reported.
+	Allow []string `mapstructure:"allow"`
+	// Deny is a list of patterns of attributes that are never reported.
+	Deny []string `mapstructure:"deny"`
+}
+
+// Sink defines where the integration will report the metrics to.
+type Sink struct {
+	// SinkDefinition holds the sink used by the integration when Sinks is empty.
+	SinkDefinition `mapstructure:",squash"`
+	// Sinks is a list of sinks the integration will write every payload to. If not empty, Type and the sink
+	// configuration above are ignored, except for the HTTP sink fields described in Definitions.
+	Sinks []SinkDefinition `mapstructure:"sinks"`
+	// Routes allows sending each entity only to some of the sinks defined in Sinks, based on its type and namespace.
+	// Routes are evaluated in order and the first match wins. If empty, every entity is written to all sinks.
+	Routes []SinkRoute `mapstructure:"routes"`
+	// DefaultRoute is the name of the sink entities not matching any route are written to. If empty, those entities
+	// are dropped.
+	DefaultRoute string `mapstructure:"defaultRoute"`
+}
+
+// SinkRoute sends entities matching its criteria to the sink named Sink. Empty criteria match any entity.
+type SinkRoute struct {
+	// EntityTypes is a list of entity types, such as `pod`, `node` or `cluster`.
+	EntityTypes []string `mapstructure:"entityTypes"`
+	// Namespaces is a list of namespaces, which can include shell patterns like `team-a-*`.
+	Namespaces []string `mapstructure:"namespaces"`
+	// Sink is the name of the sink matching entities are written to.
+	Sink string `mapstructure:"sink"`
+}
+
+// SinkDefinition describes a single sink.
+type SinkDefinition struct {
+	// Name identifies the sink in logs and routes.
+	Name string `mapstructure:"name"`
+	// Type allows selecting which of the supported sinks will be used by the integration.
+	// Supported values are `http`, `stdout`, `file` and `statsd`.
+	Type string `mapstructure:"type"`
+	// HTTP stores the configuration for the HTTP sink.
+	HTTP HTTPSink `mapstructure:"http"`
+	// File stores the configuration for the file sink.
+	File FileSink `mapstructure:"file"`
+	// StatsD stores the configuration for the StatsD sink.
+	StatsD StatsDSink `mapstructure:"statsd"`
+}
+
+// Definitions returns the list of sinks the integration should write to.
+// HTTP sinks defined in Sinks take Host, Port, Path, Timeout, Retries, ProbeTimeout and ProbeBackoff from the top-level HTTP sink
+// configuration when left empty, so defaults and environment overrides apply to them as well.
+func (s Sink) Definitions() []SinkDefinition {
+	if len(s.Sinks) == 0 {
+		return []SinkDefinition{s.SinkDefinition}
+	}
+
+	definitions := make([]SinkDefinition, 0, len(s.Sinks))
+	for _, d := range s.Sinks {
+		if d.Type == SinkTypeHTTP {
+			d.HTTP = d.HTTP.withFallback(s.HTTP)
+		}
+		definitions = append(definitions, d)
+	}
+
+	return definitions
+}
+
+// FileSink stores the configuration for the file sink.
+type FileSink struct {
+	// Path is the file payloads will be appended to. It is created if it does not exist.
+	Path string `mapstructure:"path"`
+}
+
+// StatsDSink stores the configuration for the StatsD sink.
+type StatsDSink struct {
+	// Address is the host:port of the StatsD aggregator. If empty, `localhost:8125` is used.
+	Address string `mapstructure:"address"`
+	// Prefix is prepended to the name of every metric.
+	Prefix string `mapstructure:"prefix"`
+	// TagAllowList is the list of attributes that will be sent as tags. If empty, all attributes are sent.
+	TagAllowList []string `mapstructure:"tagAllowList"`
+	// MaxPacketSize is the maximum size in bytes of each UDP datagram. If zero, 1432 is used.
+	MaxPacketSize int `mapstructure:"maxPacketSize"`
 }
 
 // HTTPSink stores the configuration for the HTTP sink.
 type HTTPSink struct {
+	// Host is the host the HTTP sink will connect to. If empty, the local agent forwarder host is used.
+	Host string `mapstructure:"host"`
 	// Port to be used for the HTTP sink.
 	Port int `mapstructure:"port"`
+	// Path is the path payloads are posted to. If empty, the agent forwarder path is used.
+	Path string `mapstructure:"path"`
+	// Scheme is the scheme used to connect to the HTTP sink. If empty, `https` is used when TLS is enabled and `http`
+	// otherwise.
+	Scheme string `mapstructure:"scheme"`
+	// Headers are added to every request made to the HTTP sink, including readiness probes.
+	Headers map[string]string `mapstructure:"headers"`
+	// Auth allows to configure authentication against the HTTP sink.
+	Auth HTTPSinkAuth `mapstructure:"auth"`
 	// Timeout is the amount of time to wait before giving up the connection to the HTTP sink.
 	Timeout time.Duration `mapstructure:"timeout"`
 	// Retries is the maximum number of attempts to connect to the HTTP sink if the connection fails before giving up
@@ -80,6 +336,52 @@ type HTTPSink struct {
 	ProbeTimeout time.Duration `mapstructure:"probeTimeout"`
 	// ProbeBackoff is the amount of time the main func to backoff when it fails to probe infra agent sidecar.
 	ProbeBackoff time.Duration `mapstructure:"probeBackoff"`
+	// MaxEntitiesPerRequest splits each payload in several requests holding at most this number of entities.
+	// If zero, payloads are not split by entity count.
+	MaxEntitiesPerRequest int `mapstructure:"maxEntitiesPerRequest"`
+	// MaxBytesPerRequest splits each payload in several requests of at most this size in bytes. Entities are never
+	// split across requests. If zero, payloads are not split by size.
+	MaxBytesPerRequest int `mapstructure:"maxBytesPerRequest"`
+	// Gzip enables gzip compression of the payloads sent to the HTTP sink.
+	Gzip bool `mapstructure:"gzip"`
+}
+
+// HTTPSinkAuth holds the credentials used to authenticate against the HTTP sink.
+// BearerToken and basic auth are mutually exclusive.
+type HTTPSinkAuth struct {
+	// BearerToken is sent in the Authorization header of every request.
+	BearerToken string `mapstructure:"bearerToken"`
+	// Username is the user for basic authentication.
+	Username string `mapstructure:"username"`
+	// Password is the password for basic authentication.
+	Password string `mapstructure:"password"`
+}
+
+// withFallback returns a copy of h where empty connection settings are taken from fallback.
+func (h HTTPSink) withFallback(fallback HTTPSink) HTTPSink {
+	if h.Host == "" {
+		h.Host = fallback.Host
+	}
+	if h.Path == "" {
+		h.Path = fallback.Path
+	}
+	if h.Port == 0 {
+		h.Port = fallback.Port
+	}
+	if h.Timeout == 0 {
+		h.Timeout = fallback.Timeout
+	}
+	if h.Retries == 0 {
+		h.Retries = fallback.Retries
+	}
+	if h.ProbeTimeout == 0 {
+		h.ProbeTimeout = fallback.ProbeTimeout
+	}
+	if h.ProbeBackoff == 0 {
+		h.ProbeBackoff = fallback.ProbeBackoff
+	}
+
+	return h
 }
 
 type TLSConfig struct {
@@ -286,6 +588,20 @@ func (e *Expression) String() (string, error) {
 	return fmt.Sprintf("%s %s (%s)", e.Key, strings.ToLower(e.Operator), strings.Join(values, ",")), nil
 }
 
+// Dimensional returns whether metrics are reported as dimensional metrics.
+func (c *Config) Dimensional() bool {
+	return c.OutputMode == OutputModeDimensional
+}
+
+// CycleTimeout returns the maximum time a metric collection run can take, or zero if runs have no deadline.
+func (c *Config) CycleTimeout() time.Duration {
+	if c.ScrapeTimeout > 0 {
+		return c.ScrapeTimeout
+	}
+
+	return 0
+}
+
 func LoadConfig(filePath string, fileName string) (*Config, error) {
 	// Update default delimiter as with the new namespaceSelector config, some labels may come in the form of
 	// newrelic.com/scrape, so the key was split in a sub-map on a "." basis.
@@ -299,14 +615,27 @@ func LoadConfig(filePath string, fileName string) (*Config, error) {
 	v.SetDefault("nodeName", "node")
 	v.SetDefault("nodeIP", "node")
 	v.SetDefault("testConnectionEndpoint", "/healthz")
+	v.SetDefault("scrapeTimeout", 0)
+	v.SetDefault("populateWorkers", 0)
+	v.SetDefault("honorScrapeAnnotation", false)
+	v.SetDefault("populateErrors|warnRatioChange", DefaultPopulateErrorsWarnRatioChange)
 
 	// Sane connection defaults
 	v.SetDefault("sink|type", SinkTypeHTTP)
+	v.SetDefault("sink|http|host", "")
 	v.SetDefault("sink|http|port", 0)
+	v.SetDefault("sink|http|path", "")
+	v.SetDefault("sink|http|scheme", "")
+	v.SetDefault("sink|http|auth|bearerToken", "")
+	v.SetDefault("sink|http|auth|username", "")
+	v.SetDefault("sink|http|auth|password", "")
 	v.SetDefault("sink|http|timeout", DefaultAgentTimeout)
 	v.SetDefault("sink|http|retries", DefaultRetries)
 	v.SetDefault("sink|http|probeTimeout", DefaultProbeTimeout)
 	v.SetDefault("sink|http|probeBackoff", DefaultProbeBackoff)
+	v.SetDefault("sink|http|maxEntitiesPerRequest", 0)
+	v.SetDefault("sink|http|maxBytesPerRequest", 0)
+	v.SetDefault("sink|http|gzip", false)
 
 	v.SetDefault("kubelet|timeout", DefaultTimeout)
 	v.SetDefault("kubelet|retries", DefaultRetries)
@@ -347,14 +676,129 @@ func LoadConfig(filePath string, fileName string) (*Config, error) {
 		return &cfg, err
 	}
 
+	if err := checkSinkRoutesConfig(cfg); err != nil {
+		return &cfg, err
+	}
+
+	if err := checkCustomAttributesConfig(cfg); err != nil {
+		return &cfg, err
+	}
+
+	if err := checkEntitiesConfig(cfg); err != nil {
+		return &cfg, err
+	}
+
+	if err := checkOutputModeConfig(cfg); err != nil {
+		return &cfg, err
+	}
+
 	return &cfg, nil
 }
 
 var (
 	ErrInvalidMatchExpressionsValue = errors.New("invalid matchExpressions value")
 	ErrInvalidMatchLabelsValue      = errors.New("invalid matchLabels value")
+	ErrDuplicatedSinkName           = errors.New("duplicated sink name")
+	ErrUnknownRouteSink             = errors.New("route references an unknown sink")
+	ErrInvalidCustomAttributeName   = errors.New("invalid custom attribute name")
+	ErrInvalidEntityRule            = errors.New("invalid entity rule")
+	ErrInvalidOutputMode            = errors.New("invalid output mode")
 )
 
+// reservedAttributes are the attributes the integration adds to every entity, which cannot be overridden by custom
+// attributes.
+var reservedAttributes = map[string]bool{
+	"clusterName": true,
+	"displayName": true,
+	"entityName":  true,
+	"event_type":  true,
+	"nrFiltered":  true,
+}
+
+func checkCustomAttributesConfig(c Config) error {
+	for _, attr := range c.CustomAttributes {
+		if attr.Name == "" || reservedAttributes[attr.Name] {
+			return fmt.Errorf("%w: %q", ErrInvalidCustomAttributeName, attr.Name)
+		}
+	}
+
+	return nil
+}
+
+func checkEntitiesConfig(c Config) error {
+	for i, rule := range c.Entities.Rules {
+		for _, m := range rule.Match {
+			if m.Attribute == "" {
+				return fmt.Errorf("%w #%d: matcher without attribute", ErrInvalidEntityRule, i)
+			}
+		}
+
+		switch rule.Action {
+		case EntityActionDrop:
+		case EntityActionKeepMetrics:
+			if len(rule.Metrics) == 0 {
+				return fmt.Errorf("%w #%d: %q requires metrics", ErrInvalidEntityRule, i, rule.Action)
+			}
+		case EntityActionSample:
+			if rule.SampleRate <= 0 || rule.SampleRate > 1 {
+				return fmt.Errorf("%w #%d: sample rate %v is not in (0, 1]", ErrInvalidEntityRule, i, rule.SampleRate)
+			}
+		default:
+			return fmt.Errorf("%w #%d: unknown action %q", ErrInvalidEntityRule, i, rule.Action)
+		}
+	}
+
+	return nil
+}
+
+func checkOutputModeConfig(c Config) error {
+	switch c.OutputMode {
+	case "", OutputModeEntities:
+		return nil
+	case OutputModeDimensional:
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidOutputMode, c.OutputMode)
+	}
+
+	if len(c.Sink.Routes) > 0 || c.Sink.DefaultRoute != "" {
+		return fmt.Errorf("%w: %q does not support sink routes", ErrInvalidOutputMode, c.OutputMode)
+	}
+
+	for _, sink := range c.Sink.Definitions() {
+		if sink.Type == SinkTypeStatsD {
+			return fmt.Errorf("%w: %q does not support %s sinks", ErrInvalidOutputMode, c.OutputMode, sink.Type)
+		}
+	}
+
+	return nil
+}
+
+func checkSinkRoutesConfig(c Config) error {
+	names := map[string]bool{}
+	for _, sink := range c.Sink.Sinks {
+		if sink.Name == "" {
+			continue
+		}
+
+		if names[sink.Name] {
+			return fmt.Errorf("%w: %q", ErrDuplicatedSinkName, sink.Name)
+		}
+		names[sink.Name] = true
+	}
+
+	for _, route := range c.Sink.Routes {
+		if !names[route.Sink] {
+			return fmt.Errorf("%w: %q", ErrUnknownRouteSink, route.Sink)
+		}
+	}
+
+	if c.Sink.DefaultRoute != "" && !names[c.Sink.DefaultRoute] {
+		return fmt.Errorf("%w: %q", ErrUnknownRouteSink, c.Sink.DefaultRoute)
+	}
+
+	return nil
+}
+
 func checkNamespaceSelectorConfig(c Config) error {
 	if c.NamespaceSelector == nil {
 		return nil
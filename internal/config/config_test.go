@@ -3,6 +3,7 @@ package config_test
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -17,6 +18,19 @@ const workingDataWithNamespaceFilters = "config_with_namespace_filter"
 const wrongDataWithNamespaceFilterMatchLabels = "config_with_namespace_filter_wrong_match_labels"
 const wrongDataWithNamespaceFiltersMatchExpressions = "config_with_namespace_filter_wrong_match_expressions"
 const unexpectedFields = "config_with_unexpected_fields"
+const multipleSinks = "config_with_multiple_sinks"
+const sinkRoutes = "config_with_sink_routes"
+const unknownSinkRoute = "config_with_unknown_sink_route"
+const labelLimits = "config_with_label_limits"
+const specFiles = "config_with_spec_files"
+const customAttributes = "config_with_custom_attributes"
+const reservedCustomAttribute = "config_with_reserved_custom_attribute"
+const metricRules = "config_with_metric_rules"
+const computedMetrics = "config_with_computed_metrics"
+const entityRules = "config_with_entity_rules"
+const invalidEntityRule = "config_with_invalid_entity_rule"
+const dimensionalOutput = "config_with_dimensional_output"
+const dimensionalOutputStatsD = "config_with_dimensional_output_statsd"
 
 func TestLoadConfig(t *testing.T) {
 
@@ -137,3 +151,263 @@ func TestDeduplicateAzureVolumes(t *testing.T) {
 		require.True(t, cfg.DeduplicateAzureVolumes, "deduplicateAzureVolumes should be true from env variable")
 	})
 }
+
+func TestCycleTimeout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults_to_no_deadline", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := config.LoadConfig(fakeDataDir, workingData)
+		require.NoError(t, err)
+		require.Zero(t, cfg.CycleTimeout())
+	})
+
+	t.Run("uses_scrape_timeout_when_set", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := config.Config{Interval: 15 * time.Second, ScrapeTimeout: 10 * time.Second}
+		require.Equal(t, 10*time.Second, cfg.CycleTimeout())
+	})
+}
+
+func TestSinkDefinitions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults_to_single_sink", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := config.LoadConfig(fakeDataDir, workingData)
+		require.NoError(t, err)
+
+		definitions := cfg.Sink.Definitions()
+		require.Len(t, definitions, 1)
+		require.Equal(t, config.SinkTypeHTTP, definitions[0].Type)
+		require.Equal(t, 8081, definitions[0].HTTP.Port)
+	})
+
+	t.Run("loads_multiple_sinks", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := config.LoadConfig(fakeDataDir, multipleSinks)
+		require.NoError(t, err)
+
+		definitions := cfg.Sink.Definitions()
+		require.Len(t, definitions, 3)
+
+		require.Equal(t, config.SinkTypeHTTP, definitions[0].Type)
+		require.Equal(t, 8081, definitions[0].HTTP.Port)
+		require.Equal(t, 5, definitions[0].HTTP.Retries)
+		require.Equal(t, 20*time.Second, definitions[0].HTTP.Timeout)
+		require.Equal(t, config.DefaultProbeTimeout, definitions[0].HTTP.ProbeTimeout)
+
+		require.Equal(t, config.SinkTypeFile, definitions[1].Type)
+		require.Equal(t, "/tmp/nri-kubernetes.json", definitions[1].File.Path)
+
+		require.Equal(t, config.SinkTypeStdout, definitions[2].Type)
+	})
+}
+
+func TestSinkRoutes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("loads_routes", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := config.LoadConfig(fakeDataDir, sinkRoutes)
+		require.NoError(t, err)
+
+		require.Equal(t, "team-a", cfg.Sink.Definitions()[0].Name)
+		require.Equal(t, "team-a-forwarder", cfg.Sink.Definitions()[0].HTTP.Host)
+		require.Equal(t, []config.SinkRoute{
+			{Namespaces: []string{"team-a-*"}, Sink: "team-a"},
+			{EntityTypes: []string{"cluster", "node"}, Sink: "platform"},
+		}, cfg.Sink.Routes)
+		require.Equal(t, "platform", cfg.Sink.DefaultRoute)
+	})
+
+	t.Run("fails_when_route_references_unknown_sink", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := config.LoadConfig(fakeDataDir, unknownSinkRoute)
+		require.ErrorIs(t, err, config.ErrUnknownRouteSink)
+	})
+}
+
+func TestLabels(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.LoadConfig(fakeDataDir, labelLimits)
+	require.NoError(t, err)
+
+	require.Equal(t, config.Labels{
+		MaxPerEntity:   30,
+		MaxValueLength: 128,
+		Rules: []config.LabelRule{
+			{Deny: []string{"label.pod-template-hash", "label.controller-revision-hash"}},
+			{EntityTypes: []string{"namespace"}, Allow: []string{"label.team", `regex:annotation\.example\.com/.+`}},
+		},
+	}, cfg.Labels)
+}
+
+func TestSpecFiles(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.LoadConfig(fakeDataDir, specFiles)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"/etc/newrelic-infra/specs/ksm.yml", "/etc/newrelic-infra/specs/etcd.yml"}, cfg.SpecFiles)
+}
+
+func TestCustomAttributes(t *testing.T) {
+	t.Parallel()
+
+	t.Run("loads_attributes", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := config.LoadConfig(fakeDataDir, customAttributes)
+		require.NoError(t, err)
+
+		require.Equal(t, []config.CustomAttribute{
+			{Name: "environment", Value: "production"},
+			{Name: "region", Value: `{{ .NodeLabel "topology.kubernetes.io/region" }}`},
+			{Name: "team", Value: `{{ .NamespaceLabel "team" }}`, EntityTypes: []string{"namespace", "pod"}},
+		}, cfg.CustomAttributes)
+	})
+
+	t.Run("fails_when_attribute_is_reserved", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := config.LoadConfig(fakeDataDir, reservedCustomAttribute)
+		require.ErrorIs(t, err, config.ErrInvalidCustomAttributeName)
+	})
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.LoadConfig(fakeDataDir, metricRules)
+	require.NoError(t, err)
+
+	require.Equal(t, config.Metrics{
+		Rules: []config.MetricRule{
+			{Exclude: []string{"metadataResourceVersion"}},
+			{EntityTypes: []string{"container"}, Exclude: []string{"containerCpuCfs*Total"}},
+			{EntityTypes: []string{"node"}, Include: []string{"regex:(cpu|memory|fs).*"}},
+		},
+	}, cfg.Metrics)
+}
+
+func TestComputedMetrics(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.LoadConfig(fakeDataDir, computedMetrics)
+	require.NoError(t, err)
+
+	require.Equal(t, []config.ComputedMetric{
+		{
+			Name:        "memoryWorkingSetRequestRatio",
+			EntityTypes: []string{"container"},
+			Expression:  "memoryWorkingSetBytes / memoryRequestedBytes * 100",
+		},
+		{
+			Name:        "replicasUnavailableRatio",
+			EntityTypes: []string{"deployment", "daemonset"},
+			Expression:  "if(podsDesired > 0, podsUnavailable / podsDesired, 0)",
+			Type:        "gauge",
+		},
+	}, cfg.Metrics.Computed)
+}
+
+func TestEntities(t *testing.T) {
+	t.Parallel()
+
+	t.Run("loads_rules", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := config.LoadConfig(fakeDataDir, entityRules)
+		require.NoError(t, err)
+
+		require.Equal(t, []config.EntityRule{
+			{
+				EntityTypes: []string{"pod"},
+				Match:       []config.EntityMatcher{{Attribute: "label.ci", Value: "true"}},
+				Action:      config.EntityActionDrop,
+			},
+			{
+				EntityTypes: []string{"container"},
+				Match:       []config.EntityMatcher{{Attribute: "containerName", Value: "istio-proxy"}},
+				Action:      config.EntityActionKeepMetrics,
+				Metrics:     []string{"cpu*", "memory*"},
+			},
+			{
+				Match:      []config.EntityMatcher{{Attribute: "namespace", Value: "regex:batch-.+"}},
+				Action:     config.EntityActionSample,
+				SampleRate: 0.25,
+			},
+		}, cfg.Entities.Rules)
+	})
+
+	t.Run("fails_when_rule_is_invalid", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := config.LoadConfig(fakeDataDir, invalidEntityRule)
+		require.ErrorIs(t, err, config.ErrInvalidEntityRule)
+	})
+}
+
+func TestOutputMode(t *testing.T) {
+	t.Parallel()
+
+	t.Run("defaults_to_entities", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := config.LoadConfig(fakeDataDir, workingData)
+		require.NoError(t, err)
+		require.False(t, cfg.Dimensional())
+	})
+
+	t.Run("loads_dimensional_mode", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := config.LoadConfig(fakeDataDir, dimensionalOutput)
+		require.NoError(t, err)
+		require.True(t, cfg.Dimensional())
+	})
+
+	t.Run("fails_with_statsd_sinks", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := config.LoadConfig(fakeDataDir, dimensionalOutputStatsD)
+		require.ErrorIs(t, err, config.ErrInvalidOutputMode)
+	})
+}
+
+func TestPopulateErrors(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := config.LoadConfig(fakeDataDir, workingData)
+	require.NoError(t, err)
+
+	require.Equal(t, config.DefaultPopulateErrorsWarnRatioChange, cfg.PopulateErrors.WarnRatioChange)
+}
+
+func TestHonorScrapeAnnotation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("disabled_by_default", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := config.LoadConfig(fakeDataDir, workingData)
+		require.NoError(t, err)
+		require.False(t, cfg.HonorScrapeAnnotation)
+	})
+
+	t.Run("enabled_from_config_file", func(t *testing.T) {
+		t.Parallel()
+
+		cfg, err := config.LoadConfig(fakeDataDir, workingDataWithNamespaceFilters)
+		require.NoError(t, err)
+		require.True(t, cfg.HonorScrapeAnnotation)
+	})
+}
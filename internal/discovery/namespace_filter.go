@@ -110,6 +110,17 @@ func (nf *NamespaceFilter) matchNamespaceByExpressions(namespace string) bool {
 	return true
 }
 
+// OptedOut returns whether the namespace opted out of being scraped by setting the ScrapeAnnotation to "false".
+// Namespaces not found in the informer are not considered opted out.
+func (nf *NamespaceFilter) OptedOut(namespace string) bool {
+	ns, err := nf.lister.Get(namespace)
+	if err != nil {
+		return false
+	}
+
+	return optedOut(ns.Annotations)
+}
+
 func (nf *NamespaceFilter) parseToStringMap(matchLabels map[string]interface{}) map[string]string {
 	strMap := make(map[string]string)
 
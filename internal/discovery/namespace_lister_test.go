@@ -0,0 +1,35 @@
+package discovery_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/discovery"
+)
+
+func TestNamespaceLister(t *testing.T) {
+	t.Parallel()
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "team-a",
+			Labels: map[string]string{"team": "a"},
+		},
+	}
+
+	client := testclient.NewSimpleClientset(namespace)
+	lister, closeChan := discovery.NewNamespaceLister(client)
+	defer close(closeChan)
+
+	ns, err := lister.Get("team-a")
+	require.NoError(t, err)
+	assert.Equal(t, namespace, ns)
+
+	_, err = lister.Get("missing")
+	require.Error(t, err)
+}
@@ -0,0 +1,127 @@
+package discovery
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+)
+
+// ScrapeAnnotation is the annotation pods and namespaces set to "false" to opt out of being scraped.
+const ScrapeAnnotation = "newrelic.com/scrape"
+
+// OptOutFilter tells whether namespaces and pods opted out of being scraped with the ScrapeAnnotation, and counts
+// the entities skipped because of it. Namespaces are read from the informer of a NamespaceFilter, and pods from an
+// informer keeping only their metadata.
+type OptOutFilter struct {
+	namespaces *NamespaceFilter
+	pods       listersv1.PodLister
+	stopCh     chan<- struct{}
+	skipped    atomic.Int64
+}
+
+// NewOptOutFilter starts the pod informer and returns a new OptOutFilter reading namespaces from the ones of
+// namespaces. Options, like informers.WithTweakListOptions, limit the pods the informer watches, which are never
+// considered opted out.
+func NewOptOutFilter(namespaces *NamespaceFilter, client kubernetes.Interface, options ...informers.SharedInformerOption) *OptOutFilter {
+	stopCh := make(chan struct{})
+
+	factory := informers.NewSharedInformerFactoryWithOptions(client, defaultResyncDuration, options...)
+
+	podInformer := factory.Core().V1().Pods()
+	// Only the annotations of pods are read, so the rest of them is not kept in memory.
+	_ = podInformer.Informer().SetTransform(podMetadata)
+	lister := podInformer.Lister()
+
+	factory.Start(stopCh)
+	factory.WaitForCacheSync(stopCh)
+
+	return &OptOutFilter{
+		namespaces: namespaces,
+		pods:       lister,
+		stopCh:     stopCh,
+	}
+}
+
+// Skip returns whether an entity of the namespace, or of the pod with the given name in it if pod is not empty,
+// must be skipped because any of them opted out of being scraped, counting the entity as skipped if so. It is safe to
+// call on a nil OptOutFilter, which skips nothing.
+func (f *OptOutFilter) Skip(namespace, pod string) bool {
+	if f == nil || namespace == "" {
+		return false
+	}
+
+	if !f.namespaces.OptedOut(namespace) && !f.podOptedOut(namespace, pod) {
+		return false
+	}
+
+	f.skipped.Add(1)
+
+	return true
+}
+
+// TakeSkipped returns the number of entities skipped since the last call. It is safe to call on a nil OptOutFilter.
+func (f *OptOutFilter) TakeSkipped() int64 {
+	if f == nil {
+		return 0
+	}
+
+	return f.skipped.Swap(0)
+}
+
+// Close stops the pod informer. It is safe to call on a nil OptOutFilter.
+func (f *OptOutFilter) Close() error {
+	if f == nil {
+		return nil
+	}
+
+	if f.stopCh == nil {
+		return errors.New("invalid channel")
+	}
+
+	close(f.stopCh)
+
+	return nil
+}
+
+func (f *OptOutFilter) podOptedOut(namespace, pod string) bool {
+	if pod == "" {
+		return false
+	}
+
+	p, err := f.pods.Pods(namespace).Get(pod)
+	if err != nil {
+		return false
+	}
+
+	return optedOut(p.Annotations)
+}
+
+// optedOut returns whether the annotations of an object opt it out of being scraped.
+func optedOut(annotations map[string]string) bool {
+	return strings.EqualFold(annotations[ScrapeAnnotation], "false")
+}
+
+// podMetadata is the transform of the pod informer, which drops everything but the metadata identifying pods and their
+// annotations.
+func podMetadata(obj any) (any, error) {
+	pod, ok := obj.(*v1.Pod)
+	if !ok {
+		return obj, nil
+	}
+
+	return &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            pod.Name,
+			Namespace:       pod.Namespace,
+			UID:             pod.UID,
+			ResourceVersion: pod.ResourceVersion,
+			Annotations:     pod.Annotations,
+		},
+	}, nil
+}
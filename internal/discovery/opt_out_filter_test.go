@@ -0,0 +1,97 @@
+package discovery_test
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	testclient "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/discovery"
+)
+
+func optOutObjects() []runtime.Object {
+	optedOut := map[string]string{discovery.ScrapeAnnotation: "false"}
+
+	return []runtime.Object{
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "scraped"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "noisy", Annotations: optedOut}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "explicit",
+			Annotations: map[string]string{discovery.ScrapeAnnotation: "true"},
+		}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "scraped"},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "batch", Namespace: "scraped", Annotations: optedOut},
+		},
+	}
+}
+
+func TestOptOutFilter_Skip(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		namespace string
+		pod       string
+		expected  bool
+	}{
+		"namespace_not_annotated":     {namespace: "scraped", expected: false},
+		"namespace_annotated_true":    {namespace: "explicit", expected: false},
+		"namespace_opted_out":         {namespace: "noisy", expected: true},
+		"unknown_namespace":           {namespace: "unknown", expected: false},
+		"empty_namespace":             {expected: false},
+		"pod_not_annotated":           {namespace: "scraped", pod: "web", expected: false},
+		"pod_opted_out":               {namespace: "scraped", pod: "batch", expected: true},
+		"unknown_pod_in_opted_out_ns": {namespace: "noisy", pod: "unknown", expected: true},
+		"pod_in_different_namespace":  {namespace: "explicit", pod: "batch", expected: false},
+		"unknown_pod_in_scraped_ns":   {namespace: "scraped", pod: "unknown", expected: false},
+	}
+
+	client := testclient.NewSimpleClientset(optOutObjects()...)
+	nsFilter := discovery.NewNamespaceFilter(nil, client, logrus.New())
+	t.Cleanup(func() { _ = nsFilter.Close() })
+
+	filter := discovery.NewOptOutFilter(nsFilter, client)
+	t.Cleanup(func() { _ = filter.Close() })
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, filter.Skip(tc.namespace, tc.pod))
+		})
+	}
+}
+
+func TestOptOutFilter_TakeSkipped(t *testing.T) {
+	t.Parallel()
+
+	client := testclient.NewSimpleClientset(optOutObjects()...)
+	nsFilter := discovery.NewNamespaceFilter(nil, client, logrus.New())
+	t.Cleanup(func() { _ = nsFilter.Close() })
+
+	filter := discovery.NewOptOutFilter(nsFilter, client)
+	t.Cleanup(func() { _ = filter.Close() })
+
+	require.True(t, filter.Skip("noisy", ""))
+	require.True(t, filter.Skip("scraped", "batch"))
+	require.False(t, filter.Skip("scraped", "web"))
+
+	assert.Equal(t, int64(2), filter.TakeSkipped())
+	assert.Zero(t, filter.TakeSkipped(), "Counter should be reset after being taken")
+}
+
+func TestOptOutFilter_Nil(t *testing.T) {
+	t.Parallel()
+
+	var filter *discovery.OptOutFilter
+
+	assert.False(t, filter.Skip("noisy", "batch"))
+	assert.Zero(t, filter.TakeSkipped())
+	assert.NoError(t, filter.Close())
+}
@@ -0,0 +1,142 @@
+// Package entities drops, reduces or samples the entities reported by the integration, based on their attributes, for
+// those not worth the cost of ingesting them in full, like the pods of CI jobs or sidecar containers.
+package entities
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"regexp"
+
+	"github.com/newrelic/infra-integrations-sdk/data/metric"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/config"
+	"github.com/newrelic/nri-kubernetes/v3/internal/pattern"
+)
+
+type matcher struct {
+	attribute string
+	value     *regexp.Regexp
+}
+
+type rule struct {
+	entityTypes pattern.EntityTypes
+	matchers    []matcher
+	action      string
+	metrics     []*regexp.Regexp
+	// threshold is the hash below which entities are kept by the sample action.
+	threshold uint64
+}
+
+func (r rule) matches(attrs map[string]string) bool {
+	for _, m := range r.matchers {
+		value, ok := attrs[m.attribute]
+		if !ok || !m.value.MatchString(value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Filter decides what is reported of each entity, as configured by config.Entities. A Filter is safe for concurrent
+// use.
+type Filter struct {
+	rules []rule
+}
+
+// NewFilter compiles the rules in c into a Filter.
+func NewFilter(c config.Entities) (*Filter, error) {
+	f := &Filter{}
+
+	for i, r := range c.Rules {
+		compiled := rule{
+			entityTypes: r.EntityTypes,
+			action:      r.Action,
+			threshold:   uint64(math.Min(r.SampleRate, 1) * (math.MaxUint32 + 1)),
+		}
+
+		for _, m := range r.Match {
+			value, err := pattern.Compile([]string{m.Value})
+			if err != nil {
+				return nil, fmt.Errorf("compiling matcher of %q in entity rule #%d: %w", m.Attribute, i, err)
+			}
+			compiled.matchers = append(compiled.matchers, matcher{attribute: m.Attribute, value: value[0]})
+		}
+
+		metrics, err := pattern.Compile(r.Metrics)
+		if err != nil {
+			return nil, fmt.Errorf("compiling metric patterns of entity rule #%d: %w", i, err)
+		}
+		compiled.metrics = metrics
+
+		f.rules = append(f.rules, compiled)
+	}
+
+	return f, nil
+}
+
+// AppliesTo returns whether any rule applies to entities of the given type, so callers can skip computing the
+// attributes of the ones no rule can match. It is safe to call on a nil Filter.
+func (f *Filter) AppliesTo(entityType string) bool {
+	if f == nil {
+		return false
+	}
+
+	for _, r := range f.rules {
+		if r.entityTypes.Matches(entityType) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Decision is what is reported of an entity. The zero Decision reports the whole entity.
+type Decision struct {
+	drop    bool
+	metrics []*regexp.Regexp
+}
+
+// Drop returns whether the entity must not be reported.
+func (d Decision) Drop() bool {
+	return d.drop
+}
+
+// Reports returns whether the metric of the entity, named as its spec and of the given source type, is reported.
+// Attributes are always reported, as they identify the entity and tell what the rest of its metrics are about.
+func (d Decision) Reports(metricName string, sourceType metric.SourceType) bool {
+	return d.metrics == nil || sourceType == metric.ATTRIBUTE || pattern.MatchesAny(d.metrics, metricName)
+}
+
+// Decide applies the first rule matching the entity of the given type, ID and attributes. It is safe to call on a nil
+// Filter, in which case the whole entity is reported.
+func (f *Filter) Decide(entityType, entityID string, attrs map[string]string) Decision {
+	if f == nil {
+		return Decision{}
+	}
+
+	for _, r := range f.rules {
+		if !r.entityTypes.Matches(entityType) || !r.matches(attrs) {
+			continue
+		}
+
+		switch r.action {
+		case config.EntityActionDrop:
+			return Decision{drop: true}
+		case config.EntityActionKeepMetrics:
+			return Decision{metrics: r.metrics}
+		case config.EntityActionSample:
+			return Decision{drop: uint64(hash(entityID)) >= r.threshold}
+		}
+	}
+
+	return Decision{}
+}
+
+func hash(entityID string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(entityID))
+
+	return h.Sum32()
+}
@@ -0,0 +1,147 @@
+package entities_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/newrelic/infra-integrations-sdk/data/metric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/config"
+	"github.com/newrelic/nri-kubernetes/v3/internal/entities"
+	"github.com/newrelic/nri-kubernetes/v3/internal/pattern"
+)
+
+func TestFilter_Decide(t *testing.T) {
+	t.Parallel()
+
+	filter, err := entities.NewFilter(config.Entities{Rules: []config.EntityRule{
+		{
+			EntityTypes: []string{"pod"},
+			Match:       []config.EntityMatcher{{Attribute: "label.ci", Value: "true"}, {Attribute: "createdKind", Value: "Job"}},
+			Action:      config.EntityActionDrop,
+		},
+		{
+			EntityTypes: []string{"container"},
+			Match:       []config.EntityMatcher{{Attribute: "containerName", Value: "regex:istio-(proxy|init)"}},
+			Action:      config.EntityActionKeepMetrics,
+			Metrics:     []string{"cpu*"},
+		},
+		{
+			Action: config.EntityActionDrop,
+			Match:  []config.EntityMatcher{{Attribute: "containerName", Value: "*"}},
+		},
+	}})
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		entityType string
+		attrs      map[string]string
+		drop       bool
+		reported   []string
+		skipped    []string
+	}{
+		"drops_entities_matching_every_condition": {
+			entityType: "pod",
+			attrs:      map[string]string{"label.ci": "true", "createdKind": "Job"},
+			drop:       true,
+		},
+		"keeps_entities_matching_some_conditions": {
+			entityType: "pod",
+			attrs:      map[string]string{"label.ci": "true", "createdKind": "ReplicaSet"},
+			reported:   []string{"cpuUsedCores", "memoryUsedBytes"},
+		},
+		"keeps_entities_without_the_attribute": {
+			entityType: "pod",
+			attrs:      map[string]string{"createdKind": "Job"},
+			reported:   []string{"cpuUsedCores"},
+		},
+		"keeps_only_some_metrics": {
+			entityType: "container",
+			attrs:      map[string]string{"containerName": "istio-proxy"},
+			reported:   []string{"cpuUsedCores"},
+			skipped:    []string{"memoryUsedBytes"},
+		},
+		"applies_first_matching_rule": {
+			entityType: "container",
+			attrs:      map[string]string{"containerName": "app"},
+			drop:       true,
+		},
+		"ignores_rules_of_other_entity_types": {
+			entityType: "node",
+			attrs:      map[string]string{"label.ci": "true", "createdKind": "Job"},
+			reported:   []string{"cpuUsedCores"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			decision := filter.Decide(tc.entityType, "id", tc.attrs)
+			assert.Equal(t, tc.drop, decision.Drop())
+			for _, m := range tc.reported {
+				assert.True(t, decision.Reports(m, metric.GAUGE), m)
+			}
+			for _, m := range tc.skipped {
+				assert.False(t, decision.Reports(m, metric.GAUGE), m)
+				assert.True(t, decision.Reports(m, metric.ATTRIBUTE), "attributes are always reported")
+			}
+		})
+	}
+}
+
+func TestFilter_Sample(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		rate     float64
+		min, max int
+	}{
+		"samples_a_fraction_of_entities": {rate: 0.25, min: 200, max: 300},
+		"keeps_every_entity":             {rate: 1, min: 1000, max: 1000},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			filter, err := entities.NewFilter(config.Entities{Rules: []config.EntityRule{
+				{Action: config.EntityActionSample, SampleRate: tc.rate},
+			}})
+			require.NoError(t, err)
+
+			kept := 0
+			for i := range 1000 {
+				id := fmt.Sprintf("default_pod-%d", i)
+				decision := filter.Decide("pod", id, nil)
+				assert.Equal(t, decision, filter.Decide("pod", id, nil), "sampling must be stable")
+				if !decision.Drop() {
+					kept++
+				}
+			}
+
+			assert.GreaterOrEqual(t, kept, tc.min)
+			assert.LessOrEqual(t, kept, tc.max)
+		})
+	}
+}
+
+func TestFilter_Nil(t *testing.T) {
+	t.Parallel()
+
+	var filter *entities.Filter
+	assert.False(t, filter.AppliesTo("pod"))
+	assert.False(t, filter.Decide("pod", "id", nil).Drop())
+	assert.True(t, filter.Decide("pod", "id", nil).Reports("cpuUsedCores", metric.GAUGE))
+}
+
+func TestNewFilter_InvalidPattern(t *testing.T) {
+	t.Parallel()
+
+	_, err := entities.NewFilter(config.Entities{Rules: []config.EntityRule{
+		{Action: config.EntityActionDrop, Match: []config.EntityMatcher{{Attribute: "namespace", Value: "regex:ci-("}}},
+	}})
+	assert.ErrorIs(t, err, pattern.ErrInvalidPattern)
+}
@@ -0,0 +1,126 @@
+// Package expression implements the small language computed metrics are defined with, which combines the values of
+// other metrics of an entity, like `memoryWorkingSetBytes / memoryRequestedBytes * 100`.
+//
+// Expressions are made of numbers, metric names, the arithmetic operators `+`, `-`, `*`, `/` and `%`, the comparison
+// operators `==`, `!=`, `<`, `<=`, `>` and `>=`, the logical operators `&&`, `||` and `!`, parentheses, and the
+// functions below:
+//
+//	abs(x)           absolute value of x.
+//	min(x, y, ...)   smallest of its arguments.
+//	max(x, y, ...)   largest of its arguments.
+//	if(c, x, y)      x if the condition c holds, y otherwise. Only the chosen branch is evaluated.
+//
+// Comparisons and logical operators yield booleans, which can only be used as conditions. Expressions must yield a
+// number.
+package expression
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+var (
+	// ErrSyntax is returned when an expression cannot be parsed.
+	ErrSyntax = errors.New("syntax error")
+	// ErrType is returned when an expression references unknown metrics or functions, or combines values of the
+	// wrong type.
+	ErrType = errors.New("type error")
+	// ErrMissingValue is returned when an expression is evaluated without the value of a metric it references.
+	ErrMissingValue = errors.New("missing value")
+	// ErrNotFinite is returned when the result of an expression is not a finite number, like after a division by
+	// zero.
+	ErrNotFinite = errors.New("result is not a finite number")
+)
+
+// Type is the type of the value of an expression.
+type Type int
+
+// Types of the values of expressions. Metrics are always numbers.
+const (
+	Number Type = iota
+	Bool
+)
+
+func (t Type) String() string {
+	if t == Bool {
+		return "bool"
+	}
+
+	return "number"
+}
+
+// Expr is a parsed expression.
+type Expr struct {
+	src  string
+	root node
+}
+
+// Parse parses src into an Expr, which must be type-checked with Check before it is evaluated.
+func Parse(src string) (*Expr, error) {
+	p := &parser{lexer: lexer{src: src}}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	root, err := p.parseExpr(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind != tokenEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+
+	return &Expr{src: src, root: root}, nil
+}
+
+// String returns the source of the expression.
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Variables returns the names of the metrics the expression references, sorted and without duplicates.
+func (e *Expr) Variables() []string {
+	var names []string
+	e.root.walk(func(n node) {
+		if v, ok := n.(variable); ok {
+			names = append(names, string(v))
+		}
+	})
+
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// Check returns an error wrapping ErrType if the expression references metrics for which isMetric returns false,
+// calls unknown functions or with the wrong number of arguments, combines values of the wrong type, or does not yield
+// a number.
+func (e *Expr) Check(isMetric func(name string) bool) error {
+	t, err := e.root.check(isMetric)
+	if err != nil {
+		return err
+	}
+
+	if t != Number {
+		return fmt.Errorf("%w: expression yields a %s, not a number", ErrType, t)
+	}
+
+	return nil
+}
+
+// Eval evaluates the expression with the given metric values. It returns an error wrapping ErrMissingValue if the
+// value of a metric it needs is not in values, and one wrapping ErrNotFinite if the result is not a finite number.
+func (e *Expr) Eval(values map[string]float64) (float64, error) {
+	v, err := e.root.eval(values)
+	if err != nil {
+		return 0, err
+	}
+
+	if math.IsNaN(v.num) || math.IsInf(v.num, 0) {
+		return 0, ErrNotFinite
+	}
+
+	return v.num, nil
+}
@@ -0,0 +1,121 @@
+package expression_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/expression"
+)
+
+var testValues = map[string]float64{
+	"memoryWorkingSetBytes": 50,
+	"memoryRequestedBytes":  200,
+	"replicasUnavailable":   1,
+	"replicasDesired":       0,
+	"net.rxBytesPerSecond":  10,
+}
+
+func isTestMetric(name string) bool {
+	_, ok := testValues[name]
+	return ok || name == "missing"
+}
+
+func TestEval(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		expression string
+		expected   float64
+	}{
+		"ratio":              {expression: "memoryWorkingSetBytes / memoryRequestedBytes * 100", expected: 25},
+		"precedence":         {expression: "1 + 2 * 3 - 4 / 2", expected: 5},
+		"parentheses":        {expression: "(1 + 2) * 3", expected: 9},
+		"unary_minus":        {expression: "-memoryWorkingSetBytes + -(-2)", expected: -48},
+		"modulo":             {expression: "7 % 4", expected: 3},
+		"exponent":           {expression: "1.5e2", expected: 150},
+		"dotted_metric_name": {expression: "net.rxBytesPerSecond * 8", expected: 80},
+		"functions":          {expression: "max(abs(-3), min(5, 4, 7))", expected: 4},
+		"if_true":            {expression: "if(memoryRequestedBytes > 0 && !(1 == 2), 1, 2)", expected: 1},
+		"if_avoids_division": {expression: "if(replicasDesired > 0, replicasUnavailable / replicasDesired, 0)", expected: 0},
+		"if_skips_missing":   {expression: "if(1 < 2 || missing > 0, 3, missing)", expected: 3},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := expression.Parse(tc.expression)
+			require.NoError(t, err)
+			require.NoError(t, expr.Check(isTestMetric))
+
+			v, err := expr.Eval(testValues)
+			require.NoError(t, err)
+			assert.InDelta(t, tc.expected, v, 1e-9)
+		})
+	}
+}
+
+func TestEval_Errors(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		expression string
+		expected   error
+	}{
+		"missing_value":    {expression: "missing + 1", expected: expression.ErrMissingValue},
+		"division_by_zero": {expression: "replicasUnavailable / replicasDesired", expected: expression.ErrNotFinite},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			expr, err := expression.Parse(tc.expression)
+			require.NoError(t, err)
+			require.NoError(t, expr.Check(isTestMetric))
+
+			_, err = expr.Eval(testValues)
+			require.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, src := range []string{"", "1 +", "(1 + 2", "min(1,", "1 2", "a $ b", "max(1 2)", "1..2"} {
+		_, err := expression.Parse(src)
+		require.ErrorIs(t, err, expression.ErrSyntax, src)
+	}
+}
+
+func TestCheck_Errors(t *testing.T) {
+	t.Parallel()
+
+	for _, src := range []string{
+		"unknownMetric * 2",
+		"pow(2, 3)",
+		"abs(1, 2)",
+		"min()",
+		"1 < 2",
+		"1 + (2 > 1)",
+		"!memoryRequestedBytes",
+		"1 && 2",
+		"if(1, 2, 3)",
+		"if(1 > 0, 1 > 0, 3)",
+	} {
+		expr, err := expression.Parse(src)
+		require.NoError(t, err, src)
+		require.ErrorIs(t, expr.Check(isTestMetric), expression.ErrType, src)
+	}
+}
+
+func TestVariables(t *testing.T) {
+	t.Parallel()
+
+	expr, err := expression.Parse("if(b > 0, a / b, a) + max(c, 1)")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, expr.Variables())
+}
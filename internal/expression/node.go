@@ -0,0 +1,282 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+)
+
+// value is the result of evaluating a node, whose field in use depends on its type.
+type value struct {
+	num     float64
+	boolean bool
+}
+
+type node interface {
+	check(isMetric func(name string) bool) (Type, error)
+	eval(values map[string]float64) (value, error)
+	walk(fn func(node))
+}
+
+type number float64
+
+func (n number) check(func(string) bool) (Type, error) {
+	return Number, nil
+}
+
+func (n number) eval(map[string]float64) (value, error) {
+	return value{num: float64(n)}, nil
+}
+
+func (n number) walk(fn func(node)) {
+	fn(n)
+}
+
+type variable string
+
+func (v variable) check(isMetric func(string) bool) (Type, error) {
+	if !isMetric(string(v)) {
+		return Number, fmt.Errorf("%w: unknown metric %q", ErrType, string(v))
+	}
+
+	return Number, nil
+}
+
+func (v variable) eval(values map[string]float64) (value, error) {
+	f, ok := values[string(v)]
+	if !ok {
+		return value{}, fmt.Errorf("%w for metric %q", ErrMissingValue, string(v))
+	}
+
+	return value{num: f}, nil
+}
+
+func (v variable) walk(fn func(node)) {
+	fn(v)
+}
+
+type unary struct {
+	op      string
+	operand node
+}
+
+func (u unary) check(isMetric func(string) bool) (Type, error) {
+	t, err := u.operand.check(isMetric)
+	if err != nil {
+		return t, err
+	}
+
+	want := Number
+	if u.op == "!" {
+		want = Bool
+	}
+
+	if t != want {
+		return want, fmt.Errorf("%w: operator %q expects a %s, got a %s", ErrType, u.op, want, t)
+	}
+
+	return want, nil
+}
+
+func (u unary) eval(values map[string]float64) (value, error) {
+	v, err := u.operand.eval(values)
+	if err != nil {
+		return v, err
+	}
+
+	if u.op == "!" {
+		return value{boolean: !v.boolean}, nil
+	}
+
+	return value{num: -v.num}, nil
+}
+
+func (u unary) walk(fn func(node)) {
+	fn(u)
+	u.operand.walk(fn)
+}
+
+type binary struct {
+	op          string
+	left, right node
+}
+
+// operandType returns the type of the operands of the operator, and the type of its result.
+func (b binary) operandType() (Type, Type) {
+	switch b.op {
+	case "&&", "||":
+		return Bool, Bool
+	case "==", "!=", "<", "<=", ">", ">=":
+		return Number, Bool
+	default:
+		return Number, Number
+	}
+}
+
+func (b binary) check(isMetric func(string) bool) (Type, error) {
+	operand, result := b.operandType()
+
+	for _, n := range []node{b.left, b.right} {
+		t, err := n.check(isMetric)
+		if err != nil {
+			return result, err
+		}
+
+		if t != operand {
+			return result, fmt.Errorf("%w: operator %q expects %s operands, got a %s", ErrType, b.op, operand, t)
+		}
+	}
+
+	return result, nil
+}
+
+func (b binary) eval(values map[string]float64) (value, error) {
+	left, err := b.left.eval(values)
+	if err != nil {
+		return left, err
+	}
+
+	// Logical operators short-circuit, so their right operand may refer to values only present when it is needed.
+	switch {
+	case b.op == "&&" && !left.boolean:
+		return value{boolean: false}, nil
+	case b.op == "||" && left.boolean:
+		return value{boolean: true}, nil
+	}
+
+	right, err := b.right.eval(values)
+	if err != nil {
+		return right, err
+	}
+
+	l, r := left.num, right.num
+	switch b.op {
+	case "&&", "||":
+		return value{boolean: right.boolean}, nil
+	case "==":
+		return value{boolean: l == r}, nil
+	case "!=":
+		return value{boolean: l != r}, nil
+	case "<":
+		return value{boolean: l < r}, nil
+	case "<=":
+		return value{boolean: l <= r}, nil
+	case ">":
+		return value{boolean: l > r}, nil
+	case ">=":
+		return value{boolean: l >= r}, nil
+	case "+":
+		return value{num: l + r}, nil
+	case "-":
+		return value{num: l - r}, nil
+	case "*":
+		return value{num: l * r}, nil
+	case "/":
+		return value{num: l / r}, nil
+	default: // "%"
+		return value{num: math.Mod(l, r)}, nil
+	}
+}
+
+func (b binary) walk(fn func(node)) {
+	fn(b)
+	b.left.walk(fn)
+	b.right.walk(fn)
+}
+
+// function is a function expressions can call. minArgs and maxArgs bound its number of arguments, with a negative
+// maxArgs meaning no bound.
+type function struct {
+	minArgs, maxArgs int
+	args             func(i int) Type
+}
+
+var functions = map[string]function{
+	"abs": {minArgs: 1, maxArgs: 1, args: numbers},
+	"min": {minArgs: 1, maxArgs: -1, args: numbers},
+	"max": {minArgs: 1, maxArgs: -1, args: numbers},
+	"if": {minArgs: 3, maxArgs: 3, args: func(i int) Type {
+		if i == 0 {
+			return Bool
+		}
+		return Number
+	}},
+}
+
+func numbers(int) Type {
+	return Number
+}
+
+type call struct {
+	name string
+	args []node
+}
+
+func (c call) check(isMetric func(string) bool) (Type, error) {
+	f, ok := functions[c.name]
+	if !ok {
+		return Number, fmt.Errorf("%w: unknown function %q", ErrType, c.name)
+	}
+
+	if len(c.args) < f.minArgs || (f.maxArgs >= 0 && len(c.args) > f.maxArgs) {
+		return Number, fmt.Errorf("%w: wrong number of arguments for %q: %d", ErrType, c.name, len(c.args))
+	}
+
+	for i, arg := range c.args {
+		t, err := arg.check(isMetric)
+		if err != nil {
+			return Number, err
+		}
+
+		if want := f.args(i); t != want {
+			return Number, fmt.Errorf("%w: argument #%d of %q must be a %s, got a %s", ErrType, i+1, c.name, want, t)
+		}
+	}
+
+	return Number, nil
+}
+
+func (c call) eval(values map[string]float64) (value, error) {
+	if c.name == "if" {
+		cond, err := c.args[0].eval(values)
+		if err != nil {
+			return cond, err
+		}
+
+		if cond.boolean {
+			return c.args[1].eval(values)
+		}
+		return c.args[2].eval(values)
+	}
+
+	args := make([]float64, 0, len(c.args))
+	for _, arg := range c.args {
+		v, err := arg.eval(values)
+		if err != nil {
+			return v, err
+		}
+		args = append(args, v.num)
+	}
+
+	result := args[0]
+	switch c.name {
+	case "abs":
+		result = math.Abs(result)
+	case "min":
+		for _, a := range args[1:] {
+			result = math.Min(result, a)
+		}
+	case "max":
+		for _, a := range args[1:] {
+			result = math.Max(result, a)
+		}
+	}
+
+	return value{num: result}, nil
+}
+
+func (c call) walk(fn func(node)) {
+	fn(c)
+	for _, arg := range c.args {
+		arg.walk(fn)
+	}
+}
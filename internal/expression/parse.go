@@ -0,0 +1,255 @@
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+
+	return strconv.Quote(t.text)
+}
+
+// operators are sorted so that the longest ones are matched first.
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "+", "-", "*", "/", "%", "<", ">", "!"}
+
+type lexer struct {
+	src string
+	pos int
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.src) && unicode.IsSpace(rune(l.src[l.pos])) {
+		l.pos++
+	}
+
+	start := l.pos
+	if l.pos == len(l.src) {
+		return token{kind: tokenEOF, pos: start}, nil
+	}
+
+	c := l.src[l.pos]
+	switch {
+	case c == '(':
+		l.pos++
+		return token{kind: tokenLParen, text: "(", pos: start}, nil
+	case c == ')':
+		l.pos++
+		return token{kind: tokenRParen, text: ")", pos: start}, nil
+	case c == ',':
+		l.pos++
+		return token{kind: tokenComma, text: ",", pos: start}, nil
+	case isDigit(c) || c == '.':
+		for l.pos < len(l.src) && (isDigit(l.src[l.pos]) || l.src[l.pos] == '.') {
+			l.pos++
+		}
+		// Exponents, like 1e9.
+		if l.pos < len(l.src) && (l.src[l.pos] == 'e' || l.src[l.pos] == 'E') {
+			l.pos++
+			if l.pos < len(l.src) && (l.src[l.pos] == '+' || l.src[l.pos] == '-') {
+				l.pos++
+			}
+			for l.pos < len(l.src) && isDigit(l.src[l.pos]) {
+				l.pos++
+			}
+		}
+		return token{kind: tokenNumber, text: l.src[start:l.pos], pos: start}, nil
+	case isIdentStart(c):
+		for l.pos < len(l.src) && (isIdentStart(l.src[l.pos]) || isDigit(l.src[l.pos]) || l.src[l.pos] == '.') {
+			l.pos++
+		}
+		return token{kind: tokenIdent, text: l.src[start:l.pos], pos: start}, nil
+	}
+
+	for _, op := range operators {
+		if strings.HasPrefix(l.src[l.pos:], op) {
+			l.pos += len(op)
+			return token{kind: tokenOperator, text: op, pos: start}, nil
+		}
+	}
+
+	return token{}, fmt.Errorf("%w at position %d: unexpected character %q", ErrSyntax, start, c)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// precedences of the binary operators. Higher ones bind tighter.
+var precedences = map[string]int{
+	"||": 1,
+	"&&": 2,
+	"==": 3, "!=": 3, "<": 3, "<=": 3, ">": 3, ">=": 3,
+	"+": 4, "-": 4,
+	"*": 5, "/": 5, "%": 5,
+}
+
+// unaryPrecedence binds tighter than any binary operator, so `-a * b` is `(-a) * b`.
+const unaryPrecedence = 6
+
+// parser is a precedence climbing parser over the tokens of lexer.
+type parser struct {
+	lexer lexer
+	tok   token
+}
+
+func (p *parser) next() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w at position %d: %s", ErrSyntax, p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+// parseExpr parses the binary operations whose operators have a precedence greater than minPrecedence.
+func (p *parser) parseExpr(minPrecedence int) (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.tok.kind == tokenOperator {
+		op := p.tok.text
+		precedence, ok := precedences[op]
+		if !ok || precedence <= minPrecedence {
+			break
+		}
+
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		right, err := p.parseExpr(precedence)
+		if err != nil {
+			return nil, err
+		}
+
+		left = binary{op: op, left: left, right: right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.tok.kind == tokenOperator && (p.tok.text == "-" || p.tok.text == "!") {
+		op := p.tok.text
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+
+		operand, err := p.parseExpr(unaryPrecedence)
+		if err != nil {
+			return nil, err
+		}
+
+		return unary{op: op, operand: operand}, nil
+	}
+
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.tok
+
+	switch tok.kind {
+	case tokenNumber:
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", tok.text)
+		}
+		return number(f), p.next()
+
+	case tokenIdent:
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokenLParen {
+			return variable(tok.text), nil
+		}
+		args, err := p.parseArgs()
+		if err != nil {
+			return nil, err
+		}
+		return call{name: tok.text, args: args}, nil
+
+	case tokenLParen:
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokenRParen {
+			return nil, p.errorf("expected \")\", got %s", p.tok)
+		}
+		return inner, p.next()
+
+	default:
+		return nil, p.errorf("unexpected %s", tok)
+	}
+}
+
+// parseArgs parses the arguments of a call, from its opening parenthesis to the closing one.
+func (p *parser) parseArgs() ([]node, error) {
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+
+	var args []node
+	if p.tok.kind == tokenRParen {
+		return args, p.next()
+	}
+
+	for {
+		arg, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+
+		switch p.tok.kind {
+		case tokenComma:
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		case tokenRParen:
+			return args, p.next()
+		default:
+			return nil, p.errorf("expected \",\" or \")\", got %s", p.tok)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Package labels limits the Kubernetes labels and annotations entities are decorated with, as some workloads carry
+// too many of them, or hash-like and very long ones.
+package labels
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/config"
+	"github.com/newrelic/nri-kubernetes/v3/internal/pattern"
+)
+
+// Prefixes of the attributes a Guard applies to.
+var prefixes = []string{"label.", "annotation."}
+
+// ErrInvalidPattern is returned when a pattern of the rules cannot be compiled.
+var ErrInvalidPattern = pattern.ErrInvalidPattern
+
+// IsLabel returns whether the attribute name holds a label or an annotation, and is thus subject to a Guard.
+func IsLabel(name string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type rule struct {
+	entityTypes pattern.EntityTypes
+	allow       []*regexp.Regexp
+	deny        []*regexp.Regexp
+}
+
+// Guard decides which label and annotation attributes are reported, as configured by config.Labels.
+// A Guard is safe for concurrent use, while the Entity limits it returns are not.
+type Guard struct {
+	rules          []rule
+	maxPerEntity   int
+	maxValueLength int
+}
+
+// NewGuard compiles the rules in c into a Guard.
+func NewGuard(c config.Labels) (*Guard, error) {
+	g := &Guard{
+		maxPerEntity:   c.MaxPerEntity,
+		maxValueLength: c.MaxValueLength,
+	}
+
+	for i, r := range c.Rules {
+		allow, err := pattern.Compile(r.Allow)
+		if err != nil {
+			return nil, fmt.Errorf("compiling allow patterns of rule #%d: %w", i, err)
+		}
+
+		deny, err := pattern.Compile(r.Deny)
+		if err != nil {
+			return nil, fmt.Errorf("compiling deny patterns of rule #%d: %w", i, err)
+		}
+
+		g.rules = append(g.rules, rule{
+			entityTypes: r.EntityTypes,
+			allow:       allow,
+			deny:        deny,
+		})
+	}
+
+	return g, nil
+}
+
+// ForEntity returns the limits for a single entity of the given type. It is safe to call on a nil Guard, in which
+// case every attribute is allowed.
+func (g *Guard) ForEntity(entityType string) *Entity {
+	if g == nil {
+		return nil
+	}
+
+	e := &Entity{guard: g}
+	for _, r := range g.rules {
+		if r.entityTypes.Matches(entityType) {
+			e.rules = append(e.rules, r)
+		}
+	}
+
+	return e
+}
+
+// Entity keeps track of the attributes allowed and dropped for a single entity.
+type Entity struct {
+	guard   *Guard
+	rules   []rule
+	allowed int
+	dropped int
+}
+
+// Allow returns whether the attribute name holding value should be reported, counting it towards the limits of the
+// entity if so. Attributes other than labels and annotations are always allowed. It is safe to call on a nil Entity.
+func (e *Entity) Allow(name string, value interface{}) bool {
+	if e == nil || !IsLabel(name) {
+		return true
+	}
+
+	if !e.matches(name) || e.tooLong(value) || (e.guard.maxPerEntity > 0 && e.allowed >= e.guard.maxPerEntity) {
+		e.dropped++
+		return false
+	}
+
+	e.allowed++
+	return true
+}
+
+// Dropped returns the number of attributes not allowed so far. It is safe to call on a nil Entity.
+func (e *Entity) Dropped() int {
+	if e == nil {
+		return 0
+	}
+
+	return e.dropped
+}
+
+func (e *Entity) matches(name string) bool {
+	hasAllowList := false
+	allowed := false
+
+	for _, r := range e.rules {
+		if pattern.MatchesAny(r.deny, name) {
+			return false
+		}
+
+		if len(r.allow) > 0 {
+			hasAllowList = true
+			allowed = allowed || pattern.MatchesAny(r.allow, name)
+		}
+	}
+
+	return !hasAllowList || allowed
+}
+
+func (e *Entity) tooLong(value interface{}) bool {
+	if e.guard.maxValueLength <= 0 {
+		return false
+	}
+
+	return len(fmt.Sprint(value)) > e.guard.maxValueLength
+}
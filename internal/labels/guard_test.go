@@ -0,0 +1,137 @@
+package labels_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/config"
+	"github.com/newrelic/nri-kubernetes/v3/internal/labels"
+)
+
+func TestGuard(t *testing.T) {
+	t.Parallel()
+
+	type attr struct {
+		name    string
+		value   string
+		allowed bool
+	}
+
+	testCases := map[string]struct {
+		config     config.Labels
+		entityType string
+		attributes []attr
+	}{
+		"allows_everything_by_default": {
+			entityType: "pod",
+			attributes: []attr{
+				{name: "label.app", value: "nginx", allowed: true},
+				{name: "annotation.example.com/owner", value: "team-a", allowed: true},
+			},
+		},
+		"denies_globs_matching_slashes": {
+			config: config.Labels{
+				Rules: []config.LabelRule{{Deny: []string{"label.pod-template-hash", "annotation.*"}}},
+			},
+			entityType: "pod",
+			attributes: []attr{
+				{name: "label.app", value: "nginx", allowed: true},
+				{name: "label.pod-template-hash", value: "5d59d67564", allowed: false},
+				{name: "annotation.example.com/owner", value: "team-a", allowed: false},
+			},
+		},
+		"allows_regex_for_matching_entity_types_only": {
+			config: config.Labels{
+				Rules: []config.LabelRule{{EntityTypes: []string{"namespace"}, Allow: []string{`regex:label\.team(-[a-z]+)?`}}},
+			},
+			entityType: "namespace",
+			attributes: []attr{
+				{name: "label.team", value: "a", allowed: true},
+				{name: "label.team-owner", value: "b", allowed: true},
+				{name: "label.environment", value: "prod", allowed: false},
+			},
+		},
+		"ignores_rules_of_other_entity_types": {
+			config: config.Labels{
+				Rules: []config.LabelRule{{EntityTypes: []string{"namespace"}, Allow: []string{"label.team"}}},
+			},
+			entityType: "pod",
+			attributes: []attr{
+				{name: "label.environment", value: "prod", allowed: true},
+			},
+		},
+		"deny_takes_precedence_over_allow": {
+			config: config.Labels{
+				Rules: []config.LabelRule{
+					{Allow: []string{"label.*"}},
+					{EntityTypes: []string{"pod"}, Deny: []string{"label.*-hash"}},
+				},
+			},
+			entityType: "pod",
+			attributes: []attr{
+				{name: "label.app", value: "nginx", allowed: true},
+				{name: "label.controller-revision-hash", value: "abc", allowed: false},
+			},
+		},
+		"drops_long_values": {
+			config:     config.Labels{MaxValueLength: 5},
+			entityType: "pod",
+			attributes: []attr{
+				{name: "label.app", value: "nginx", allowed: true},
+				{name: "label.checksum", value: "0123456789abcdef", allowed: false},
+			},
+		},
+		"limits_labels_per_entity": {
+			config:     config.Labels{MaxPerEntity: 2},
+			entityType: "pod",
+			attributes: []attr{
+				{name: "label.a", value: "1", allowed: true},
+				{name: "clusterName", value: "test", allowed: true},
+				{name: "annotation.b", value: "2", allowed: true},
+				{name: "label.c", value: "3", allowed: false},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			guard, err := labels.NewGuard(tc.config)
+			require.NoError(t, err)
+
+			entity := guard.ForEntity(tc.entityType)
+
+			dropped := 0
+			for _, a := range tc.attributes {
+				assert.Equal(t, a.allowed, entity.Allow(a.name, a.value), a.name)
+				if !a.allowed {
+					dropped++
+				}
+			}
+
+			assert.Equal(t, dropped, entity.Dropped())
+		})
+	}
+}
+
+func TestGuard_Nil(t *testing.T) {
+	t.Parallel()
+
+	var guard *labels.Guard
+
+	entity := guard.ForEntity("pod")
+	assert.True(t, entity.Allow("label.app", "nginx"))
+	assert.Zero(t, entity.Dropped())
+}
+
+func TestNewGuard_InvalidRegex(t *testing.T) {
+	t.Parallel()
+
+	_, err := labels.NewGuard(config.Labels{
+		Rules: []config.LabelRule{{Deny: []string{"regex:label.("}}},
+	})
+	assert.ErrorIs(t, err, labels.ErrInvalidPattern)
+}
@@ -0,0 +1,13 @@
+package pattern
+
+import "slices"
+
+// EntityTypes is a list of entity types, like `pod` or `container`, a rule of the configuration applies to. Entity
+// types are the names of the groups metric specs are defined in, and must match exactly. An empty list matches
+// entities of any type.
+type EntityTypes []string
+
+// Matches returns whether entityType is one of the listed types, or the list is empty.
+func (t EntityTypes) Matches(entityType string) bool {
+	return len(t) == 0 || slices.Contains(t, entityType)
+}
@@ -0,0 +1,64 @@
+// Package pattern compiles the name patterns used in the configuration, which are globs where `*` matches any sequence
+// of characters, including dots and slashes, or regular expressions when prefixed with `regex:`.
+package pattern
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const regexPrefix = "regex:"
+
+var ErrInvalidPattern = errors.New("invalid pattern")
+
+// Compile turns a list of patterns into regular expressions matching the whole name.
+func Compile(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+
+	for _, p := range patterns {
+		expr, isRegex := strings.CutPrefix(p, regexPrefix)
+		if !isRegex {
+			expr = globToRegex(p)
+		}
+
+		re, err := regexp.Compile("^(?:" + expr + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrInvalidPattern, p, err)
+		}
+
+		compiled = append(compiled, re)
+	}
+
+	return compiled, nil
+}
+
+// MatchesAny returns whether name is matched by any of the compiled patterns.
+func MatchesAny(patterns []*regexp.Regexp, name string) bool {
+	for _, re := range patterns {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// globToRegex translates a glob where `*` matches any sequence of characters and `?` any single character.
+func globToRegex(glob string) string {
+	var sb strings.Builder
+
+	for _, r := range glob {
+		switch r {
+		case '*':
+			sb.WriteString(".*")
+		case '?':
+			sb.WriteString(".")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+
+	return sb.String()
+}
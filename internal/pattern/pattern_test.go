@@ -0,0 +1,73 @@
+package pattern_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/pattern"
+)
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		pattern string
+		matches []string
+		misses  []string
+	}{
+		"literal": {
+			pattern: "label.app",
+			matches: []string{"label.app"},
+			misses:  []string{"label.apps", "xlabel.app", "label_app"},
+		},
+		"glob": {
+			pattern: "containerCpuCfs*Total",
+			matches: []string{"containerCpuCfsPeriodsTotal", "containerCpuCfsThrottledSecondsTotal"},
+			misses:  []string{"containerCpuCfsPeriodsDelta"},
+		},
+		"glob_single_character": {
+			pattern: "pod?",
+			matches: []string{"pods"},
+			misses:  []string{"pod", "podss"},
+		},
+		"regex_matching_whole_name": {
+			pattern: `regex:label\.team(-[a-z]+)?`,
+			matches: []string{"label.team", "label.team-owner"},
+			misses:  []string{"label.teams", "label.team-1"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			compiled, err := pattern.Compile([]string{tc.pattern})
+			require.NoError(t, err)
+
+			for _, m := range tc.matches {
+				assert.True(t, pattern.MatchesAny(compiled, m), m)
+			}
+			for _, m := range tc.misses {
+				assert.False(t, pattern.MatchesAny(compiled, m), m)
+			}
+		})
+	}
+}
+
+func TestCompile_InvalidRegex(t *testing.T) {
+	t.Parallel()
+
+	_, err := pattern.Compile([]string{"regex:label.("})
+	assert.ErrorIs(t, err, pattern.ErrInvalidPattern)
+}
+
+func TestEntityTypes_Matches(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, pattern.EntityTypes(nil).Matches("pod"), "empty list should match any type")
+	assert.True(t, pattern.EntityTypes{"pod", "container"}.Matches("container"))
+	assert.False(t, pattern.EntityTypes{"pod", "container"}.Matches("node"))
+	assert.False(t, pattern.EntityTypes{"pod"}.Matches("po*"), "types should not be matched as globs")
+}
@@ -0,0 +1,99 @@
+package recorded
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const (
+	endpointsFile  = "endpoints.yaml"
+	namespacesFile = "namespaces.yaml"
+	nodesFile      = "nodes.yaml"
+	podsFile       = "pods.yaml"
+	servicesFile   = "services.yaml"
+)
+
+// K8s provides fake instances of the K8s objects recorded for a version, ready to use with the kubernetes fake client.
+type K8s struct {
+	fsys    fs.FS
+	version string
+}
+
+// K8s returns the K8s objects recorded for the given version.
+func (d Data) K8s(version string) (K8s, error) {
+	_, err := fs.ReadDir(d.fsys, version)
+	if err != nil {
+		return K8s{}, fmt.Errorf("cannot stat testdata dir for version %q: %w", version, err)
+	}
+
+	return K8s{fsys: d.fsys, version: version}, nil
+}
+
+func (k K8s) Everything() []runtime.Object {
+	return []runtime.Object{
+		k.Endpoints(),
+		k.Namespaces(),
+		k.Nodes(),
+		k.Pods(),
+		k.Services(),
+	}
+}
+
+func (k K8s) Namespaces() runtime.Object {
+	var namespaceList corev1.NamespaceList
+	if err := k.loadYaml(&namespaceList, namespacesFile); err != nil {
+		panic(err)
+	}
+
+	return &namespaceList
+}
+
+func (k K8s) Services() runtime.Object {
+	var services corev1.ServiceList
+	if err := k.loadYaml(&services, servicesFile); err != nil {
+		panic(err)
+	}
+
+	return &services
+}
+
+func (k K8s) Nodes() runtime.Object {
+	var nodes corev1.NodeList
+	if err := k.loadYaml(&nodes, nodesFile); err != nil {
+		panic(err)
+	}
+
+	return &nodes
+}
+
+func (k K8s) Endpoints() runtime.Object {
+	var nodes corev1.EndpointsList
+	if err := k.loadYaml(&nodes, endpointsFile); err != nil {
+		panic(err)
+	}
+
+	return &nodes
+}
+
+func (k K8s) Pods() runtime.Object {
+	var nodes corev1.PodList
+	if err := k.loadYaml(&nodes, podsFile); err != nil {
+		panic(err)
+	}
+
+	return &nodes
+}
+
+func (k K8s) loadYaml(dst interface{}, file string) error {
+	yamlFile, err := fs.ReadFile(k.fsys, path.Join(k.version, file))
+	if err != nil {
+		return fmt.Errorf("reading testdata %s: %w", file, err)
+	}
+
+	return yaml.Unmarshal(yamlFile, dst) //nolint: wrapcheck
+}
@@ -0,0 +1,84 @@
+// Package recorded serves data recorded from clusters running different Kubernetes versions, laid out as in
+// internal/testutil/data, the way the KSM, kubelet and control plane endpoints would, and loads the Kubernetes objects
+// recorded along with it.
+package recorded
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"regexp"
+)
+
+// versionDir matches the names of the directories holding the data of a version, like `1_34`.
+var versionDir = regexp.MustCompile(`^\d+_\d+$`)
+
+// Data is a directory with the recorded data of one or more versions, each in a subdirectory named after it, like
+// `1_34`.
+type Data struct {
+	fsys fs.FS
+}
+
+// New returns the Data recorded in fsys.
+func New(fsys fs.FS) Data {
+	return Data{fsys: fsys}
+}
+
+// Versions returns the versions there is data for, sorted.
+func (d Data) Versions() ([]string, error) {
+	entries, err := fs.ReadDir(d.fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("reading data dir: %w", err)
+	}
+
+	var versions []string
+	for _, e := range entries {
+		if e.IsDir() && versionDir.MatchString(e.Name()) {
+			versions = append(versions, e.Name())
+		}
+	}
+
+	return versions, nil
+}
+
+// Server is an HTTP server serving the endpoints of a version.
+type Server struct {
+	*httptest.Server
+}
+
+// KSMEndpoint returns the full URL of the KSM metrics endpoint.
+func (s *Server) KSMEndpoint() string {
+	// We must add /metrics to the URL here as the KSM override endpoint must be a full URL
+	return s.Server.URL + "/ksm/metrics"
+}
+
+// KubeletEndpoint returns the base URL of the kubelet endpoints.
+func (s *Server) KubeletEndpoint() string {
+	return s.Server.URL + "/kubelet"
+}
+
+// ControlPlaneEndpoint returns the full URL of the metrics endpoint of a control plane component.
+func (s *Server) ControlPlaneEndpoint(component string) string {
+	return s.Server.URL + path.Join("/controlplane", component, "metrics")
+}
+
+// Server starts a Server for the given version, ready to serve static endpoints for KSM, Kubelet and CP components.
+// It must be closed once no longer used.
+func (d Data) Server(version string) (*Server, error) {
+	subversion, err := fs.Sub(d.fsys, version)
+	if err != nil {
+		return nil, fmt.Errorf("opening dir for version %s: %w", version, err)
+	}
+
+	fileserver := http.FileServer(http.FS(subversion))
+	testServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("server", "testutil fake http server")
+		rw.Header().Set("testutil-data-version", version)
+
+		fileserver.ServeHTTP(rw, r)
+	}))
+
+	return &Server{testServer}, nil
+}
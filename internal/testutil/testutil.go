@@ -2,7 +2,10 @@ package testutil
 
 import (
 	"embed"
+	"io/fs"
 	"sort"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/recorded"
 )
 
 //go:embed data
@@ -11,6 +14,16 @@ var testDataDir embed.FS
 // Name of the root folder in embed.FS
 const testDataRootDir = "data"
 
+// testData returns the recorded data embedded in testDataDir.
+func testData() recorded.Data {
+	root, err := fs.Sub(testDataDir, testDataRootDir)
+	if err != nil {
+		panic(err) // testDataRootDir is always a valid path.
+	}
+
+	return recorded.New(root)
+}
+
 // Version represents a kubernetes version. Mock servers can be instantiated to return known output for a given version.
 type Version string
 
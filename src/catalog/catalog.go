@@ -0,0 +1,126 @@
+// Package catalog lists the metrics the integration reports for each entity type, where they are read from, and which
+// Kubernetes versions they were seen to produce values on.
+package catalog
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	sdkMetric "github.com/newrelic/infra-integrations-sdk/data/metric"
+
+	kubeletMetric "github.com/newrelic/nri-kubernetes/v3/src/kubelet/metric"
+	"github.com/newrelic/nri-kubernetes/v3/src/metric"
+)
+
+// Entry describes a metric, or a set of them for specs like `label.*`, of an entity type.
+type Entry struct {
+	// EntityType is the group the metric is defined in, like `pod`.
+	EntityType string `json:"entityType"`
+	Name       string `json:"name"`
+	// Target is the scrape target the metric is computed for, like `kubelet` or `ksm`.
+	Target string `json:"target"`
+	// Endpoint is the endpoint of the target the metric is read from.
+	Endpoint string `json:"endpoint"`
+	// RawMetrics are the Prometheus metrics the value is computed from, as declared by its spec. They are unknown for
+	// metrics read from endpoints other than Prometheus ones, and for the ones of spec files not declaring them.
+	RawMetrics []string `json:"rawMetrics,omitempty"`
+	// Type is the SDK source type of the metric, like `gauge` or `attribute`.
+	Type     string `json:"type"`
+	Optional bool   `json:"optional"`
+	// Computed is true for metrics computed from the others of the entity type.
+	Computed bool `json:"computed,omitempty"`
+	// Versions are the Kubernetes versions, out of Catalog.Versions, for which the recorded data produces a value for
+	// the metric.
+	Versions []string `json:"versions,omitempty"`
+}
+
+// Catalog is the list of the metrics of the integration, sorted by entity type, name and target.
+type Catalog struct {
+	// Versions are the Kubernetes versions of the recorded data entries were checked against, if any.
+	Versions []string `json:"versions,omitempty"`
+	Entries  []Entry  `json:"entries"`
+
+	definitions map[string]metric.Definitions
+}
+
+// New returns the Catalog of the metrics in defs, indexed by target.
+func New(defs map[string]metric.Definitions) *Catalog {
+	c := &Catalog{definitions: defs}
+
+	for target, d := range defs {
+		for entityType, group := range d.Specs {
+			for _, spec := range group.Specs {
+				c.Entries = append(c.Entries, Entry{
+					EntityType: entityType,
+					Name:       spec.Name,
+					Target:     target,
+					Endpoint:   endpoint(target, len(spec.RawMetrics) > 0),
+					RawMetrics: spec.RawMetrics,
+					Type:       typeName(spec.Type),
+					Optional:   spec.Optional,
+				})
+			}
+
+			for _, spec := range group.Computed {
+				c.Entries = append(c.Entries, Entry{
+					EntityType: entityType,
+					Name:       spec.Name,
+					Target:     target,
+					Type:       typeName(spec.Type),
+					Optional:   true,
+					Computed:   true,
+				})
+			}
+		}
+	}
+
+	slices.SortFunc(c.Entries, func(a, b Entry) int {
+		return cmp.Or(
+			strings.Compare(a.EntityType, b.EntityType),
+			strings.Compare(a.Name, b.Name),
+			strings.Compare(a.Target, b.Target),
+		)
+	})
+
+	return c
+}
+
+// Definitions returns the definitions the catalog was built from, indexed by target.
+func (c *Catalog) Definitions() map[string]metric.Definitions {
+	return c.definitions
+}
+
+// EntityTypes returns the entity types of the entries of the catalog, sorted.
+func (c *Catalog) EntityTypes() []string {
+	types := map[string]bool{}
+	for _, e := range c.Entries {
+		types[e.EntityType] = true
+	}
+
+	return slices.Sorted(maps.Keys(types))
+}
+
+// endpoint returns the endpoint of the target metrics are read from. Kubelet metrics are read from the cAdvisor
+// endpoint if they are computed from Prometheus metrics, and from the kubelet API otherwise.
+func endpoint(target string, fromPrometheus bool) string {
+	if target != metric.TargetKubelet {
+		return "/metrics"
+	}
+
+	if fromPrometheus {
+		return kubeletMetric.KubeletCAdvisorMetricsPath
+	}
+
+	return fmt.Sprintf("%s, %s", kubeletMetric.StatsSummaryPath, kubeletMetric.KubeletPodsPath)
+}
+
+func typeName(t sdkMetric.SourceType) string {
+	if name, ok := sdkMetric.SourcesTypeToName[t]; ok {
+		return name
+	}
+
+	return fmt.Sprint(t)
+}
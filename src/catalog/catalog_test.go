@@ -0,0 +1,102 @@
+package catalog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/config"
+	"github.com/newrelic/nri-kubernetes/v3/src/catalog"
+	kubeletMetric "github.com/newrelic/nri-kubernetes/v3/src/kubelet/metric"
+	"github.com/newrelic/nri-kubernetes/v3/src/metric"
+)
+
+func findEntry(t *testing.T, c *catalog.Catalog, target, entityType, name string) catalog.Entry {
+	t.Helper()
+
+	for _, e := range c.Entries {
+		if e.Target == target && e.EntityType == entityType && e.Name == name {
+			return e
+		}
+	}
+
+	t.Fatalf("entry %s/%s/%s not found", target, entityType, name)
+	return catalog.Entry{}
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	c := catalog.New(metric.Builtin())
+
+	ksmPods := findEntry(t, c, metric.TargetKSM, "deployment", "podsDesired")
+	assert.Equal(t, "/metrics", ksmPods.Endpoint)
+	assert.Equal(t, []string{"kube_deployment_spec_replicas"}, ksmPods.RawMetrics)
+	assert.Equal(t, "gauge", ksmPods.Type)
+
+	storage := findEntry(t, c, metric.TargetAPIServer, "api-server", "apiserverStorageObjects")
+	assert.Equal(t, []string{"apiserver_storage_objects", "etcd_object_counts"}, storage.RawMetrics, "fallbacks are listed")
+
+	cfs := findEntry(t, c, metric.TargetKubelet, "container", "containerCpuCfsPeriodsTotal")
+	assert.Equal(t, kubeletMetric.KubeletCAdvisorMetricsPath, cfs.Endpoint)
+
+	memory := findEntry(t, c, metric.TargetKubelet, "container", "memoryWorkingSetBytes")
+	assert.Empty(t, memory.RawMetrics)
+	assert.Contains(t, memory.Endpoint, kubeletMetric.StatsSummaryPath)
+
+	assert.Contains(t, c.EntityTypes(), "pod")
+	assert.IsIncreasing(t, c.EntityTypes())
+}
+
+func TestNew_Computed(t *testing.T) {
+	t.Parallel()
+
+	defs, err := metric.ComputeDefinitions(metric.Builtin(), metricsWithComputed)
+	require.NoError(t, err)
+
+	ratio := findEntry(t, catalog.New(defs), metric.TargetKubelet, "container", "memoryWorkingSetRequestRatio")
+	assert.True(t, ratio.Computed)
+	assert.True(t, ratio.Optional)
+}
+
+func TestCatalog_Write(t *testing.T) {
+	t.Parallel()
+
+	c := &catalog.Catalog{
+		Versions: []string{"1.33", "1.34"},
+		Entries: []catalog.Entry{
+			{EntityType: "pod", Name: "isReady", Target: "ksm", Endpoint: "/metrics", Type: "gauge", Versions: []string{"1.34"}},
+			{EntityType: "pod", Name: "label.*", Target: "ksm", Endpoint: "/metrics", Type: "attribute", Optional: true},
+		},
+	}
+
+	var jsonOut bytes.Buffer
+	require.NoError(t, c.WriteJSON(&jsonOut))
+
+	var decoded catalog.Catalog
+	require.NoError(t, json.Unmarshal(jsonOut.Bytes(), &decoded))
+	assert.Equal(t, c.Entries, decoded.Entries)
+	assert.Equal(t, c.Versions, decoded.Versions)
+
+	var markdown bytes.Buffer
+	require.NoError(t, c.WriteMarkdown(&markdown))
+
+	lines := strings.Split(markdown.String(), "\n")
+	assert.Contains(t, lines, "## pod")
+	assert.Contains(t, lines, "| Metric | Type | Optional | Target | Endpoint | Raw metrics | 1.33 | 1.34 |")
+	assert.Contains(t, lines, "| `isReady` | gauge | no | ksm | /metrics |  | no | yes |")
+	assert.Contains(t, lines, "| `label.*` | attribute | yes | ksm | /metrics |  | no | no |")
+
+	assert.ErrorIs(t, c.Write(io.Discard, "yaml"), catalog.ErrUnknownFormat)
+}
+
+var metricsWithComputed = config.Metrics{Computed: []config.ComputedMetric{{
+	Name:        "memoryWorkingSetRequestRatio",
+	EntityTypes: []string{"container"},
+	Expression:  "memoryWorkingSetBytes / memoryRequestedBytes",
+}}}
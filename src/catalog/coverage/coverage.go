@@ -0,0 +1,237 @@
+// Package coverage checks which metrics of a catalog.Catalog produce values on the data recorded for each Kubernetes
+// version. It runs the scrapers against a fake cluster, so it lives apart from package catalog to keep the fake
+// clients out of the integration binary.
+package coverage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/newrelic/infra-integrations-sdk/integration"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/config"
+	"github.com/newrelic/nri-kubernetes/v3/internal/recorded"
+	"github.com/newrelic/nri-kubernetes/v3/src/catalog"
+	"github.com/newrelic/nri-kubernetes/v3/src/controlplane"
+	"github.com/newrelic/nri-kubernetes/v3/src/definition"
+	"github.com/newrelic/nri-kubernetes/v3/src/ksm"
+	ksmClient "github.com/newrelic/nri-kubernetes/v3/src/ksm/client"
+	"github.com/newrelic/nri-kubernetes/v3/src/kubelet"
+	kubeletClient "github.com/newrelic/nri-kubernetes/v3/src/kubelet/client"
+	"github.com/newrelic/nri-kubernetes/v3/src/metric"
+)
+
+const recordedClusterName = "catalog"
+
+// entryKey identifies an Entry of the catalog.
+type entryKey struct {
+	target, entityType, name string
+}
+
+// producedSet records the entries that produced a value while scraping the recorded data of a version. It is safe for
+// concurrent use, as entities are populated concurrently.
+type producedSet struct {
+	lock     sync.Mutex
+	produced map[entryKey]bool
+}
+
+func (ps *producedSet) add(key entryKey) {
+	ps.lock.Lock()
+	defer ps.lock.Unlock()
+
+	ps.produced[key] = true
+}
+
+// Check runs the scrapers against the recorded data of every version in data, and sets the Versions of each entry of c
+// producing a value for some entity on them. Versions are named like `1.34`.
+func Check(ctx context.Context, c *catalog.Catalog, data recorded.Data, logger *log.Logger) error {
+	versions, err := data.Versions()
+	if err != nil {
+		return fmt.Errorf("listing recorded versions: %w", err)
+	}
+
+	for _, dir := range versions {
+		produced, err := scrapeRecorded(ctx, c.Definitions(), data, dir, logger)
+		if err != nil {
+			return fmt.Errorf("scraping data of version %s: %w", dir, err)
+		}
+
+		version := strings.ReplaceAll(dir, "_", ".")
+		c.Versions = append(c.Versions, version)
+		for i, e := range c.Entries {
+			if produced[entryKey{target: e.Target, entityType: e.EntityType, name: e.Name}] {
+				c.Entries[i].Versions = append(c.Entries[i].Versions, version)
+			}
+		}
+	}
+
+	return nil
+}
+
+// scrapeRecorded runs the KSM, kubelet and control plane scrapers with definitions against the data of version, and
+// returns the entries producing a value.
+func scrapeRecorded(
+	ctx context.Context,
+	definitions map[string]metric.Definitions,
+	data recorded.Data,
+	version string,
+	logger *log.Logger,
+) (map[entryKey]bool, error) {
+	server, err := data.Server(version)
+	if err != nil {
+		return nil, err //nolint: wrapcheck
+	}
+	defer server.Close()
+
+	k8sData, err := data.K8s(version)
+	if err != nil {
+		return nil, err //nolint: wrapcheck
+	}
+	k8s := fake.NewSimpleClientset(k8sData.Everything()...)
+
+	i, err := integration.New(recordedClusterName, "recorded", integration.Writer(io.Discard), integration.InMemoryStore())
+	if err != nil {
+		return nil, fmt.Errorf("creating integration: %w", err)
+	}
+
+	ps := &producedSet{produced: map[entryKey]bool{}}
+	defs := make(map[string]metric.Definitions, len(definitions))
+	for target, d := range definitions {
+		defs[target] = instrument(target, d, ps)
+	}
+
+	ksmCli, err := ksmClient.New(ksmClient.WithLogger(logger))
+	if err != nil {
+		return nil, fmt.Errorf("creating KSM client: %w", err)
+	}
+
+	ksmScraper, err := ksm.NewScraper(&config.Config{
+		ClusterName: recordedClusterName,
+		KSM:         config.KSM{StaticURL: server.KSMEndpoint(), EnableResourceQuotaSamples: true},
+	}, ksm.Providers{K8s: k8s, KSM: ksmCli}, ksm.WithLogger(logger), ksm.WithDefinitions(defs[metric.TargetKSM]))
+	if err != nil {
+		return nil, fmt.Errorf("creating KSM scraper: %w", err)
+	}
+	defer ksmScraper.Close()
+
+	kubeletURL, err := url.Parse(server.KubeletEndpoint())
+	if err != nil {
+		return nil, fmt.Errorf("parsing kubelet URL: %w", err)
+	}
+
+	kubeletCli, err := kubeletClient.New(kubeletClient.StaticConnector(&http.Client{}, *kubeletURL), kubeletClient.WithLogger(logger))
+	if err != nil {
+		return nil, fmt.Errorf("creating kubelet client: %w", err)
+	}
+
+	kubeletScraper, err := kubelet.NewScraper(
+		&config.Config{ClusterName: recordedClusterName},
+		kubelet.Providers{K8s: k8s, Kubelet: kubeletCli, CAdvisor: kubeletCli},
+		kubelet.WithLogger(logger),
+		kubelet.WithDefinitions(defs[metric.TargetKubelet]),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating kubelet scraper: %w", err)
+	}
+	defer kubeletScraper.Close()
+
+	staticComponent := func(name controlplane.ComponentName) config.ControlPlaneComponent {
+		return config.ControlPlaneComponent{
+			Enabled:        true,
+			StaticEndpoint: &config.Endpoint{URL: server.ControlPlaneEndpoint(string(name))},
+		}
+	}
+
+	controlPlaneScraper, err := controlplane.NewScraper(&config.Config{
+		ClusterName: recordedClusterName,
+		ControlPlane: config.ControlPlane{
+			Enabled:           true,
+			ETCD:              staticComponent(controlplane.Etcd),
+			APIServer:         staticComponent(controlplane.APIServer),
+			ControllerManager: staticComponent(controlplane.ControllerManager),
+			Scheduler:         staticComponent(controlplane.Scheduler),
+		},
+	}, controlplane.Providers{K8s: k8s}, controlplane.WithLogger(logger), controlplane.WithDefinitions(defs))
+	if err != nil {
+		return nil, fmt.Errorf("creating control plane scraper: %w", err)
+	}
+	defer controlPlaneScraper.Close()
+
+	// Scrapers fail when some of their endpoints do, which is expected for data not recorded for some versions, so
+	// their errors are only logged.
+	scrapers := []struct {
+		name string
+		run  func(context.Context, *integration.Integration) error
+	}{
+		{name: metric.TargetKSM, run: ksmScraper.Run},
+		{name: metric.TargetKubelet, run: kubeletScraper.Run},
+		{name: "control plane", run: controlPlaneScraper.Run},
+	}
+	for _, s := range scrapers {
+		if err := s.run(ctx, i); err != nil {
+			logger.Warnf("Scraping %s data of version %s: %v", s.name, version, err)
+		}
+	}
+
+	return ps.produced, nil
+}
+
+// instrument returns a copy of d whose specs and computed specs record in ps whether they produce a value.
+func instrument(target string, d metric.Definitions, ps *producedSet) metric.Definitions {
+	specs := make(definition.SpecGroups, len(d.Specs))
+
+	for entityType, group := range d.Specs {
+		instrumented := group
+		instrumented.Specs = make([]definition.Spec, 0, len(group.Specs))
+		instrumented.Computed = make([]definition.ComputedSpec, 0, len(group.Computed))
+
+		for _, spec := range group.Specs {
+			key := entryKey{target: target, entityType: entityType, name: spec.Name}
+			valueFunc := spec.ValueFunc
+			spec.ValueFunc = func(groupLabel, entityID string, groups definition.RawGroups) (definition.FetchedValue, error) {
+				val, err := valueFunc(groupLabel, entityID, groups)
+				if err == nil && hasValue(val) {
+					ps.add(key)
+				}
+				return val, err
+			}
+			instrumented.Specs = append(instrumented.Specs, spec)
+		}
+
+		for _, spec := range group.Computed {
+			key := entryKey{target: target, entityType: entityType, name: spec.Name}
+			computeFunc := spec.ComputeFunc
+			spec.ComputeFunc = func(values map[string]float64) (definition.FetchedValue, error) {
+				val, err := computeFunc(values)
+				if err == nil && hasValue(val) {
+					ps.add(key)
+				}
+				return val, err
+			}
+			instrumented.Computed = append(instrumented.Computed, spec)
+		}
+
+		specs[entityType] = instrumented
+	}
+
+	return metric.Definitions{Specs: specs, Queries: d.Queries}
+}
+
+// hasValue returns whether val is a value, or a non-empty set of them.
+func hasValue(val definition.FetchedValue) bool {
+	switch values := val.(type) {
+	case definition.FetchedValues:
+		return len(values) > 0
+	case definition.Summaries:
+		return len(values) > 0
+	}
+
+	return val != nil
+}
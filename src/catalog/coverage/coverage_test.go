@@ -0,0 +1,64 @@
+package coverage_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/config"
+	"github.com/newrelic/nri-kubernetes/v3/internal/recorded"
+	"github.com/newrelic/nri-kubernetes/v3/src/catalog"
+	"github.com/newrelic/nri-kubernetes/v3/src/catalog/coverage"
+	"github.com/newrelic/nri-kubernetes/v3/src/metric"
+)
+
+func findEntry(t *testing.T, c *catalog.Catalog, target, entityType, name string) catalog.Entry {
+	t.Helper()
+
+	for _, e := range c.Entries {
+		if e.Target == target && e.EntityType == entityType && e.Name == name {
+			return e
+		}
+	}
+
+	t.Fatalf("entry %s/%s/%s not found", target, entityType, name)
+	return catalog.Entry{}
+}
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	logger := log.New()
+	logger.SetOutput(io.Discard)
+
+	defs, err := metric.ComputeDefinitions(metric.Builtin(), config.Metrics{Computed: []config.ComputedMetric{{
+		Name:        "memoryWorkingSetRequestRatio",
+		EntityTypes: []string{"container"},
+		Expression:  "memoryWorkingSetBytes / memoryRequestedBytes",
+	}}})
+	require.NoError(t, err)
+
+	c := catalog.New(defs)
+	err = coverage.Check(context.Background(), c, recorded.New(os.DirFS("../../../internal/testutil/data")), logger)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, c.Versions)
+	assert.NotContains(t, c.Versions[0], "_")
+
+	for _, e := range []catalog.Entry{
+		findEntry(t, c, metric.TargetKSM, "deployment", "podsDesired"),
+		findEntry(t, c, metric.TargetKubelet, "pod", "createdAt"),
+		findEntry(t, c, metric.TargetKubelet, "container", "memoryWorkingSetRequestRatio"),
+		findEntry(t, c, metric.TargetAPIServer, "api-server", "goGoroutines"),
+	} {
+		assert.Equal(t, c.Versions, e.Versions, "%s/%s should produce values on every version", e.EntityType, e.Name)
+	}
+
+	// Metrics renamed upstream are not present in any recorded version.
+	assert.Empty(t, findEntry(t, c, metric.TargetAPIServer, "api-server", "etcdObjectCounts").Versions)
+}
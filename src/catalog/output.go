@@ -0,0 +1,105 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Formats the catalog can be written in.
+const (
+	FormatJSON     = "json"
+	FormatMarkdown = "markdown"
+)
+
+// ErrUnknownFormat is returned when writing the catalog in a format that is not supported.
+var ErrUnknownFormat = errors.New("unknown catalog format")
+
+// Write writes the catalog to w in format, either FormatJSON or FormatMarkdown.
+func (c *Catalog) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatJSON:
+		return c.WriteJSON(w)
+	case FormatMarkdown:
+		return c.WriteMarkdown(w)
+	default:
+		return fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+}
+
+// WriteJSON writes the catalog to w as indented JSON.
+func (c *Catalog) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(c); err != nil {
+		return fmt.Errorf("encoding catalog: %w", err)
+	}
+
+	return nil
+}
+
+// WriteMarkdown writes the catalog to w as a Markdown document with a table per entity type. If the catalog was
+// checked against recorded data, a column per version marks the metrics producing values on it.
+func (c *Catalog) WriteMarkdown(w io.Writer) error {
+	var sb strings.Builder
+
+	sb.WriteString("# Metric catalog\n")
+
+	header := []string{"Metric", "Type", "Optional", "Target", "Endpoint", "Raw metrics"}
+	header = append(header, c.Versions...)
+
+	for _, entityType := range c.EntityTypes() {
+		fmt.Fprintf(&sb, "\n## %s\n\n", entityType)
+		writeRow(&sb, header)
+
+		separator := make([]string, len(header))
+		for i := range separator {
+			separator[i] = "---"
+		}
+		writeRow(&sb, separator)
+
+		for _, e := range c.Entries {
+			if e.EntityType != entityType {
+				continue
+			}
+
+			name := "`" + e.Name + "`"
+			if e.Computed {
+				name += " (computed)"
+			}
+
+			row := []string{name, e.Type, yesNo(e.Optional), e.Target, e.Endpoint, strings.Join(e.RawMetrics, ", ")}
+			for _, v := range c.Versions {
+				row = append(row, yesNo(slices.Contains(e.Versions, v)))
+			}
+			writeRow(&sb, row)
+		}
+	}
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("writing catalog: %w", err)
+	}
+
+	return nil
+}
+
+func writeRow(sb *strings.Builder, cells []string) {
+	for _, cell := range cells {
+		sb.WriteString("| ")
+		sb.WriteString(strings.ReplaceAll(cell, "|", `\|`))
+		sb.WriteString(" ")
+	}
+	sb.WriteString("|\n")
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+
+	return "no"
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 )
@@ -8,8 +9,8 @@ import (
 // HTTPGetter is an interface for HTTP client with, which should provide
 // scheme, port and hostname for the HTTP call.
 type HTTPGetter interface {
-	Get(path string) (*http.Response, error)
-	GetURI(uri url.URL) (*http.Response, error)
+	Get(ctx context.Context, path string) (*http.Response, error)
+	GetURI(ctx context.Context, uri url.URL) (*http.Response, error)
 }
 
 type HTTPDoer interface {
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -83,8 +84,8 @@ func New(connector connector.Connector, opts ...OptionFunc) (*Client, error) {
 // MetricFamiliesGetFunc returns a function that obtains metric families from a list of prometheus queries.
 // Notice that it does not satisfy prometheus.MetricFamiliesGetFunc, since the url path is injected by the connector
 func (c *Client) MetricFamiliesGetFunc() prometheus.FetchAndFilterMetricsFamilies {
-	return func(queries []prometheus.Query) ([]prometheus.MetricFamily, error) {
-		mFamily, err := prometheus.GetFilteredMetricFamilies(c.doer, c.endpoint.String(), queries, c.logger)
+	return func(ctx context.Context, queries []prometheus.Query) ([]prometheus.MetricFamily, error) {
+		mFamily, err := prometheus.GetFilteredMetricFamilies(ctx, c.doer, c.endpoint.String(), queries, c.logger)
 		if err != nil {
 			return nil, fmt.Errorf("getting filtered metric families %q: %w", c.endpoint.String(), err)
 		}
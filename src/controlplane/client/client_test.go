@@ -1,6 +1,7 @@
 package client_test
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -44,7 +45,7 @@ func Test_Client(t *testing.T) {
 	familyGetter := cpClient.MetricFamiliesGetFunc()
 
 	// Scrapes prometheus endpoint
-	_, err = familyGetter(nil)
+	_, err = familyGetter(context.Background(), nil)
 	require.NoError(t, err)
 	require.Equal(t, true, hit)
 
@@ -52,7 +53,7 @@ func Test_Client(t *testing.T) {
 	serverDelay = serverDelay + c.Timeout
 
 	// Fails if timeout
-	_, err = familyGetter(nil)
+	_, err = familyGetter(context.Background(), nil)
 	require.Error(t, err)
 
 	// reset Server Delay
@@ -68,7 +69,7 @@ func Test_Client(t *testing.T) {
 	serverDelay = serverDelay + c.Timeout
 
 	// Should not fail because of second retry
-	_, err = familyGetter(nil)
+	_, err = familyGetter(context.Background(), nil)
 	require.NoError(t, err)
 }
 
@@ -31,14 +31,14 @@ type component struct {
 	StaticEndpointConfig *config.Endpoint
 }
 
-func newComponents(config config.ControlPlane) []component {
+func newComponents(config config.ControlPlane, definitions map[string]metric.Definitions) []component {
 	components := []component{}
 
 	if config.Scheduler.Enabled {
 		component := component{
 			Name:                 Scheduler,
-			Queries:              metric.SchedulerQueries,
-			Specs:                metric.SchedulerSpecs,
+			Queries:              definitions[metric.TargetScheduler].Queries,
+			Specs:                definitions[metric.TargetScheduler].Specs,
 			StaticEndpointConfig: config.Scheduler.StaticEndpoint,
 			AutodiscoverConfigs:  config.Scheduler.Autodiscover,
 		}
@@ -48,8 +48,8 @@ func newComponents(config config.ControlPlane) []component {
 	if config.ETCD.Enabled {
 		component := component{
 			Name:                 Etcd,
-			Queries:              metric.EtcdQueries,
-			Specs:                metric.EtcdSpecs,
+			Queries:              definitions[metric.TargetEtcd].Queries,
+			Specs:                definitions[metric.TargetEtcd].Specs,
 			StaticEndpointConfig: config.ETCD.StaticEndpoint,
 			AutodiscoverConfigs:  config.ETCD.Autodiscover,
 		}
@@ -59,8 +59,8 @@ func newComponents(config config.ControlPlane) []component {
 	if config.ControllerManager.Enabled {
 		component := component{
 			Name:                 ControllerManager,
-			Queries:              metric.ControllerManagerQueries,
-			Specs:                metric.ControllerManagerSpecs,
+			Queries:              definitions[metric.TargetControllerManager].Queries,
+			Specs:                definitions[metric.TargetControllerManager].Specs,
 			StaticEndpointConfig: config.ControllerManager.StaticEndpoint,
 			AutodiscoverConfigs:  config.ControllerManager.Autodiscover,
 		}
@@ -70,8 +70,8 @@ func newComponents(config config.ControlPlane) []component {
 	if config.APIServer.Enabled {
 		component := component{
 			Name:                 APIServer,
-			Queries:              metric.APIServerQueries,
-			Specs:                metric.APIServerSpecs,
+			Queries:              definitions[metric.TargetAPIServer].Queries,
+			Specs:                definitions[metric.TargetAPIServer].Specs,
 			StaticEndpointConfig: config.APIServer.StaticEndpoint,
 			AutodiscoverConfigs:  config.APIServer.Autodiscover,
 		}
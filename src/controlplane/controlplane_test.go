@@ -98,7 +98,7 @@ func Test_Scraper_Autodiscover_all_cp_components(t *testing.T) {
 				t.Fatalf("error building scraper: %v", err)
 			}
 
-			if err = scraper.Run(i); err != nil {
+			if err = scraper.Run(context.Background(), i); err != nil {
 				t.Fatalf("running scraper: %v", err)
 			}
 
@@ -166,7 +166,7 @@ func Test_Scraper_Autodiscover_cp_component_after_start(t *testing.T) {
 	// create a scheduler pod on different node
 	createControlPlanePod(t, fakeK8s, controlplane.Scheduler, discoveryConfig[controlplane.Scheduler], "masterNode2")
 
-	if err = scraper.Run(i); err != nil {
+	if err = scraper.Run(context.Background(), i); err != nil {
 		t.Fatalf("running scraper shouldn't fail if autodiscovery doesn't found a matching pod: %v", err)
 	}
 
@@ -177,7 +177,7 @@ func Test_Scraper_Autodiscover_cp_component_after_start(t *testing.T) {
 
 	createControlPlanePod(t, fakeK8s, controlplane.Scheduler, discoveryConfig[controlplane.Scheduler], masterNodeName)
 
-	if err = scraper.Run(i); err != nil {
+	if err = scraper.Run(context.Background(), i); err != nil {
 		t.Fatalf("running scraper: %v", err)
 	}
 	// Call the asserter for the entities of this particular sub-test.
@@ -234,7 +234,7 @@ func Test_Scraper_external_endpoint(t *testing.T) {
 		t.Fatalf("error building scraper: %v", err)
 	}
 
-	if err = scraper.Run(i); err != nil {
+	if err = scraper.Run(context.Background(), i); err != nil {
 		t.Fatalf("running scraper: %v", err)
 	}
 	// Call the asserter for the entities of this particular sub-test.
@@ -242,7 +242,7 @@ func Test_Scraper_external_endpoint(t *testing.T) {
 
 	testServer.Close()
 
-	if err = scraper.Run(i); err == nil {
+	if err = scraper.Run(context.Background(), i); err == nil {
 		t.Fatalf("scraper should fail if static endpoint cannot be scraped")
 	}
 }
@@ -1,6 +1,7 @@
 package grouper
 
 import (
+	"context"
 	"fmt"
 
 	log "github.com/sirupsen/logrus"
@@ -19,8 +20,8 @@ type grouper struct {
 
 // Group implements Grouper interface by fetching Prometheus metrics from a given component and converting them
 // into metrics of a single entity ID, using controlplane Pod name for autodiscovered and Host for external.
-func (r *grouper) Group(specGroups definition.SpecGroups) (definition.RawGroups, *data.ErrorGroup) {
-	mFamily, err := r.client(r.queries)
+func (r *grouper) Group(ctx context.Context, specGroups definition.SpecGroups) (definition.RawGroups, *data.ErrorGroup) {
+	mFamily, err := r.client(ctx, r.queries)
 	if err != nil {
 		return nil, &data.ErrorGroup{
 			Errors: []error{
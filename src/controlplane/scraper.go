@@ -1,6 +1,7 @@
 package controlplane
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -12,13 +13,20 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 
+	"github.com/newrelic/nri-kubernetes/v3/internal/attributes"
 	"github.com/newrelic/nri-kubernetes/v3/internal/config"
 	"github.com/newrelic/nri-kubernetes/v3/internal/discovery"
+	"github.com/newrelic/nri-kubernetes/v3/internal/entities"
+	"github.com/newrelic/nri-kubernetes/v3/internal/labels"
+	"github.com/newrelic/nri-kubernetes/v3/internal/storer"
 	controlplaneClient "github.com/newrelic/nri-kubernetes/v3/src/controlplane/client"
 	"github.com/newrelic/nri-kubernetes/v3/src/controlplane/client/authenticator"
 	"github.com/newrelic/nri-kubernetes/v3/src/controlplane/client/connector"
 	"github.com/newrelic/nri-kubernetes/v3/src/controlplane/discoverer"
 	"github.com/newrelic/nri-kubernetes/v3/src/controlplane/grouper"
+	"github.com/newrelic/nri-kubernetes/v3/src/dimensional"
+	"github.com/newrelic/nri-kubernetes/v3/src/metric"
+	"github.com/newrelic/nri-kubernetes/v3/src/populator"
 	"github.com/newrelic/nri-kubernetes/v3/src/scrape"
 )
 
@@ -35,7 +43,14 @@ type Scraper struct {
 	config          *config.Config
 	k8sVersion      *version.Info
 	components      []component
+	definitions     map[string]metric.Definitions
 	informerClosers []chan<- struct{}
+	samples         *storer.InMemoryStore
+	labels          *labels.Guard
+	entities        *entities.Filter
+	attributes      *attributes.Decorator
+	dimensional     *dimensional.Emitter
+	errors          *populator.ErrorReporter
 	podDiscoverer   discoverer.PodDiscoverer
 	inClusterConfig *rest.Config
 	authenticator   authenticator.Authenticator
@@ -62,11 +77,43 @@ func WithRestConfig(restConfig *rest.Config) ScraperOpt {
 	}
 }
 
+// WithCustomAttributes returns an OptionFunc to add the custom attributes computed by decorator to the entities the
+// scraper populates.
+func WithCustomAttributes(decorator *attributes.Decorator) ScraperOpt {
+	return func(s *Scraper) error {
+		s.attributes = decorator
+
+		return nil
+	}
+}
+
+// WithDimensionalEmitter returns an OptionFunc to report the metrics the scraper populates as dimensional metrics
+// collected by emitter, instead of metric sets of the integration.
+func WithDimensionalEmitter(emitter *dimensional.Emitter) ScraperOpt {
+	return func(s *Scraper) error {
+		s.dimensional = emitter
+
+		return nil
+	}
+}
+
+// WithDefinitions returns an OptionFunc to change the specs and queries of the components from the builtin ones.
+// definitions are indexed by component name.
+func WithDefinitions(definitions map[string]metric.Definitions) ScraperOpt {
+	return func(s *Scraper) error {
+		s.definitions = definitions
+
+		return nil
+	}
+}
+
 // Close will signal internal informers to stop running.
 func (s *Scraper) Close() {
 	for _, ch := range s.informerClosers {
 		close(ch)
 	}
+
+	s.samples.StopVacuum()
 }
 
 // NewScraper initialize its internal informers and components.
@@ -76,7 +123,7 @@ func NewScraper(config *config.Config, providers Providers, options ...ScraperOp
 		config:          config,
 		Providers:       providers,
 		logger:          logutil.Discard,
-		components:      newComponents(config.ControlPlane),
+		definitions:     metric.Builtin(),
 		inClusterConfig: &rest.Config{},
 	}
 
@@ -86,6 +133,8 @@ func NewScraper(config *config.Config, providers Providers, options ...ScraperOp
 		}
 	}
 
+	s.components = newComponents(config.ControlPlane, s.definitions)
+
 	var err error
 	// TODO If this could change without a restart of the pod we should run it each time we scrape data,
 	// possibly with a reasonable cache Es: NewCachedDiscoveryClientForConfig
@@ -94,6 +143,20 @@ func NewScraper(config *config.Config, providers Providers, options ...ScraperOp
 		return nil, fmt.Errorf("fetching K8s version: %w", err)
 	}
 
+	s.labels, err = labels.NewGuard(config.Labels)
+	if err != nil {
+		return nil, fmt.Errorf("building label guard: %w", err)
+	}
+
+	s.entities, err = entities.NewFilter(config.Entities)
+	if err != nil {
+		return nil, fmt.Errorf("building entity filter: %w", err)
+	}
+
+	s.errors = populator.NewErrorReporter(config.PopulateErrors.WarnRatioChange)
+
+	s.samples = storer.NewInMemoryStore(storer.DefaultTTL, storer.DefaultInterval, s.logger)
+
 	secretListerer, informerCloser := discovery.NewNamespaceSecretListerer(discovery.SecretListererConfig{
 		Client:     s.K8s,
 		Namespaces: secretNamespaces(s.components),
@@ -133,8 +196,9 @@ func NewScraper(config *config.Config, providers Providers, options ...ScraperOp
 	return s, nil
 }
 
-// Run scraper collect the data populating the integration entities.
-func (s *Scraper) Run(i *integration.Integration) error {
+// Run scraper collect the data populating the integration entities. Cancelling ctx aborts the requests to the
+// components and skips the jobs not yet run.
+func (s *Scraper) Run(ctx context.Context, i *integration.Integration) error {
 	var jobs []*scrape.Job
 
 	for _, component := range s.components {
@@ -167,16 +231,17 @@ func (s *Scraper) Run(i *integration.Integration) error {
 	}
 
 	for _, job := range jobs {
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("running control plane jobs: %w", err)
+		}
+
 		s.logger.Debugf("Running job: %s", job.Name)
 
-		result := job.Populate(i, s.config.ClusterName, s.logger, s.k8sVersion)
+		result := job.Populate(ctx, i, s.config.ClusterName, s.logger, s.k8sVersion)
 
-		if len(result.Errors) > 0 {
-			if result.Populated {
-				s.logger.Tracef("Error populating data from %s: %v", job.Name, result.Error())
-			} else {
-				s.logger.Warnf("Error populating data from %s: %v", job.Name, result.Error())
-			}
+		// Errors of populated jobs are logged by the error reporter.
+		if len(result.Errors) > 0 && !result.Populated {
+			s.logger.Warnf("Error populating data from %s: %v", job.Name, result.Error())
 		}
 	}
 
@@ -221,7 +286,15 @@ func (s *Scraper) externalEndpoint(c component) (*scrape.Job, error) {
 		u.Host,
 	)
 
-	return scrape.NewScrapeJob(string(c.Name), grouper, c.Specs), nil
+	return scrape.NewScrapeJob(string(c.Name), grouper, c.Specs,
+		scrape.JobWithSampleStore(s.samples),
+		scrape.JobWithLabelGuard(s.labels),
+		scrape.JobWithCustomAttributes(s.attributes),
+		scrape.JobWithEntityFilter(s.entities),
+		scrape.JobWithDimensionalEmitter(s.dimensional),
+		scrape.JobWithErrorReporter(s.errors),
+		scrape.JobWithWorkers(s.config.PopulateWorkers),
+	), nil
 }
 
 // autodiscover will iterate over the Autodiscovery configs from a component and for each:
@@ -273,7 +346,15 @@ func (s *Scraper) autodiscover(c component) (*scrape.Job, error) {
 			pod.Name,
 		)
 
-		return scrape.NewScrapeJob(string(c.Name), grouper, c.Specs), nil
+		return scrape.NewScrapeJob(string(c.Name), grouper, c.Specs,
+			scrape.JobWithSampleStore(s.samples),
+			scrape.JobWithLabelGuard(s.labels),
+			scrape.JobWithCustomAttributes(s.attributes),
+			scrape.JobWithEntityFilter(s.entities),
+			scrape.JobWithDimensionalEmitter(s.dimensional),
+			scrape.JobWithErrorReporter(s.errors),
+			scrape.JobWithWorkers(s.config.PopulateWorkers),
+		), nil
 	}
 
 	s.logger.Debugf("No %q pod has been discovered", c.Name)
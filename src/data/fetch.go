@@ -1,8 +1,10 @@
 package data
 
 import (
+	"context"
+
 	"github.com/newrelic/nri-kubernetes/v3/src/definition"
 )
 
 // FetchFunc fetches data from a source.
-type FetchFunc func() (definition.RawGroups, error)
+type FetchFunc func(ctx context.Context) (definition.RawGroups, error)
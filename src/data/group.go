@@ -1,15 +1,17 @@
 package data
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/newrelic/nri-kubernetes/v3/src/definition"
 )
 
-// Grouper groups raw data by any desired label such object (pod, container...).
+// Grouper groups raw data by any desired label such object (pod, container...). Implementations should abort any
+// in-flight fetch when ctx is cancelled.
 type Grouper interface {
-	Group(definition.SpecGroups) (definition.RawGroups, *ErrorGroup)
+	Group(ctx context.Context, specGroups definition.SpecGroups) (definition.RawGroups, *ErrorGroup)
 }
 
 // ErrorGroup groups errors that can be recoverable (the execution can continue) or not
@@ -2,6 +2,9 @@ package definition
 
 import (
 	"fmt"
+	"math"
+	"strconv"
+	"time"
 )
 
 // RawValue is just any value from a raw metric.
@@ -16,6 +19,82 @@ type FetchedValue interface{}
 // FetchedValues is a map of FetchedValue indexed by metric name.
 type FetchedValues map[string]FetchedValue
 
+// TimestampedValue is a FetchedValue along with the time its source sampled it at, for sources exposing it like
+// Prometheus samples with an explicit timestamp. RATE and DELTA metrics populated from a TimestampedValue are computed
+// over the time elapsed between samples instead of between collections.
+type TimestampedValue struct {
+	Value     FetchedValue
+	Timestamp time.Time
+}
+
+// WithTimestamp returns value as a TimestampedValue sampled at timestamp, or value itself if timestamp is zero.
+func WithTimestamp(value FetchedValue, timestamp time.Time) FetchedValue {
+	if timestamp.IsZero() {
+		return value
+	}
+
+	return TimestampedValue{Value: value, Timestamp: timestamp}
+}
+
+// WithoutTimestamp returns the value held by a TimestampedValue, or value itself if it is not timestamped.
+// The values of FetchedValues are unwrapped as well, into a new FetchedValues.
+func WithoutTimestamp(value FetchedValue) FetchedValue {
+	switch v := value.(type) {
+	case TimestampedValue:
+		return v.Value
+	case FetchedValues:
+		unwrapped := make(FetchedValues, len(v))
+		for name, fv := range v {
+			unwrapped[name] = WithoutTimestamp(fv)
+		}
+		return unwrapped
+	}
+
+	return value
+}
+
+// Summary is a Prometheus summary, fetched as a whole so it can be reported as a summary where supported.
+type Summary struct {
+	SampleCount uint64
+	SampleSum   float64
+	Quantiles   []SummaryQuantile
+}
+
+// SummaryQuantile is the value of a quantile of a Summary.
+type SummaryQuantile struct {
+	Quantile float64
+	Value    float64
+}
+
+// Summaries is a map of Summary indexed by metric name.
+type Summaries map[string]Summary
+
+// Flatten returns the sample count, sum and quantiles of the summaries as FetchedValues, for outputs not supporting
+// summaries. They are named `<name>_count`, `<name>_sum` and `<name>_quantile_<quantile>`, and sums and quantiles
+// that are not finite are skipped.
+func (s Summaries) Flatten() FetchedValues {
+	values := make(FetchedValues)
+	for name, summary := range s {
+		values[name+"_count"] = summary.SampleCount
+
+		if finite(summary.SampleSum) {
+			values[name+"_sum"] = summary.SampleSum
+		}
+
+		for _, q := range summary.Quantiles {
+			if finite(q.Value) {
+				values[name+"_quantile_"+strconv.FormatFloat(q.Quantile, 'f', -1, 64)] = q.Value
+			}
+		}
+	}
+
+	return values
+}
+
+func finite(v float64) bool {
+	return !math.IsInf(v, 0) && !math.IsNaN(v)
+}
+
 // FetchFunc fetches values or values from raw metric groups.
 // Return FetchedValues if you want to prototype metrics.
 type FetchFunc func(groupLabel, entityID string, groups RawGroups) (FetchedValue, error)
@@ -57,25 +136,27 @@ func FromRaw(metricKey string) FetchFunc {
 }
 
 // Transform return a new FetchFunc that applies the transformFunc to the result of the fetchFunc passed as argument.
+// Timestamps of TimestampedValues are dropped, as they do not apply to the transformed value.
 func Transform(fetchFunc FetchFunc, transformFunc TransformFunc) FetchFunc {
 	return func(groupLabel, entityID string, groups RawGroups) (FetchedValue, error) {
 		fetchedVal, err := fetchFunc(groupLabel, entityID, groups)
 		if err != nil {
 			return nil, err
 		}
-		return transformFunc(fetchedVal)
+		return transformFunc(WithoutTimestamp(fetchedVal))
 	}
 }
 
 // TransformAndFilter return a new FetchFunc that first applies a TransformFunc to the result of the fetchFunc passed as argument.
 // It then applies the FilterFunc to the result of the TransformFunc if the transform was successfully applied.
+// As in Transform, timestamps of TimestampedValues are dropped.
 func TransformAndFilter(fetchFunc FetchFunc, transformFunc TransformFunc, filterFunc FilterFunc) FetchFunc {
 	return func(groupLabel, entityID string, groups RawGroups) (FetchedValue, error) {
 		fetchedVal, err := fetchFunc(groupLabel, entityID, groups)
 		if err != nil {
 			return nil, err
 		}
-		fetchedVal, err = transformFunc(fetchedVal)
+		fetchedVal, err = transformFunc(WithoutTimestamp(fetchedVal))
 		if err != nil {
 			return nil, err
 		}
@@ -2,8 +2,10 @@ package definition
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -79,6 +81,59 @@ func TestTransform(t *testing.T) {
 	assert.Equal(t, "METRIC_VALUE_3", v)
 }
 
+func TestTransformDropsTimestamp(t *testing.T) {
+	raw := RawGroups{
+		"group1": {
+			"entity1": {
+				"metric_name_1": WithTimestamp("metric_value_1", time.Unix(1700000000, 0)),
+			},
+		},
+	}
+
+	v, err := Transform(FromRaw("metric_name_1"),
+		func(in FetchedValue) (FetchedValue, error) {
+			return strings.ToUpper(in.(string)), nil
+		})("group1", "entity1", raw)
+	assert.NoError(t, err)
+	assert.Equal(t, "METRIC_VALUE_1", v)
+}
+
+func TestWithTimestamp(t *testing.T) {
+	ts := time.Unix(1700000000, 0)
+
+	assert.Equal(t, "value", WithTimestamp("value", time.Time{}))
+	assert.Equal(t, TimestampedValue{Value: "value", Timestamp: ts}, WithTimestamp("value", ts))
+	assert.Equal(t, "value", WithoutTimestamp(WithTimestamp("value", ts)))
+	assert.Equal(t,
+		FetchedValues{"a": 1, "b": 2},
+		WithoutTimestamp(FetchedValues{"a": WithTimestamp(1, ts), "b": 2}),
+	)
+}
+
+func TestSummaries_Flatten(t *testing.T) {
+	summaries := Summaries{
+		"duration_handler_prometheus": {
+			SampleCount: 5,
+			SampleSum:   45,
+			Quantiles:   []SummaryQuantile{{Quantile: 0.5, Value: 42}, {Quantile: 0.99, Value: 44}},
+		},
+		"duration_handler_other": {
+			SampleCount: 5,
+			SampleSum:   math.Inf(1),
+			Quantiles:   []SummaryQuantile{{Quantile: 0.5, Value: math.NaN()}, {Quantile: 0.99, Value: 44}},
+		},
+	}
+
+	assert.Equal(t, FetchedValues{
+		"duration_handler_prometheus_count":         uint64(5),
+		"duration_handler_prometheus_sum":           float64(45),
+		"duration_handler_prometheus_quantile_0.5":  float64(42),
+		"duration_handler_prometheus_quantile_0.99": float64(44),
+		"duration_handler_other_count":              uint64(5),
+		"duration_handler_other_quantile_0.99":      float64(44),
+	}, summaries.Flatten())
+}
+
 func TestTransformBypassesError(t *testing.T) {
 	raw := RawGroups{
 		"group1": {
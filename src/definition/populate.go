@@ -4,12 +4,22 @@ import (
 	"fmt"
 
 	"github.com/newrelic/infra-integrations-sdk/integration"
+	"github.com/newrelic/nri-kubernetes/v3/internal/attributes"
 	"github.com/newrelic/nri-kubernetes/v3/internal/discovery"
+	"github.com/newrelic/nri-kubernetes/v3/internal/entities"
+	"github.com/newrelic/nri-kubernetes/v3/internal/labels"
+	"github.com/newrelic/nri-kubernetes/v3/internal/storer"
+	"github.com/newrelic/nri-kubernetes/v3/src/dimensional"
 )
 
 const (
 	NamespaceGroup         = "namespace"
 	NamespaceFilteredLabel = "nrFiltered"
+	// DroppedLabelsMetric counts the label and annotation attributes of an entity dropped by the labels.Guard.
+	DroppedLabelsMetric = "nrDroppedLabels"
+	// SkippedEntitiesMetric counts the entities of a scrape skipped because their namespace or pod opted out of being
+	// scraped. It is reported by the cluster entity.
+	SkippedEntitiesMetric = "nrSkippedEntities"
 )
 
 // GuessFunc guesses from data.
@@ -23,4 +33,20 @@ type IntegrationPopulateConfig struct {
 	Groups        RawGroups
 	Specs         SpecGroups
 	Filterer      discovery.NamespaceFilterer
+	// OptOut skips the entities of namespaces and pods opting out of being scraped. If nil, none is skipped.
+	OptOut *discovery.OptOutFilter
+	// Samples keeps the TimestampedValues of previous runs, to compute RATE and DELTA metrics over the time elapsed
+	// between samples. If nil, sample timestamps are ignored.
+	Samples storer.Storer
+	// Labels limits the label and annotation attributes of entities. If nil, all of them are reported.
+	Labels *labels.Guard
+	// Attributes adds custom attributes to every entity. If nil, no custom attributes are added.
+	Attributes *attributes.Decorator
+	// Entities drops, reduces or samples entities matching its rules. If nil, every entity is reported in full.
+	Entities *entities.Filter
+	// Dimensional, if set, receives the metrics of every entity as dimensional metrics instead of Integration, which
+	// is only used for its name and version.
+	Dimensional *dimensional.Emitter
+	// Workers is the number of entities populated concurrently. If not positive, GOMAXPROCS is used.
+	Workers int
 }
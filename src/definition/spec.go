@@ -13,12 +13,18 @@ type EntityTypeGeneratorFunc func(groupLabel, rawEntityID string, g RawGroups, p
 // NamespaceGetterFunc gets the namespace.
 type NamespaceGetterFunc func(metrics RawMetrics) string
 
+// PodGetterFunc gets the name of the pod an entity belongs to.
+type PodGetterFunc func(metrics RawMetrics) string
+
 // Spec is a metric specification.
 type Spec struct {
 	Name      string
 	ValueFunc FetchFunc
 	Type      metric.SourceType
 	Optional  bool
+	// RawMetrics are the names of the raw metrics, fetched by Prometheus queries, ValueFunc reads. Queries only read by
+	// specs excluded by the configuration are not run, so every one ValueFunc may read, even as a fallback, must be listed.
+	RawMetrics []string
 }
 
 // SpecGroup represents a bunch of specs that share logic.
@@ -26,15 +32,68 @@ type SpecGroup struct {
 	IDGenerator     EntityIDGeneratorFunc
 	TypeGenerator   EntityTypeGeneratorFunc
 	NamespaceGetter NamespaceGetterFunc
-	MsTypeGuesser   GuessFunc
-	Specs           []Spec
+	// PodGetter, if set, tells the pod entities of the group belong to, so they are skipped with it if it opts out of
+	// being scraped.
+	PodGetter     PodGetterFunc
+	MsTypeGuesser GuessFunc
+	Specs         []Spec
 	// If set, creates a new event for each unique value of this label in the metrics.
 	// Useful for subgroups, e.g., ResourceQuota per resource.
 	SplitByLabel string
 	// It tells the populator which metric name holds the slice to be split.
 	// Used with subgroups
 	SliceMetricName string
+	// Computed are metrics derived from the values of Specs, populated after them in order.
+	Computed []ComputedSpec
+	// RawMetrics are the names of the raw metrics, fetched by Prometheus queries, the generators and getters of the group
+	// read, as Spec.RawMetrics. SliceMetricName is read by the group as well, and needs not be listed.
+	RawMetrics []string
+}
+
+// ComputeFunc computes a value from the numeric values of the specs of an entity, and the computed specs before it,
+// indexed by name. It returns a nil value if some of the values it needs are missing.
+type ComputeFunc func(values map[string]float64) (FetchedValue, error)
+
+// ComputedSpec is a metric computed from other metrics of the same entity.
+type ComputedSpec struct {
+	Name        string
+	ComputeFunc ComputeFunc
+	Type        metric.SourceType
 }
 
 // SpecGroups is a map of groups indexed by group name.
 type SpecGroups map[string]SpecGroup
+
+// SourceTypes returns the source type of every metric defined in the groups, indexed by the event type of the metric
+// sets of each group, as named by its MsTypeGuesser or K8sMetricSetTypeGuesser, and then by metric name.
+// Entities and metric sets do not carry source types once populated, so this allows consumers of the populated data to
+// know how each metric was computed. Different entity types can define metrics with the same name and source type.
+func (sg SpecGroups) SourceTypes() map[string]map[string]metric.SourceType {
+	sourceTypes := map[string]map[string]metric.SourceType{}
+	for groupLabel, group := range sg {
+		guesser := group.MsTypeGuesser
+		if guesser == nil {
+			guesser = K8sMetricSetTypeGuesser
+		}
+
+		eventType, err := guesser(groupLabel)
+		if err != nil {
+			continue
+		}
+
+		types := sourceTypes[eventType]
+		if types == nil {
+			types = map[string]metric.SourceType{}
+			sourceTypes[eventType] = types
+		}
+
+		for _, spec := range group.Specs {
+			types[spec.Name] = spec.Type
+		}
+		for _, spec := range group.Computed {
+			types[spec.Name] = spec.Type
+		}
+	}
+
+	return sourceTypes
+}
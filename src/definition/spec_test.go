@@ -0,0 +1,47 @@
+package definition
+
+import (
+	"testing"
+
+	"github.com/newrelic/infra-integrations-sdk/data/metric"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpecGroups_SourceTypes(t *testing.T) {
+	t.Parallel()
+
+	specs := SpecGroups{
+		"pod": {
+			Specs: []Spec{
+				{Name: "podName", Type: metric.ATTRIBUTE},
+				{Name: "restartCountDelta", Type: metric.DELTA},
+			},
+		},
+		"node": {
+			Specs: []Spec{
+				{Name: "cpuUsedCores", Type: metric.GAUGE},
+				{Name: "netRxBytesPerSecond", Type: metric.RATE},
+			},
+		},
+		"hpa": {
+			MsTypeGuesser: func(string) (string, error) { return "K8sHpaSample", nil },
+			Specs: []Spec{
+				{Name: "cpuUsedCores", Type: metric.DELTA},
+			},
+		},
+	}
+
+	assert.Equal(t, map[string]map[string]metric.SourceType{
+		"K8sPodSample": {
+			"podName":           metric.ATTRIBUTE,
+			"restartCountDelta": metric.DELTA,
+		},
+		"K8sNodeSample": {
+			"cpuUsedCores":        metric.GAUGE,
+			"netRxBytesPerSecond": metric.RATE,
+		},
+		"K8sHpaSample": {
+			"cpuUsedCores": metric.DELTA,
+		},
+	}, specs.SourceTypes())
+}
@@ -0,0 +1,212 @@
+// Package dimensional collects dimensional metrics, which are reported one by one with the attributes of their entity
+// as dimensions instead of grouped in metric sets, and writes them using version 4 of the integrations protocol.
+package dimensional
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/newrelic/infra-integrations-sdk/data/attribute"
+)
+
+// ProtocolVersion is the version of the integrations protocol supporting dimensional metrics.
+const ProtocolVersion = "4"
+
+// Types of the metrics, as understood by the agent. Cumulative ones are turned into counts and rates by the agent,
+// using the value reported in the previous payload.
+const (
+	MetricTypeGauge             = "gauge"
+	MetricTypeCumulativeCount   = "cumulative-count"
+	MetricTypeCumulativeRate    = "cumulative-rate"
+	MetricTypePrometheusSummary = "prometheus-summary"
+)
+
+// Metric is a single dimensional metric. Value is a float64, or a PrometheusSummary for MetricTypePrometheusSummary.
+type Metric struct {
+	Name       string                 `json:"name"`
+	Type       string                 `json:"type"`
+	Attributes map[string]interface{} `json:"attributes,omitempty"`
+	// Timestamp is the time the metric was sampled at, in milliseconds since the epoch. If zero, the time the payload
+	// is published at is used.
+	Timestamp int64       `json:"timestamp,omitempty"`
+	Value     interface{} `json:"value"`
+}
+
+// PrometheusSummary is the value of a Prometheus summary, with the cumulative count and sum of its observations.
+type PrometheusSummary struct {
+	SampleCount float64    `json:"sample_count"`
+	SampleSum   float64    `json:"sample_sum"`
+	Quantiles   []Quantile `json:"quantiles"`
+}
+
+// Quantile is a quantile of a PrometheusSummary.
+type Quantile struct {
+	Quantile float64 `json:"quantile"`
+	Value    float64 `json:"value"`
+}
+
+type entityMetadata struct {
+	Name        string                 `json:"name"`
+	Type        string                 `json:"type"`
+	DisplayName string                 `json:"displayName"`
+	Metadata    map[string]interface{} `json:"metadata"`
+}
+
+type common struct {
+	Timestamp  int64                  `json:"timestamp"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// Entity holds the dimensional metrics of an entity, along with the attributes common to all of them. An Entity is
+// safe for concurrent use.
+type Entity struct {
+	lock      sync.Mutex
+	Common    common                            `json:"common"`
+	Entity    entityMetadata                    `json:"entity"`
+	Metrics   []Metric                          `json:"metrics"`
+	Inventory map[string]map[string]interface{} `json:"inventory"`
+	Events    []interface{}                     `json:"events"`
+}
+
+// Name returns the name of the entity.
+func (e *Entity) Name() string {
+	return e.Entity.Name
+}
+
+// AddAttributes adds attributes shared by every metric of the entity. The displayName attribute also sets the display
+// name of the entity.
+func (e *Entity) AddAttributes(attrs ...attribute.Attribute) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for _, attr := range attrs {
+		e.Common.Attributes[attr.Key] = attr.Value
+		if attr.Key == "displayName" {
+			e.Entity.DisplayName = attr.Value
+		}
+	}
+}
+
+// AddAttribute adds an attribute shared by every metric of the entity, whose value can be of any type.
+func (e *Entity) AddAttribute(key string, value interface{}) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.Common.Attributes[key] = value
+}
+
+// AddMetric adds a metric to the entity.
+func (e *Entity) AddMetric(m Metric) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.Metrics = append(e.Metrics, m)
+}
+
+// SetInventoryItem sets the value of a field of an inventory item of the entity.
+func (e *Entity) SetInventoryItem(key, field string, value interface{}) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	item, ok := e.Inventory[key]
+	if !ok {
+		item = map[string]interface{}{}
+		e.Inventory[key] = item
+	}
+	item[field] = value
+}
+
+type integrationMetadata struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type payload struct {
+	ProtocolVersion string              `json:"protocol_version"`
+	Integration     integrationMetadata `json:"integration"`
+	Data            []*Entity           `json:"data"`
+}
+
+// Emitter collects the entities of a run and writes them to its writer when published. An Emitter is safe for
+// concurrent use.
+type Emitter struct {
+	lock     sync.Mutex
+	metadata integrationMetadata
+	writer   io.Writer
+	entities []*Entity
+	index    map[string]*Entity
+	now      func() time.Time
+}
+
+// NewEmitter creates an Emitter writing the payloads of the named integration to w.
+func NewEmitter(name, version string, w io.Writer) *Emitter {
+	return &Emitter{
+		metadata: integrationMetadata{Name: name, Version: version},
+		writer:   w,
+		index:    map[string]*Entity{},
+		now:      time.Now,
+	}
+}
+
+// Entity returns the entity with the given name and type, creating it if it does not exist yet.
+func (em *Emitter) Entity(name, entityType string) *Entity {
+	em.lock.Lock()
+	defer em.lock.Unlock()
+
+	key := entityType + ":" + name
+	if e, ok := em.index[key]; ok {
+		return e
+	}
+
+	e := &Entity{
+		Common:    common{Attributes: map[string]interface{}{}},
+		Entity:    entityMetadata{Name: name, Type: entityType, DisplayName: name, Metadata: map[string]interface{}{}},
+		Metrics:   []Metric{},
+		Inventory: map[string]map[string]interface{}{},
+		Events:    []interface{}{},
+	}
+	em.index[key] = e
+	em.entities = append(em.entities, e)
+
+	return e
+}
+
+// Entities returns the entities collected since the last time the emitter was published.
+func (em *Emitter) Entities() []*Entity {
+	em.lock.Lock()
+	defer em.lock.Unlock()
+
+	return append([]*Entity(nil), em.entities...)
+}
+
+// Publish writes the collected entities as a single payload and clears them, even if writing fails.
+func (em *Emitter) Publish() error {
+	em.lock.Lock()
+	entities := em.entities
+	em.entities = nil
+	em.index = map[string]*Entity{}
+	em.lock.Unlock()
+
+	timestamp := em.now().UnixMilli()
+	for _, e := range entities {
+		e.Common.Timestamp = timestamp
+	}
+
+	p, err := json.Marshal(payload{
+		ProtocolVersion: ProtocolVersion,
+		Integration:     em.metadata,
+		Data:            append([]*Entity{}, entities...),
+	})
+	if err != nil {
+		return fmt.Errorf("encoding payload: %w", err)
+	}
+
+	if _, err := em.writer.Write(p); err != nil {
+		return fmt.Errorf("writing payload: %w", err)
+	}
+
+	return nil
+}
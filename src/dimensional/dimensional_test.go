@@ -0,0 +1,109 @@
+package dimensional_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/newrelic/infra-integrations-sdk/data/attribute"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/src/dimensional"
+)
+
+func TestEmitter_Publish(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	emitter := dimensional.NewEmitter("com.newrelic.kubernetes", "1.2.3", &buf)
+
+	e := emitter.Entity("default_nginx", "k8s:cluster:default:pod")
+	e.AddAttributes(attribute.Attr("displayName", "nginx"), attribute.Attr("namespace", "default"))
+	e.AddAttribute("isReady", true)
+	e.AddMetric(dimensional.Metric{Name: "cpuUsedCores", Type: dimensional.MetricTypeGauge, Value: 0.5})
+	e.AddMetric(dimensional.Metric{Name: "restartCount", Type: dimensional.MetricTypeCumulativeCount, Timestamp: 1000, Value: float64(3)})
+	e.SetInventoryItem("pod", "name", "nginx")
+
+	assert.Same(t, e, emitter.Entity("default_nginx", "k8s:cluster:default:pod"))
+	require.NoError(t, emitter.Publish())
+
+	// The timestamp of the payload is the time it is published at.
+	var published struct {
+		Data []struct {
+			Common struct {
+				Timestamp int64 `json:"timestamp"`
+			} `json:"common"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &published))
+	require.Len(t, published.Data, 1)
+	timestamp := published.Data[0].Common.Timestamp
+	assert.InDelta(t, time.Now().UnixMilli(), timestamp, float64(time.Minute.Milliseconds()))
+
+	assert.JSONEq(t, `{
+		"protocol_version": "4",
+		"integration": {"name": "com.newrelic.kubernetes", "version": "1.2.3"},
+		"data": [{
+			"common": {
+				"timestamp": `+strconv.FormatInt(timestamp, 10)+`,
+				"attributes": {"displayName": "nginx", "namespace": "default", "isReady": true}
+			},
+			"entity": {"name": "default_nginx", "type": "k8s:cluster:default:pod", "displayName": "nginx", "metadata": {}},
+			"metrics": [
+				{"name": "cpuUsedCores", "type": "gauge", "value": 0.5},
+				{"name": "restartCount", "type": "cumulative-count", "timestamp": 1000, "value": 3}
+			],
+			"inventory": {"pod": {"name": "nginx"}},
+			"events": []
+		}]
+	}`, buf.String())
+
+	t.Run("clears_published_entities", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Empty(t, emitter.Entities())
+	})
+}
+
+func TestEmitter_ConcurrentEntities(t *testing.T) {
+	t.Parallel()
+
+	emitter := dimensional.NewEmitter("com.newrelic.kubernetes", "1.2.3", &bytes.Buffer{})
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			emitter.Entity("node-1", "k8s:cluster:node").AddMetric(dimensional.Metric{Name: "cpuUsedCores", Value: 1.0})
+		}()
+	}
+	wg.Wait()
+
+	entities := emitter.Entities()
+	require.Len(t, entities, 1)
+	assert.Len(t, entities[0].Metrics, 10)
+}
+
+var errWrite = errors.New("write failed")
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errWrite
+}
+
+func TestEmitter_PublishError(t *testing.T) {
+	t.Parallel()
+
+	emitter := dimensional.NewEmitter("com.newrelic.kubernetes", "1.2.3", failingWriter{})
+	emitter.Entity("node-1", "k8s:cluster:node")
+
+	assert.ErrorIs(t, emitter.Publish(), errWrite)
+	assert.Empty(t, emitter.Entities())
+}
@@ -0,0 +1,124 @@
+package prober
+
+import (
+	"sync"
+	"time"
+)
+
+// Monitor keeps track of the readiness of an endpoint during runtime.
+// A Monitor starts in the ready state, as it is meant to be created once the endpoint has been probed successfully.
+// When the endpoint is reported as unavailable, Monitor re-probes it in the background with an exponential backoff,
+// capped to the Prober timeout, until it is ready again or the Monitor is closed.
+type Monitor struct {
+	prober *Prober
+	url    string
+	stopCh chan struct{}
+
+	lock    sync.Mutex
+	status  Status
+	probing bool
+	closed  bool
+}
+
+// Status holds the readiness state of a monitored endpoint.
+type Status struct {
+	// Ready is true if the last probe against the endpoint succeeded.
+	Ready bool
+	// Since is the time the endpoint entered its current state.
+	Since time.Time
+	// LastError is the error that caused the endpoint to be considered unavailable, if any.
+	LastError error
+}
+
+// NewMonitor returns a Monitor which will use the given Prober to check url.
+func NewMonitor(prober *Prober, url string) *Monitor {
+	return &Monitor{
+		prober: prober,
+		url:    url,
+		stopCh: make(chan struct{}),
+		status: Status{
+			Ready: true,
+			Since: time.Now(),
+		},
+	}
+}
+
+// Ready returns whether the monitored endpoint is considered ready.
+func (m *Monitor) Ready() bool {
+	return m.Status().Ready
+}
+
+// Status returns a snapshot of the readiness state of the monitored endpoint.
+func (m *Monitor) Status() Status {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.status
+}
+
+// Unavailable flags the monitored endpoint as not ready because of err, and starts re-probing it in the background
+// if that is not happening already.
+func (m *Monitor) Unavailable(err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.status.Ready {
+		m.status = Status{Ready: false, Since: time.Now(), LastError: err}
+	}
+
+	if m.probing || m.closed {
+		return
+	}
+
+	m.probing = true
+	go m.reprobe(m.stopCh)
+}
+
+// Close stops re-probing the endpoint in the background. The endpoint keeps its current readiness state afterwards.
+func (m *Monitor) Close() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if m.closed {
+		return
+	}
+
+	m.closed = true
+	close(m.stopCh)
+}
+
+// reprobe probes the endpoint until it succeeds, and flags it as ready afterwards. It returns early if stopCh is
+// closed.
+func (m *Monitor) reprobe(stopCh <-chan struct{}) {
+	backoff := m.prober.backoff
+	for {
+		err := m.prober.attempt(m.url)
+		if err == nil {
+			break
+		}
+
+		m.prober.logger.Debugf("%s is still not ready: %v", m.url, err)
+		m.prober.logger.Debugf("Retrying in %s", backoff)
+
+		select {
+		case <-stopCh:
+			m.lock.Lock()
+			m.probing = false
+			m.lock.Unlock()
+			return
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > m.prober.timeout {
+			backoff = m.prober.timeout
+		}
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.prober.logger.Infof("%s is ready again", m.url)
+	m.status = Status{Ready: true, Since: time.Now()}
+	m.probing = false
+}
@@ -0,0 +1,101 @@
+package prober_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/newrelic/nri-kubernetes/v3/src/integration/prober"
+)
+
+func TestMonitor_recovers_after_endpoint_is_ready_again(t *testing.T) {
+	t.Parallel()
+
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, request *http.Request) {
+		if healthy.Load() {
+			rw.WriteHeader(http.StatusOK)
+			return
+		}
+
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	p, err := prober.New(time.Second, 50*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Error building prober: %v", err)
+	}
+
+	m := prober.NewMonitor(p, server.URL)
+	if !m.Ready() {
+		t.Fatalf("Monitor should start as ready")
+	}
+
+	writeErr := errors.New("write failed")
+	m.Unavailable(writeErr)
+
+	status := m.Status()
+	if status.Ready || !errors.Is(status.LastError, writeErr) {
+		t.Fatalf("Expected monitor to be not ready with the reported error, got %+v", status)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if m.Ready() {
+		t.Fatalf("Monitor should not be ready while the endpoint fails")
+	}
+
+	healthy.Store(true)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for !m.Ready() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Monitor did not recover after the endpoint was ready again")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestMonitor_stops_probing_when_closed(t *testing.T) {
+	t.Parallel()
+
+	var probes atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, request *http.Request) {
+		probes.Add(1)
+		rw.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	p, err := prober.New(100*time.Millisecond, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Error building prober: %v", err)
+	}
+
+	m := prober.NewMonitor(p, server.URL)
+	m.Unavailable(errors.New("write failed"))
+	time.Sleep(100 * time.Millisecond)
+
+	m.Close()
+	m.Close()
+	// Leave time for a probe which could be in flight when closing to finish.
+	time.Sleep(100 * time.Millisecond)
+
+	after := probes.Load()
+	if after == 0 {
+		t.Fatalf("Monitor should have probed the endpoint before being closed")
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	if probes.Load() != after {
+		t.Fatalf("Monitor kept probing after being closed")
+	}
+
+	m.Unavailable(errors.New("write failed"))
+	time.Sleep(100 * time.Millisecond)
+	if probes.Load() != after {
+		t.Fatalf("Monitor should not start probing once closed")
+	}
+}
@@ -18,6 +18,7 @@ type Prober struct {
 	backoff time.Duration
 	logger  *log.Logger
 	client  *http.Client
+	headers http.Header
 }
 
 var ErrProbeTimeout = errors.New("probe timed out")
@@ -41,6 +42,14 @@ func WithClient(client *http.Client) OptionFunc {
 	}
 }
 
+// WithHeaders returns an OptionFunc which tells the Prober to add the specified headers to every probe request.
+func WithHeaders(headers http.Header) OptionFunc {
+	return func(p *Prober) error {
+		p.headers = headers
+		return nil
+	}
+}
+
 // New creates a Prober that will check an endpoint every backoff seconds.
 func New(timeout, backoff time.Duration, options ...OptionFunc) (*Prober, error) {
 	p := &Prober{
@@ -95,6 +104,12 @@ func (p *Prober) attempt(url string) error {
 		return fmt.Errorf("building GET %q: %w", url, err)
 	}
 
+	for name, values := range p.headers {
+		for _, value := range values {
+			request.Header.Add(name, value)
+		}
+	}
+
 	resp, err := p.client.Do(request)
 	if err != nil {
 		return fmt.Errorf("probe attempt to infra agent (%s) failed: %w", url, err)
@@ -0,0 +1,80 @@
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// payload mirrors the top-level structure of the JSON document produced by the SDK integration, or by a
+// dimensional.Emitter, which holds the name and version of the integration in Integration instead. Entities are kept
+// as raw JSON so they can be split into several documents without being decoded and re-encoded.
+type payload struct {
+	Name               string            `json:"name,omitempty"`
+	ProtocolVersion    string            `json:"protocol_version"`
+	IntegrationVersion string            `json:"integration_version,omitempty"`
+	Integration        json.RawMessage   `json:"integration,omitempty"`
+	Data               []json.RawMessage `json:"data"`
+}
+
+// splitPayload splits an SDK payload into several valid SDK payloads, each of them holding at most maxEntities
+// entities and, when possible, at most maxBytes bytes. Entities are never split across chunks, so an entity which is
+// bigger than maxBytes on its own will be sent in a chunk by itself.
+// A zero value for maxEntities or maxBytes means no limit. If both are zero, the payload is returned untouched.
+func splitPayload(p []byte, maxEntities, maxBytes int) ([][]byte, error) {
+	if maxEntities <= 0 && maxBytes <= 0 {
+		return [][]byte{p}, nil
+	}
+
+	var full payload
+	if err := json.Unmarshal(p, &full); err != nil {
+		return nil, fmt.Errorf("decoding payload: %w", err)
+	}
+
+	header := full
+	header.Data = []json.RawMessage{}
+	empty, err := json.Marshal(header)
+	if err != nil {
+		return nil, fmt.Errorf("encoding empty payload: %w", err)
+	}
+
+	var groups [][]json.RawMessage
+	var current []json.RawMessage
+	currentSize := len(empty)
+
+	for _, entity := range full.Data {
+		// Each entity adds its own size plus a separating comma.
+		entitySize := len(entity) + 1
+
+		entitiesExceeded := maxEntities > 0 && len(current) >= maxEntities
+		bytesExceeded := maxBytes > 0 && currentSize+entitySize > maxBytes
+		if len(current) > 0 && (entitiesExceeded || bytesExceeded) {
+			groups = append(groups, current)
+			current = nil
+			currentSize = len(empty)
+		}
+
+		current = append(current, entity)
+		currentSize += entitySize
+	}
+
+	if len(current) > 0 || len(groups) == 0 {
+		groups = append(groups, current)
+	}
+
+	chunks := make([][]byte, 0, len(groups))
+	for _, group := range groups {
+		chunk := header
+		if group != nil {
+			chunk.Data = group
+		}
+
+		encoded, err := json.Marshal(chunk)
+		if err != nil {
+			return nil, fmt.Errorf("encoding payload chunk: %w", err)
+		}
+
+		chunks = append(chunks, encoded)
+	}
+
+	return chunks, nil
+}
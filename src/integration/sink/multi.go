@@ -0,0 +1,98 @@
+package sink
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/logutil"
+)
+
+// ErrAllSinksFailed is returned by Multi.Write when the payload could not be written to any of its sinks.
+var ErrAllSinksFailed = errors.New("writing to all sinks failed")
+
+// Target is a named sink. The name is used to identify the sink in logs and errors.
+type Target struct {
+	Name   string
+	Writer io.Writer
+}
+
+// Multi is an io.Writer that writes each payload to several sinks concurrently.
+// A failure in one of the sinks is logged but does not prevent the payload from being written to the others.
+type Multi struct {
+	targets []Target
+	logger  *log.Logger
+}
+
+// MultiOptionFunc is an option func for Multi.
+type MultiOptionFunc func(m *Multi)
+
+// WithMultiLogger returns a MultiOptionFunc which tells Multi to use the specified logger.
+func WithMultiLogger(logger *log.Logger) MultiOptionFunc {
+	return func(m *Multi) {
+		m.logger = logger
+	}
+}
+
+// NewMulti creates a Multi writing to the specified targets.
+func NewMulti(targets []Target, opts ...MultiOptionFunc) (*Multi, error) {
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("at least one sink is needed")
+	}
+
+	m := &Multi{
+		targets: targets,
+		logger:  logutil.Discard,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m, nil
+}
+
+// Write writes p to all the targets, and only returns an error if none of them succeeded.
+func (m *Multi) Write(p []byte) (int, error) {
+	payloads := make([][]byte, len(m.targets))
+	for i := range payloads {
+		payloads[i] = p
+	}
+
+	if err := writeConcurrently(m.logger, m.targets, payloads); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// writeConcurrently writes payloads[i] to targets[i] for every target concurrently. Failures are logged, and an
+// error is only returned if writing to all the targets failed.
+func writeConcurrently(logger *log.Logger, targets []Target, payloads [][]byte) error {
+	errs := make([]error, len(targets))
+
+	wg := sync.WaitGroup{}
+	for i, t := range targets {
+		wg.Add(1)
+		go func(i int, t Target) {
+			defer wg.Done()
+
+			if _, err := t.Writer.Write(payloads[i]); err != nil {
+				logger.Errorf("Writing payload to sink %q: %v", t.Name, err)
+				errs[i] = fmt.Errorf("sink %q: %w", t.Name, err)
+			}
+		}(i, t)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %w", ErrAllSinksFailed, errors.Join(errs...))
+}
@@ -0,0 +1,59 @@
+package sink_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/src/integration/sink"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(_ []byte) (int, error) {
+	return 0, errors.New("broken sink")
+}
+
+func Test_multi_sink_creation_fails_when_there_are_no_targets(t *testing.T) {
+	t.Parallel()
+
+	_, err := sink.NewMulti(nil)
+	assert.Error(t, err)
+}
+
+func Test_multi_sink_writes_to_healthy_sinks_when_one_fails(t *testing.T) {
+	t.Parallel()
+
+	first := &bytes.Buffer{}
+	second := &bytes.Buffer{}
+
+	m, err := sink.NewMulti([]sink.Target{
+		{Name: "first", Writer: first},
+		{Name: "broken", Writer: failingWriter{}},
+		{Name: "second", Writer: second},
+	})
+	require.NoError(t, err)
+
+	payload := []byte("some data")
+	n, err := m.Write(payload)
+	require.NoError(t, err)
+	assert.Equal(t, len(payload), n)
+	assert.Equal(t, payload, first.Bytes())
+	assert.Equal(t, payload, second.Bytes())
+}
+
+func Test_multi_sink_fails_when_all_sinks_fail(t *testing.T) {
+	t.Parallel()
+
+	m, err := sink.NewMulti([]sink.Target{
+		{Name: "broken", Writer: failingWriter{}},
+		{Name: "also-broken", Writer: failingWriter{}},
+	})
+	require.NoError(t, err)
+
+	_, err = m.Write([]byte("some data"))
+	assert.ErrorIs(t, err, sink.ErrAllSinksFailed)
+}
@@ -0,0 +1,200 @@
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/logutil"
+)
+
+// namespaceAttributes are the metric set attributes holding the namespace of an entity, in order of preference.
+var namespaceAttributes = []string{"namespace", "namespaceName"}
+
+// Route sends the entities matching its criteria to Target.
+type Route struct {
+	// EntityTypes is a list of entity types, like `pod` or `node`, this route applies to. The entity type is the last
+	// segment of the entity type reported by the integration, e.g. `pod` for `k8s:cluster:default:pod`.
+	// If empty, entities of any type match.
+	EntityTypes []string
+	// Namespaces is a list of namespaces this route applies to. Shell patterns, as supported by path.Match, are
+	// accepted. If empty, entities in any namespace, or in no namespace at all, match.
+	Namespaces []string
+	Target     Target
+}
+
+// matches returns whether an entity of the given type and namespace matches the route.
+func (r Route) matches(entityType, namespace string) bool {
+	if len(r.EntityTypes) > 0 && !contains(r.EntityTypes, entityType) {
+		return false
+	}
+
+	if len(r.Namespaces) == 0 {
+		return true
+	}
+
+	for _, pattern := range r.Namespaces {
+		if ok, _ := path.Match(pattern, namespace); ok && namespace != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Router is an io.Writer that splits each payload by entity, and writes every entity to the target of the first
+// route it matches. Entities not matching any route are written to the default target, or dropped if there is none.
+// As with Multi, failures in one target do not prevent the others from receiving their entities.
+type Router struct {
+	routes        []Route
+	defaultTarget *Target
+	logger        *log.Logger
+}
+
+// RouterOptionFunc is an option func for Router.
+type RouterOptionFunc func(r *Router)
+
+// WithRouterLogger returns a RouterOptionFunc which tells Router to use the specified logger.
+func WithRouterLogger(logger *log.Logger) RouterOptionFunc {
+	return func(r *Router) {
+		r.logger = logger
+	}
+}
+
+// WithDefaultTarget returns a RouterOptionFunc which tells Router to write entities not matching any route to target.
+func WithDefaultTarget(target Target) RouterOptionFunc {
+	return func(r *Router) {
+		r.defaultTarget = &target
+	}
+}
+
+// NewRouter creates a Router with the specified routes, which are evaluated in order.
+func NewRouter(routes []Route, opts ...RouterOptionFunc) (*Router, error) {
+	r := &Router{
+		routes: routes,
+		logger: logutil.Discard,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if len(r.routes) == 0 && r.defaultTarget == nil {
+		return nil, fmt.Errorf("at least a route or a default target is needed")
+	}
+
+	for _, route := range r.routes {
+		if route.Target.Writer == nil {
+			return nil, fmt.Errorf("route to %q has no writer", route.Target.Name)
+		}
+	}
+
+	return r, nil
+}
+
+// Write splits the SDK payload in p and writes each part to its target.
+func (r *Router) Write(p []byte) (int, error) {
+	var full payload
+	if err := json.Unmarshal(p, &full); err != nil {
+		return 0, fmt.Errorf("decoding payload: %w", err)
+	}
+
+	var targets []Target
+	entitiesByTarget := map[string][]json.RawMessage{}
+	for _, entity := range full.Data {
+		target, ok := r.route(entity)
+		if !ok {
+			continue
+		}
+
+		if _, seen := entitiesByTarget[target.Name]; !seen {
+			targets = append(targets, target)
+		}
+		entitiesByTarget[target.Name] = append(entitiesByTarget[target.Name], entity)
+	}
+
+	if len(targets) == 0 {
+		return len(p), nil
+	}
+
+	payloads := make([][]byte, 0, len(targets))
+	for _, target := range targets {
+		part := full
+		part.Data = entitiesByTarget[target.Name]
+
+		encoded, err := json.Marshal(part)
+		if err != nil {
+			return 0, fmt.Errorf("encoding payload for sink %q: %w", target.Name, err)
+		}
+
+		payloads = append(payloads, append(encoded, '\n'))
+	}
+
+	if err := writeConcurrently(r.logger, targets, payloads); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// route returns the target for the given entity, and false if the entity should be dropped.
+func (r *Router) route(entity json.RawMessage) (Target, bool) {
+	var decoded struct {
+		Metadata struct {
+			Name string `json:"name"`
+			Type string `json:"type"`
+		} `json:"entity"`
+		Metrics []map[string]interface{} `json:"metrics"`
+	}
+
+	if err := json.Unmarshal(entity, &decoded); err != nil {
+		r.logger.Warnf("Dropping entity that could not be decoded for routing: %v", err)
+		return Target{}, false
+	}
+
+	entityType := decoded.Metadata.Type
+	if i := strings.LastIndex(entityType, ":"); i >= 0 {
+		entityType = entityType[i+1:]
+	}
+
+	namespace := entityNamespace(decoded.Metrics)
+
+	for _, route := range r.routes {
+		if route.matches(entityType, namespace) {
+			return route.Target, true
+		}
+	}
+
+	if r.defaultTarget != nil {
+		return *r.defaultTarget, true
+	}
+
+	r.logger.Debugf("Dropping entity %q as it does not match any route", decoded.Metadata.Name)
+	return Target{}, false
+}
+
+// entityNamespace returns the namespace of an entity from the attributes of its metric sets.
+func entityNamespace(metricSets []map[string]interface{}) string {
+	for _, attribute := range namespaceAttributes {
+		for _, ms := range metricSets {
+			if namespace, ok := ms[attribute].(string); ok && namespace != "" {
+				return namespace
+			}
+		}
+	}
+
+	return ""
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,124 @@
+package sink_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/src/integration/sink"
+)
+
+const routerPayload = `{"name":"com.newrelic.kubernetes","protocol_version":"3","integration_version":"0.0.0","data":[
+{"entity":{"name":"k8s:cluster","type":"k8s:cluster","id_attributes":[]},"metrics":[{"event_type":"K8sClusterSample"}],"inventory":{},"events":[]},
+{"entity":{"name":"node-a","type":"k8s:cluster:node","id_attributes":[]},"metrics":[{"event_type":"K8sNodeSample"}],"inventory":{},"events":[]},
+{"entity":{"name":"pod-a","type":"k8s:cluster:team-a-prod:pod","id_attributes":[]},"metrics":[{"event_type":"K8sPodSample","namespace":"team-a-prod"}],"inventory":{},"events":[]},
+{"entity":{"name":"ns-a","type":"k8s:cluster:namespace","id_attributes":[]},"metrics":[{"event_type":"K8sNamespaceSample","namespaceName":"team-a-dev"}],"inventory":{},"events":[]},
+{"entity":{"name":"pod-b","type":"k8s:cluster:team-b:pod","id_attributes":[]},"metrics":[{"event_type":"K8sPodSample","namespace":"team-b"}],"inventory":{},"events":[]}
+]}`
+
+func entityNames(t *testing.T, written []byte) []string {
+	t.Helper()
+
+	if len(written) == 0 {
+		return nil
+	}
+
+	var p struct {
+		Name string `json:"name"`
+		Data []struct {
+			Entity struct {
+				Name string `json:"name"`
+			} `json:"entity"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(written, &p))
+	assert.Equal(t, "com.newrelic.kubernetes", p.Name)
+
+	names := make([]string, 0, len(p.Data))
+	for _, e := range p.Data {
+		names = append(names, e.Entity.Name)
+	}
+
+	return names
+}
+
+func Test_router_sends_entities_to_matching_routes(t *testing.T) {
+	t.Parallel()
+
+	teamA := &bytes.Buffer{}
+	platform := &bytes.Buffer{}
+	fallback := &bytes.Buffer{}
+
+	r, err := sink.NewRouter(
+		[]sink.Route{
+			{
+				Namespaces: []string{"team-a-*"},
+				Target:     sink.Target{Name: "team-a", Writer: teamA},
+			},
+			{
+				EntityTypes: []string{"cluster", "node"},
+				Target:      sink.Target{Name: "platform", Writer: platform},
+			},
+		},
+		sink.WithDefaultTarget(sink.Target{Name: "default", Writer: fallback}),
+	)
+	require.NoError(t, err)
+
+	_, err = r.Write([]byte(routerPayload))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"pod-a", "ns-a"}, entityNames(t, teamA.Bytes()))
+	assert.Equal(t, []string{"k8s:cluster", "node-a"}, entityNames(t, platform.Bytes()))
+	assert.Equal(t, []string{"pod-b"}, entityNames(t, fallback.Bytes()))
+}
+
+func Test_router_drops_unmatched_entities_without_default_target(t *testing.T) {
+	t.Parallel()
+
+	platform := &bytes.Buffer{}
+
+	r, err := sink.NewRouter([]sink.Route{
+		{
+			EntityTypes: []string{"node"},
+			Target:      sink.Target{Name: "platform", Writer: platform},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = r.Write([]byte(routerPayload))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"node-a"}, entityNames(t, platform.Bytes()))
+}
+
+func Test_router_keeps_writing_when_one_target_fails(t *testing.T) {
+	t.Parallel()
+
+	platform := &bytes.Buffer{}
+
+	r, err := sink.NewRouter(
+		[]sink.Route{
+			{
+				EntityTypes: []string{"node"},
+				Target:      sink.Target{Name: "platform", Writer: platform},
+			},
+		},
+		sink.WithDefaultTarget(sink.Target{Name: "broken", Writer: failingWriter{}}),
+	)
+	require.NoError(t, err)
+
+	_, err = r.Write([]byte(routerPayload))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"node-a"}, entityNames(t, platform.Bytes()))
+}
+
+func Test_router_creation_fails_without_routes_nor_default_target(t *testing.T) {
+	t.Parallel()
+
+	_, err := sink.NewRouter(nil)
+	assert.Error(t, err)
+}
@@ -2,8 +2,11 @@ package sink
 
 import (
 	"bytes"
+	"compress/gzip"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 
 	log "github.com/sirupsen/logrus"
@@ -15,21 +18,58 @@ const (
 	DefaultAgentForwarderPath = "/v1/data"
 )
 
+// ErrPaused is returned by HTTPSink.Write when the sink is not ready to receive data, so the payload has been dropped.
+var ErrPaused = errors.New("sink is paused until it is ready again")
+
+// statusError is returned when the sink answers a request with an unexpected status code.
+type statusError struct {
+	code int
+}
+
+func (e statusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d, expected: %d", e.code, http.StatusNoContent)
+}
+
 // Doer is the interface that HTTPSink client should satisfy.
 type Doer interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+// Readiness is the interface that HTTPSink uses to know whether the remote end is ready to receive data, and to report
+// when it seems not to be.
+type Readiness interface {
+	Ready() bool
+	Unavailable(err error)
+}
+
 // HTTPSink holds the configuration of the HTTP sink used by the integration.
 type HTTPSink struct {
-	url    string
-	client Doer
+	url         string
+	client      Doer
+	maxEntities int
+	maxBytes    int
+	gzip        bool
+	headers     http.Header
+	readiness   Readiness
 }
 
 // HTTPSinkOptions holds the configuration of the HTTP sink used by the integration.
 type HTTPSinkOptions struct {
 	URL    string
 	Client Doer
+	// MaxEntitiesPerRequest splits payloads so that no request holds more than this number of entities.
+	// Zero means no limit.
+	MaxEntitiesPerRequest int
+	// MaxBytesPerRequest splits payloads so that requests are, whenever possible, smaller than this size in bytes.
+	// Entities are never split, so a single entity bigger than this limit is sent on its own. Zero means no limit.
+	MaxBytesPerRequest int
+	// Gzip enables gzip compression of the request bodies.
+	Gzip bool
+	// Headers are added to every request.
+	Headers http.Header
+	// Readiness, if set, is checked before every write. While it is not ready, writes are skipped and ErrPaused is
+	// returned. Writes failing because the sink could not be reached or answered with a 5xx status are reported to it.
+	Readiness Readiness
 }
 
 // New initialize HTTPSink struct.
@@ -42,32 +82,115 @@ func New(options HTTPSinkOptions) (*HTTPSink, error) {
 		return nil, fmt.Errorf("url cannot be empty")
 	}
 
+	if options.MaxEntitiesPerRequest < 0 {
+		return nil, fmt.Errorf("max entities per request cannot be negative")
+	}
+
+	if options.MaxBytesPerRequest < 0 {
+		return nil, fmt.Errorf("max bytes per request cannot be negative")
+	}
+
 	return &HTTPSink{
-		url:    options.URL,
-		client: options.Client,
+		url:         options.URL,
+		client:      options.Client,
+		maxEntities: options.MaxEntitiesPerRequest,
+		maxBytes:    options.MaxBytesPerRequest,
+		gzip:        options.Gzip,
+		headers:     options.Headers,
+		readiness:   options.Readiness,
 	}, nil
 }
 
 // Write is the function signature needed by the infrastructure SDK package.
+// If limits are configured, the payload is split in several requests which are sent sequentially. Write fails as soon
+// as one of them fails.
 func (h HTTPSink) Write(p []byte) (n int, err error) {
-	request, err := http.NewRequest("POST", h.url, bytes.NewBuffer(p))
+	if h.readiness != nil && !h.readiness.Ready() {
+		return 0, ErrPaused
+	}
+
+	chunks, err := splitPayload(p, h.maxEntities, h.maxBytes)
 	if err != nil {
-		return 0, fmt.Errorf("preparing request: %w", err)
+		return 0, fmt.Errorf("splitting payload: %w", err)
+	}
+
+	for i, chunk := range chunks {
+		if err := h.send(chunk); err != nil {
+			err = fmt.Errorf("sending chunk %d/%d: %w", i+1, len(chunks), err)
+			if h.readiness != nil && unavailable(err) {
+				h.readiness.Unavailable(err)
+				return 0, fmt.Errorf("%w: %w", ErrPaused, err)
+			}
+
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// send performs a single request against the sink URL with the given body.
+func (h HTTPSink) send(body []byte) error {
+	if h.gzip {
+		compressed, err := compress(body)
+		if err != nil {
+			return fmt.Errorf("compressing body: %w", err)
+		}
+		body = compressed
+	}
+
+	request, err := http.NewRequest("POST", h.url, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("preparing request: %w", err)
+	}
+	for name, values := range h.headers {
+		for _, value := range values {
+			request.Header.Add(name, value)
+		}
 	}
 	request.Header.Set("Content-Type", "application/json")
+	if h.gzip {
+		request.Header.Set("Content-Encoding", "gzip")
+	}
 
 	resp, err := h.client.Do(request)
 	if err != nil {
-		return 0, fmt.Errorf("performing HTTP request: %w", err)
+		return fmt.Errorf("performing HTTP request: %w", err)
 	}
 
 	defer cleanBody(resp)
 
 	if resp.StatusCode != http.StatusNoContent {
-		return 0, fmt.Errorf("unexpected status code: %d, expected: %d", resp.StatusCode, http.StatusNoContent)
+		return statusError{code: resp.StatusCode}
 	}
 
-	return len(p), nil
+	return nil
+}
+
+// unavailable returns whether err means the sink is not able to receive data at the moment, that is, it could not be
+// reached or failed with a server error. Other errors, like payloads rejected with a 4xx, are not solved by waiting.
+func unavailable(err error) bool {
+	var statusErr statusError
+	if errors.As(err, &statusErr) {
+		return statusErr.code >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func compress(p []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	if _, err := gz.Write(p); err != nil {
+		return nil, err
+	}
+
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
 }
 
 func cleanBody(resp *http.Response) {
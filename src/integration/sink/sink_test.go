@@ -1,10 +1,15 @@
 package sink_test
 
 import (
+	"compress/gzip"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -31,6 +36,12 @@ func Test_http_Sink_creation_fails_when_there_is(t *testing.T) {
 		"no_url": func(s *sink.HTTPSinkOptions) {
 			s.URL = ""
 		},
+		"negative_max_entities": func(s *sink.HTTPSinkOptions) {
+			s.MaxEntitiesPerRequest = -1
+		},
+		"negative_max_bytes": func(s *sink.HTTPSinkOptions) {
+			s.MaxBytesPerRequest = -1
+		},
 	}
 
 	for testName, modifyFunc := range testCases {
@@ -175,3 +186,224 @@ func defaultPesterClient(t *testing.T) *pester.Client {
 
 	return c
 }
+
+func Test_http_sink_splits_payload_respecting_entity_boundaries(t *testing.T) {
+	t.Parallel()
+
+	entities := []string{
+		`{"entity":{"name":"a"},"metrics":[]}`,
+		`{"entity":{"name":"b"},"metrics":[]}`,
+		`{"entity":{"name":"c"},"metrics":[]}`,
+		`{"entity":{"name":"d"},"metrics":[]}`,
+		`{"entity":{"name":"e"},"metrics":[]}`,
+	}
+	payload := fmt.Sprintf(
+		`{"name":"com.newrelic.kubernetes","protocol_version":"3","integration_version":"0.0.0","data":[%s]}`+"\n",
+		strings.Join(entities, ","),
+	)
+
+	testCases := map[string]struct {
+		options        sink.HTTPSinkOptions
+		expectedChunks []int
+	}{
+		"no_limits": {
+			expectedChunks: []int{5},
+		},
+		"by_entity_count": {
+			options:        sink.HTTPSinkOptions{MaxEntitiesPerRequest: 2},
+			expectedChunks: []int{2, 2, 1},
+		},
+		"by_bytes": {
+			options:        sink.HTTPSinkOptions{MaxBytesPerRequest: 210},
+			expectedChunks: []int{3, 2},
+		},
+		"by_bytes_smaller_than_an_entity": {
+			options:        sink.HTTPSinkOptions{MaxBytesPerRequest: 1},
+			expectedChunks: []int{1, 1, 1, 1, 1},
+		},
+		"gzipped": {
+			options:        sink.HTTPSinkOptions{MaxEntitiesPerRequest: 4, Gzip: true},
+			expectedChunks: []int{4, 1},
+		},
+	}
+
+	for testName, testCase := range testCases {
+		tc := testCase
+		t.Run(testName, func(t *testing.T) {
+			t.Parallel()
+
+			var received []int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				body := io.Reader(req.Body)
+				if tc.options.Gzip {
+					assert.Equal(t, "gzip", req.Header.Get("Content-Encoding"))
+					gz, err := gzip.NewReader(req.Body)
+					require.NoError(t, err)
+					body = gz
+				}
+
+				var chunk struct {
+					Name string            `json:"name"`
+					Data []json.RawMessage `json:"data"`
+				}
+				require.NoError(t, json.NewDecoder(body).Decode(&chunk))
+				assert.Equal(t, "com.newrelic.kubernetes", chunk.Name)
+				received = append(received, len(chunk.Data))
+
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			t.Cleanup(server.Close)
+
+			options := tc.options
+			options.URL = server.URL
+			options.Client = server.Client()
+
+			h, err := sink.New(options)
+			require.NoError(t, err)
+
+			n, err := h.Write([]byte(payload))
+			require.NoError(t, err)
+			assert.Equal(t, len(payload), n)
+			assert.Equal(t, tc.expectedChunks, received)
+		})
+	}
+}
+
+func Test_http_sink_splits_dimensional_payload_keeping_header(t *testing.T) {
+	t.Parallel()
+
+	payload := `{"protocol_version":"4","integration":{"name":"com.newrelic.kubernetes","version":"0.0.0"},` +
+		`"data":[{"entity":{"name":"a"},"metrics":[]},{"entity":{"name":"b"},"metrics":[]}]}`
+
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		received = append(received, string(body))
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	h, err := sink.New(sink.HTTPSinkOptions{URL: server.URL, Client: server.Client(), MaxEntitiesPerRequest: 1})
+	require.NoError(t, err)
+
+	_, err = h.Write([]byte(payload))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`{"protocol_version":"4","integration":{"name":"com.newrelic.kubernetes","version":"0.0.0"},"data":[{"entity":{"name":"a"},"metrics":[]}]}`,
+		`{"protocol_version":"4","integration":{"name":"com.newrelic.kubernetes","version":"0.0.0"},"data":[{"entity":{"name":"b"},"metrics":[]}]}`,
+	}, received)
+}
+
+func Test_http_sink_fails_splitting_invalid_payload(t *testing.T) {
+	t.Parallel()
+
+	options := getHTTPSinkOptions(t)
+	options.MaxEntitiesPerRequest = 1
+
+	h, err := sink.New(options)
+	require.NoError(t, err)
+
+	_, err = h.Write([]byte("random data"))
+	assert.Error(t, err)
+}
+
+type fakeReadiness struct {
+	ready       bool
+	unavailable []error
+}
+
+func (f *fakeReadiness) Ready() bool {
+	return f.ready
+}
+
+func (f *fakeReadiness) Unavailable(err error) {
+	f.ready = false
+	f.unavailable = append(f.unavailable, err)
+}
+
+func Test_http_sink_pauses_when_not_ready(t *testing.T) {
+	t.Parallel()
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	readiness := &fakeReadiness{ready: true}
+	h, err := sink.New(sink.HTTPSinkOptions{
+		URL:       server.URL,
+		Client:    server.Client(),
+		Readiness: readiness,
+	})
+	require.NoError(t, err)
+
+	_, err = h.Write([]byte("random data"))
+	assert.ErrorIs(t, err, sink.ErrPaused)
+	assert.Len(t, readiness.unavailable, 1)
+	assert.Equal(t, 1, requests)
+
+	_, err = h.Write([]byte("random data"))
+	assert.ErrorIs(t, err, sink.ErrPaused)
+	assert.Equal(t, 1, requests, "no requests should be made while the sink is paused")
+}
+
+func Test_http_sink_pauses_only_when_unavailable(t *testing.T) {
+	t.Parallel()
+
+	closedServer := httptest.NewServer(http.NotFoundHandler())
+	closedServer.Close()
+
+	testCases := map[string]struct {
+		status      int
+		url         string
+		shouldPause bool
+	}{
+		"server_error":           {status: http.StatusInternalServerError, shouldPause: true},
+		"service_unavailable":    {status: http.StatusServiceUnavailable, shouldPause: true},
+		"connection_refused":     {url: closedServer.URL, shouldPause: true},
+		"bad_request":            {status: http.StatusBadRequest},
+		"request_entity_too_big": {status: http.StatusRequestEntityTooLarge},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(tc.status)
+			}))
+			t.Cleanup(server.Close)
+
+			url := server.URL
+			if tc.url != "" {
+				url = tc.url
+			}
+
+			readiness := &fakeReadiness{ready: true}
+			h, err := sink.New(sink.HTTPSinkOptions{
+				URL:       url,
+				Client:    server.Client(),
+				Readiness: readiness,
+			})
+			require.NoError(t, err)
+
+			_, err = h.Write([]byte("random data"))
+			require.Error(t, err)
+
+			if tc.shouldPause {
+				assert.ErrorIs(t, err, sink.ErrPaused)
+				assert.Len(t, readiness.unavailable, 1)
+				return
+			}
+
+			assert.NotErrorIs(t, err, sink.ErrPaused)
+			assert.Empty(t, readiness.unavailable)
+			assert.True(t, readiness.Ready())
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package sink
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+
+	sdkMetric "github.com/newrelic/infra-integrations-sdk/data/metric"
+)
+
+const (
+	// DefaultStatsDAddress is the address of the StatsD aggregator used when none is specified.
+	DefaultStatsDAddress = "localhost:8125"
+	// DefaultStatsDMaxPacketSize is the default maximum size of the UDP datagrams sent to the aggregator. It is chosen
+	// so that datagrams fit in the MTU of most networks.
+	DefaultStatsDMaxPacketSize = 1432
+
+	eventTypeKey = "event_type"
+)
+
+var (
+	// statsdReplacer replaces characters that have special meaning in DogStatsD metric and tag names.
+	statsdReplacer = strings.NewReplacer(":", "_", "|", "_", "@", "_", ",", "_", "#", "_", "\n", "_")
+	// statsdTagValueReplacer replaces characters that have special meaning in DogStatsD tag values.
+	statsdTagValueReplacer = strings.NewReplacer(",", "_", "|", "_", "\n", "_")
+)
+
+// StatsDSink is an io.Writer that converts SDK payloads into DogStatsD metrics sent over UDP.
+// Numeric metrics are sent as gauges, except for those whose source type is DELTA or PDELTA, which are sent as
+// counters. RATE and PRATE metrics are already per-second rates, so they are sent as gauges too. String attributes are
+// sent as tags.
+type StatsDSink struct {
+	conn          net.Conn
+	prefix        string
+	tagAllowList  map[string]bool
+	maxPacketSize int
+	sourceTypes   map[string]map[string]sdkMetric.SourceType
+}
+
+// StatsDSinkOptions holds the configuration of the StatsD sink.
+type StatsDSinkOptions struct {
+	// Address is the host:port of the StatsD aggregator. Defaults to DefaultStatsDAddress.
+	Address string
+	// Prefix is prepended to the name of every metric.
+	Prefix string
+	// TagAllowList restricts which attributes are sent as tags. If empty, all attributes are sent.
+	TagAllowList []string
+	// MaxPacketSize is the maximum size of each UDP datagram. Defaults to DefaultStatsDMaxPacketSize.
+	MaxPacketSize int
+	// SourceTypes holds the source type of each metric, indexed by the event type of its metric set and then by name.
+	// Metrics not present are sent as gauges.
+	SourceTypes map[string]map[string]sdkMetric.SourceType
+}
+
+// NewStatsD creates a StatsDSink sending metrics to the configured address.
+func NewStatsD(options StatsDSinkOptions) (*StatsDSink, error) {
+	if options.Address == "" {
+		options.Address = DefaultStatsDAddress
+	}
+
+	if options.MaxPacketSize == 0 {
+		options.MaxPacketSize = DefaultStatsDMaxPacketSize
+	}
+
+	if options.MaxPacketSize < 0 {
+		return nil, fmt.Errorf("max packet size cannot be negative")
+	}
+
+	conn, err := net.Dial("udp", options.Address)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %q: %w", options.Address, err)
+	}
+
+	var allowList map[string]bool
+	if len(options.TagAllowList) > 0 {
+		allowList = make(map[string]bool, len(options.TagAllowList))
+		for _, tag := range options.TagAllowList {
+			allowList[tag] = true
+		}
+	}
+
+	return &StatsDSink{
+		conn:          conn,
+		prefix:        options.Prefix,
+		tagAllowList:  allowList,
+		maxPacketSize: options.MaxPacketSize,
+		sourceTypes:   options.SourceTypes,
+	}, nil
+}
+
+// Write decodes the SDK payload in p and sends every numeric metric of every metric set to the aggregator.
+func (s *StatsDSink) Write(p []byte) (int, error) {
+	var full struct {
+		Data []struct {
+			Metrics []map[string]interface{} `json:"metrics"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(p, &full); err != nil {
+		return 0, fmt.Errorf("decoding payload: %w", err)
+	}
+
+	packet := &bytes.Buffer{}
+	for _, entity := range full.Data {
+		for _, ms := range entity.Metrics {
+			for _, line := range s.lines(ms) {
+				if packet.Len() > 0 && packet.Len()+1+len(line) > s.maxPacketSize {
+					if err := s.flush(packet); err != nil {
+						return 0, err
+					}
+				}
+
+				if packet.Len() > 0 {
+					packet.WriteByte('\n')
+				}
+				packet.WriteString(line)
+			}
+		}
+	}
+
+	if err := s.flush(packet); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// lines returns the DogStatsD lines corresponding to the numeric metrics of a metric set, sorted by name.
+func (s *StatsDSink) lines(ms map[string]interface{}) []string {
+	eventType, _ := ms[eventTypeKey].(string)
+	tags := s.tags(ms)
+
+	var lines []string
+	for name, value := range ms {
+		number, ok := value.(float64)
+		if !ok {
+			continue
+		}
+
+		metricType := "g"
+		switch s.sourceTypes[eventType][name] {
+		case sdkMetric.DELTA, sdkMetric.PDELTA:
+			metricType = "c"
+		}
+
+		metricName := name
+		if eventType != "" {
+			metricName = eventType + "." + name
+		}
+
+		line := fmt.Sprintf("%s%s:%s|%s", s.prefix, statsdReplacer.Replace(metricName), strconv.FormatFloat(number, 'f', -1, 64), metricType)
+		if tags != "" {
+			line += "|#" + tags
+		}
+
+		lines = append(lines, line)
+	}
+
+	sort.Strings(lines)
+	return lines
+}
+
+// tags returns the string attributes of a metric set formatted as DogStatsD tags, filtered by the allow list.
+func (s *StatsDSink) tags(ms map[string]interface{}) string {
+	var tags []string
+	for name, value := range ms {
+		str, ok := value.(string)
+		if !ok || name == eventTypeKey {
+			continue
+		}
+
+		if s.tagAllowList != nil && !s.tagAllowList[name] {
+			continue
+		}
+
+		tags = append(tags, statsdReplacer.Replace(name)+":"+statsdTagValueReplacer.Replace(str))
+	}
+
+	sort.Strings(tags)
+	return strings.Join(tags, ",")
+}
+
+// flush sends the contents of packet as a single datagram and resets it.
+func (s *StatsDSink) flush(packet *bytes.Buffer) error {
+	if packet.Len() == 0 {
+		return nil
+	}
+
+	defer packet.Reset()
+
+	if _, err := s.conn.Write(packet.Bytes()); err != nil {
+		return fmt.Errorf("sending metrics: %w", err)
+	}
+
+	return nil
+}
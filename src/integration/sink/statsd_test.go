@@ -0,0 +1,151 @@
+package sink_test
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	sdkMetric "github.com/newrelic/infra-integrations-sdk/data/metric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/src/integration/sink"
+)
+
+const statsdPayload = `{"name":"com.newrelic.kubernetes","protocol_version":"3","integration_version":"0.0.0","data":[
+{"entity":{"name":"pod-a","type":"k8s:cluster:namespace:pod","id_attributes":[]},"metrics":[
+{"event_type":"K8sPodSample","podName":"pod-a","namespace":"default","cpuUsedCores":0.5,"netRxBytesPerSecond":100,"restartCountDelta":2}
+],"inventory":{},"events":[]}
+]}`
+
+func runUDPListener(t *testing.T) (string, <-chan string) {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	packets := make(chan string, 100)
+	go func() {
+		buf := make([]byte, 65536)
+		for {
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				close(packets)
+				return
+			}
+			packets <- string(buf[:n])
+		}
+	}()
+
+	return conn.LocalAddr().String(), packets
+}
+
+func readStatsDLines(t *testing.T, packets <-chan string, expected int) []string {
+	t.Helper()
+
+	var lines []string
+	timeout := time.After(2 * time.Second)
+	for len(lines) < expected {
+		select {
+		case packet := <-packets:
+			lines = append(lines, strings.Split(packet, "\n")...)
+		case <-timeout:
+			t.Fatalf("timed out waiting for %d lines, got %v", expected, lines)
+		}
+	}
+
+	return lines
+}
+
+func Test_statsd_sink_sends_gauges_and_counters(t *testing.T) {
+	t.Parallel()
+
+	address, packets := runUDPListener(t)
+
+	s, err := sink.NewStatsD(sink.StatsDSinkOptions{
+		Address:      address,
+		Prefix:       "k8s.",
+		TagAllowList: []string{"namespace", "podName"},
+		SourceTypes: map[string]map[string]sdkMetric.SourceType{
+			"K8sPodSample": {
+				"cpuUsedCores":        sdkMetric.GAUGE,
+				"netRxBytesPerSecond": sdkMetric.RATE,
+				"restartCountDelta":   sdkMetric.DELTA,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = s.Write([]byte(statsdPayload))
+	require.NoError(t, err)
+
+	lines := readStatsDLines(t, packets, 3)
+	assert.ElementsMatch(t, []string{
+		"k8s.K8sPodSample.cpuUsedCores:0.5|g|#namespace:default,podName:pod-a",
+		"k8s.K8sPodSample.netRxBytesPerSecond:100|g|#namespace:default,podName:pod-a",
+		"k8s.K8sPodSample.restartCountDelta:2|c|#namespace:default,podName:pod-a",
+	}, lines)
+}
+
+func Test_statsd_sink_looks_up_source_types_by_event_type(t *testing.T) {
+	t.Parallel()
+
+	address, packets := runUDPListener(t)
+
+	s, err := sink.NewStatsD(sink.StatsDSinkOptions{
+		Address: address,
+		SourceTypes: map[string]map[string]sdkMetric.SourceType{
+			"K8sNodeSample": {
+				"restartCountDelta": sdkMetric.DELTA,
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	_, err = s.Write([]byte(statsdPayload))
+	require.NoError(t, err)
+
+	lines := readStatsDLines(t, packets, 3)
+	assert.Contains(t, lines, "K8sPodSample.restartCountDelta:2|g|#namespace:default,podName:pod-a")
+}
+
+func Test_statsd_sink_splits_packets(t *testing.T) {
+	t.Parallel()
+
+	address, packets := runUDPListener(t)
+
+	s, err := sink.NewStatsD(sink.StatsDSinkOptions{
+		Address:       address,
+		TagAllowList:  []string{"podName"},
+		MaxPacketSize: 60,
+	})
+	require.NoError(t, err)
+
+	_, err = s.Write([]byte(statsdPayload))
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		select {
+		case packet := <-packets:
+			assert.LessOrEqual(t, len(packet), 60)
+			assert.NotContains(t, packet, "\n")
+			assert.Contains(t, packet, "|g|#podName:pod-a")
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for packet %d", i)
+		}
+	}
+}
+
+func Test_statsd_sink_fails_on_invalid_payload(t *testing.T) {
+	t.Parallel()
+
+	address, _ := runUDPListener(t)
+
+	s, err := sink.NewStatsD(sink.StatsDSinkOptions{Address: address})
+	require.NoError(t, err)
+
+	_, err = s.Write([]byte("random data"))
+	assert.Error(t, err)
+}
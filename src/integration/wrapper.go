@@ -1,13 +1,20 @@
 package integration
 
 import (
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"slices"
 	"strconv"
+	"time"
 
+	sdkMetric "github.com/newrelic/infra-integrations-sdk/data/metric"
 	sdk "github.com/newrelic/infra-integrations-sdk/integration"
 	"github.com/sethgrid/pester"
 	log "github.com/sirupsen/logrus"
@@ -15,6 +22,7 @@ import (
 	"github.com/newrelic/nri-kubernetes/v3/internal/config"
 	"github.com/newrelic/nri-kubernetes/v3/internal/logutil"
 	"github.com/newrelic/nri-kubernetes/v3/internal/storer"
+	"github.com/newrelic/nri-kubernetes/v3/src/dimensional"
 	"github.com/newrelic/nri-kubernetes/v3/src/integration/prober"
 	"github.com/newrelic/nri-kubernetes/v3/src/integration/sink"
 )
@@ -26,7 +34,13 @@ type Wrapper struct {
 	sdkIntegration *sdk.Integration
 	logger         *log.Logger
 	metadata       Metadata
-	sink           io.Writer
+	sinks          []sink.Target
+	// monitors of the readiness of HTTP sinks, indexed by the position of their sink in sinks, as sink names are not
+	// unique unless set with Named.
+	monitors     map[int]*prober.Monitor
+	routes       []config.SinkRoute
+	defaultRoute string
+	closers      []func() error
 }
 
 // OptionFunc is an option func for the Wrapper.
@@ -47,34 +61,133 @@ func WithMetadata(metadata Metadata) OptionFunc {
 	}
 }
 
+// Named applies opt, which must configure exactly one sink, and gives that sink the specified name so it can be
+// referenced by routes. An empty name leaves the default one untouched.
+func Named(name string, opt OptionFunc) OptionFunc {
+	return func(iw *Wrapper) error {
+		sinks := len(iw.sinks)
+		if err := opt(iw); err != nil {
+			return err
+		}
+
+		if len(iw.sinks) != sinks+1 {
+			return fmt.Errorf("option for sink %q did not configure exactly one sink", name)
+		}
+
+		if name == "" {
+			return nil
+		}
+
+		iw.sinks[sinks].Name = name
+
+		return nil
+	}
+}
+
+// WithRoutes configures the wrapper to write each entity only to the sink named by the first route it matches, or to
+// the defaultRoute sink if none matches. Sinks must be named using Named.
+func WithRoutes(routes []config.SinkRoute, defaultRoute string) OptionFunc {
+	return func(iw *Wrapper) error {
+		iw.routes = routes
+		iw.defaultRoute = defaultRoute
+		return nil
+	}
+}
+
+// WithStdoutSink configures the wrapper to write metrics to stdout.
+func WithStdoutSink() OptionFunc {
+	return func(iw *Wrapper) error {
+		iw.sinks = append(iw.sinks, sink.Target{Name: config.SinkTypeStdout, Writer: os.Stdout})
+		return nil
+	}
+}
+
+// WithFileSink configures the wrapper to append metrics to the file specified in the config.
+func WithFileSink(sinkConfig config.FileSink) OptionFunc {
+	return func(iw *Wrapper) error {
+		if sinkConfig.Path == "" {
+			return fmt.Errorf("file sink path cannot be empty")
+		}
+
+		f, err := os.OpenFile(sinkConfig.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+		if err != nil {
+			return fmt.Errorf("opening file sink: %w", err)
+		}
+
+		iw.sinks = append(iw.sinks, sink.Target{Name: fmt.Sprintf("%s:%s", config.SinkTypeFile, sinkConfig.Path), Writer: f})
+		iw.closers = append(iw.closers, func() error {
+			return errors.Join(f.Sync(), f.Close())
+		})
+		return nil
+	}
+}
+
+// WithStatsDSink configures the wrapper to send metrics to a StatsD aggregator.
+// sourceTypes is used to tell counters apart from gauges, as source types are not present in the SDK payload.
+func WithStatsDSink(sinkConfig config.StatsDSink, sourceTypes map[string]map[string]sdkMetric.SourceType) OptionFunc {
+	return func(iw *Wrapper) error {
+		s, err := sink.NewStatsD(sink.StatsDSinkOptions{
+			Address:       sinkConfig.Address,
+			Prefix:        sinkConfig.Prefix,
+			TagAllowList:  sinkConfig.TagAllowList,
+			MaxPacketSize: sinkConfig.MaxPacketSize,
+			SourceTypes:   sourceTypes,
+		})
+		if err != nil {
+			return fmt.Errorf("creating StatsD sink: %w", err)
+		}
+
+		iw.sinks = append(iw.sinks, sink.Target{Name: fmt.Sprintf("%s:%s", config.SinkTypeStatsD, sinkConfig.Address), Writer: s})
+		return nil
+	}
+}
+
 // WithHTTPSink configures the wrapper to use an HTTP Sink for metrics.
-// If this option is not specified, Wrapper will configure the integration.Integration to sink metrics to stdout.
+// If no sink option is specified, Wrapper will configure the integration.Integration to sink metrics to stdout.
+// Sink options can be specified more than once, in which case metrics are written to all of them.
 func WithHTTPSink(sinkConfig config.HTTPSink) OptionFunc {
 	return func(iw *Wrapper) error {
-		scheme := "http"
 		client := http.DefaultClient
 		var err error
 
 		if sinkConfig.TLS.Enabled {
-			scheme = "https"
 			client, err = sink.NewTLSClient(sinkConfig.TLS)
 			if err != nil {
 				return fmt.Errorf("creating TLS client: %w", err)
 			}
 		}
 
-		prober, err := prober.New(sinkConfig.ProbeTimeout, sinkConfig.ProbeBackoff, prober.WithLogger(iw.logger), prober.WithClient(client))
+		baseURL, err := httpSinkBaseURL(sinkConfig)
+		if err != nil {
+			return fmt.Errorf("building HTTP sink URL: %w", err)
+		}
+
+		headers, err := httpSinkHeaders(sinkConfig)
+		if err != nil {
+			return fmt.Errorf("building HTTP sink headers: %w", err)
+		}
+
+		p, err := prober.New(
+			sinkConfig.ProbeTimeout,
+			sinkConfig.ProbeBackoff,
+			prober.WithLogger(iw.logger),
+			prober.WithClient(client),
+			prober.WithHeaders(headers),
+		)
 		if err != nil {
 			return fmt.Errorf("building prober: %w", err)
 		}
 
-		iw.logger.Info("Waiting for agent container to be ready...")
-		hostPort := net.JoinHostPort(sink.DefaultAgentForwarderhost, strconv.Itoa(sinkConfig.Port))
-		err = prober.Probe(fmt.Sprintf("%s://%s%s", scheme, hostPort, agentReadyPath))
+		iw.logger.Infof("Waiting for agent at %s to be ready...", baseURL.Host)
+		readyURL := baseURL.JoinPath(agentReadyPath).String()
+		err = p.Probe(readyURL)
 		if err != nil {
 			return fmt.Errorf("timeout waiting for agent: %w", err)
 		}
 
+		monitor := prober.NewMonitor(p, readyURL)
+		iw.monitors[len(iw.sinks)] = monitor
+
 		c := pester.NewExtendedClient(client)
 		c.Backoff = pester.LinearBackoff
 		c.MaxRetries = sinkConfig.Retries
@@ -84,19 +197,79 @@ func WithHTTPSink(sinkConfig config.HTTPSink) OptionFunc {
 			iw.logger.Warnf("Error sending data to agent sink: %q", e)
 		}
 
+		path := sinkConfig.Path
+		if path == "" {
+			path = sink.DefaultAgentForwarderPath
+		}
+
 		h, err := sink.New(sink.HTTPSinkOptions{
-			URL:    fmt.Sprintf("http://%s%s", hostPort, sink.DefaultAgentForwarderPath),
-			Client: c,
+			URL:                   baseURL.JoinPath(path).String(),
+			Client:                c,
+			MaxEntitiesPerRequest: sinkConfig.MaxEntitiesPerRequest,
+			MaxBytesPerRequest:    sinkConfig.MaxBytesPerRequest,
+			Gzip:                  sinkConfig.Gzip,
+			Headers:               headers,
+			Readiness:             monitor,
 		})
 		if err != nil {
 			return fmt.Errorf("creating HTTP Sink: %w", err)
 		}
 
-		iw.sink = h
+		iw.sinks = append(iw.sinks, sink.Target{Name: fmt.Sprintf("%s:%s", config.SinkTypeHTTP, baseURL.Host), Writer: h})
 		return nil
 	}
 }
 
+// httpSinkBaseURL returns the scheme and host of the HTTP sink described by sinkConfig, falling back to the local
+// agent forwarder when no host is configured.
+func httpSinkBaseURL(sinkConfig config.HTTPSink) (*url.URL, error) {
+	scheme := sinkConfig.Scheme
+	if scheme == "" {
+		scheme = "http"
+		if sinkConfig.TLS.Enabled {
+			scheme = "https"
+		}
+	}
+
+	if scheme != "http" && scheme != "https" {
+		return nil, fmt.Errorf("unsupported scheme %q", scheme)
+	}
+
+	host := sinkConfig.Host
+	if host == "" {
+		host = sink.DefaultAgentForwarderhost
+	}
+
+	if sinkConfig.Port != 0 {
+		host = net.JoinHostPort(host, strconv.Itoa(sinkConfig.Port))
+	}
+
+	return &url.URL{Scheme: scheme, Host: host}, nil
+}
+
+// httpSinkHeaders returns the headers that should be added to every request made to the HTTP sink, including the
+// authentication ones.
+func httpSinkHeaders(sinkConfig config.HTTPSink) (http.Header, error) {
+	headers := http.Header{}
+	for name, value := range sinkConfig.Headers {
+		headers.Set(name, value)
+	}
+
+	auth := sinkConfig.Auth
+	basicAuth := auth.Username != "" || auth.Password != ""
+	switch {
+	case auth.BearerToken != "" && basicAuth:
+		return nil, fmt.Errorf("bearer token and basic auth cannot be used at the same time")
+	case auth.BearerToken != "":
+		headers.Set("Authorization", "Bearer "+auth.BearerToken)
+	case basicAuth:
+		credentials := base64.StdEncoding.EncodeToString([]byte(auth.Username + ":" + auth.Password))
+		headers.Set("Authorization", "Basic "+credentials)
+	}
+
+	return headers, nil
+}
+
 // Metadata contains the integration name and version that is passed down to the integration SDK.
 type Metadata struct {
 	Name    string
@@ -106,13 +279,14 @@ type Metadata struct {
 // NewWrapper creates a new SDK integration wrapper using the specified options.
 func NewWrapper(opts ...OptionFunc) (*Wrapper, error) {
 	intgr := &Wrapper{
-		logger: logutil.Discard,
-		sink:   os.Stdout,
+		logger:   logutil.Discard,
+		monitors: map[int]*prober.Monitor{},
 	}
 
 	for _, opt := range opts {
 		err := opt(intgr)
 		if err != nil {
+			_ = intgr.Close()
 			return nil, fmt.Errorf("applying option: %w", err)
 		}
 	}
@@ -120,9 +294,121 @@ func NewWrapper(opts ...OptionFunc) (*Wrapper, error) {
 	return intgr, nil
 }
 
+// Close flushes and releases the resources held by the configured sinks, like the files of file sinks, and stops
+// monitoring the readiness of HTTP sinks. The wrapper must not be used after calling it.
+func (iw *Wrapper) Close() error {
+	for _, monitor := range iw.monitors {
+		monitor.Close()
+	}
+
+	var errs []error
+	for _, closer := range iw.closers {
+		if err := closer(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	iw.closers = nil
+
+	return errors.Join(errs...)
+}
+
 // Integration returns a sdk.Integration, configured to output data to the specified agent.
 // Integration will block and wait until the specified server is ready, up to a maximum timeout.
 func (iw *Wrapper) Integration() (*sdk.Integration, error) {
 	cache := storer.NewInMemoryStore(storer.DefaultTTL, storer.DefaultInterval, iw.logger)
-	return sdk.New(iw.metadata.Name, iw.metadata.Version, sdk.Writer(iw.sink), sdk.Storer(cache))
+
+	w, err := iw.writer()
+	if err != nil {
+		return nil, fmt.Errorf("building sink: %w", err)
+	}
+
+	return sdk.New(iw.metadata.Name, iw.metadata.Version, sdk.Writer(w), sdk.Storer(cache))
+}
+
+// Emitter returns a dimensional.Emitter writing dimensional metrics to the configured sinks, as an alternative to the
+// metric sets of Integration.
+func (iw *Wrapper) Emitter() (*dimensional.Emitter, error) {
+	w, err := iw.writer()
+	if err != nil {
+		return nil, fmt.Errorf("building sink: %w", err)
+	}
+
+	return dimensional.NewEmitter(iw.metadata.Name, iw.metadata.Version, w), nil
+}
+
+// Ready returns an error describing which HTTP sinks are currently not ready to receive data, or nil if all of them are.
+func (iw *Wrapper) Ready() error {
+	var errs []error
+	for _, i := range slices.Sorted(maps.Keys(iw.monitors)) {
+		status := iw.monitors[i].Status()
+		if !status.Ready {
+			errs = append(errs, fmt.Errorf("sink %q not ready since %s: %w", iw.sinks[i].Name, status.Since.Format(time.RFC3339), status.LastError))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ReadyHandler returns an http.Handler reporting whether all HTTP sinks are ready to receive data, as returned by
+// Ready, for health checks to observe it. It responds with a 200 status if they are, and with a 503 one describing
+// the sinks that are not otherwise.
+func (iw *Wrapper) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if err := iw.Ready(); err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = io.WriteString(w, "ok\n")
+	})
+}
+
+// writer returns the io.Writer the integration should write payloads to, fanning out to all the configured sinks
+// when more than one is present.
+func (iw *Wrapper) writer() (io.Writer, error) {
+	if len(iw.routes) > 0 || iw.defaultRoute != "" {
+		return iw.router()
+	}
+
+	switch len(iw.sinks) {
+	case 0:
+		return os.Stdout, nil
+	case 1:
+		return iw.sinks[0].Writer, nil
+	default:
+		return sink.NewMulti(iw.sinks, sink.WithMultiLogger(iw.logger))
+	}
+}
+
+// router builds a sink.Router out of the configured routes and sinks.
+func (iw *Wrapper) router() (*sink.Router, error) {
+	targets := map[string]sink.Target{}
+	for _, t := range iw.sinks {
+		targets[t.Name] = t
+	}
+
+	routes := make([]sink.Route, 0, len(iw.routes))
+	for _, r := range iw.routes {
+		target, ok := targets[r.Sink]
+		if !ok {
+			return nil, fmt.Errorf("route references unknown sink %q", r.Sink)
+		}
+
+		routes = append(routes, sink.Route{
+			EntityTypes: r.EntityTypes,
+			Namespaces:  r.Namespaces,
+			Target:      target,
+		})
+	}
+
+	opts := []sink.RouterOptionFunc{sink.WithRouterLogger(iw.logger)}
+	if iw.defaultRoute != "" {
+		target, ok := targets[iw.defaultRoute]
+		if !ok {
+			return nil, fmt.Errorf("default route references unknown sink %q", iw.defaultRoute)
+		}
+		opts = append(opts, sink.WithDefaultTarget(target))
+	}
+
+	return sink.NewRouter(routes, opts...)
 }
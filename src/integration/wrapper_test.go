@@ -0,0 +1,222 @@
+package integration_test
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/config"
+	"github.com/newrelic/nri-kubernetes/v3/src/integration"
+)
+
+func testSinkServer(t *testing.T, path string, checkRequest func(r *http.Request)) (string, int) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/data/ready", func(rw http.ResponseWriter, r *http.Request) {
+		checkRequest(r)
+		rw.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc(path, func(rw http.ResponseWriter, r *http.Request) {
+		checkRequest(r)
+		rw.WriteHeader(http.StatusNoContent)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	return host, portNumber
+}
+
+func httpSinkConfig(host string, port int) config.HTTPSink {
+	return config.HTTPSink{
+		Host:         host,
+		Port:         port,
+		Timeout:      time.Second,
+		Retries:      1,
+		ProbeTimeout: 3 * time.Second,
+		ProbeBackoff: 100 * time.Millisecond,
+	}
+}
+
+func TestWithHTTPSink_sends_to_configured_target(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		auth           config.HTTPSinkAuth
+		expectedHeader string
+	}{
+		"with_bearer_token": {
+			auth:           config.HTTPSinkAuth{BearerToken: "token"},
+			expectedHeader: "Bearer token",
+		},
+		"with_basic_auth": {
+			auth:           config.HTTPSinkAuth{Username: "user", Password: "pass"},
+			expectedHeader: "Basic dXNlcjpwYXNz",
+		},
+		"without_auth": {},
+	}
+
+	for name, testCase := range testCases {
+		tc := testCase
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			published := make(chan struct{}, 1)
+			host, port := testSinkServer(t, "/custom/path", func(r *http.Request) {
+				assert.Equal(t, "bar", r.Header.Get("X-Foo"))
+				assert.Equal(t, tc.expectedHeader, r.Header.Get("Authorization"))
+				if r.URL.Path == "/custom/path" {
+					published <- struct{}{}
+				}
+			})
+
+			sinkConfig := httpSinkConfig(host, port)
+			sinkConfig.Path = "/custom/path"
+			sinkConfig.Headers = map[string]string{"x-foo": "bar"}
+			sinkConfig.Auth = tc.auth
+
+			iw, err := integration.NewWrapper(
+				integration.WithMetadata(integration.Metadata{Name: "test", Version: "0.0.0"}),
+				integration.WithHTTPSink(sinkConfig),
+			)
+			require.NoError(t, err)
+
+			i, err := iw.Integration()
+			require.NoError(t, err)
+			require.NoError(t, i.Publish())
+
+			select {
+			case <-published:
+			default:
+				t.Fatal("payload was not posted to the configured path")
+			}
+		})
+	}
+}
+
+func TestWrapper_ReadyHandler_reports_every_http_sink(t *testing.T) {
+	t.Parallel()
+
+	var down atomic.Bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(rw http.ResponseWriter, _ *http.Request) {
+		if down.Load() {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/v1/data/ready", func(rw http.ResponseWriter, _ *http.Request) {
+		if down.Load() {
+			rw.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+	require.NoError(t, err)
+	portNumber, err := strconv.Atoi(port)
+	require.NoError(t, err)
+
+	// Both sinks share their host, so they are told apart by their position rather than their name.
+	first := httpSinkConfig(host, portNumber)
+	first.Path = "/first"
+	second := httpSinkConfig(host, portNumber)
+	second.Path = "/second"
+
+	iw, err := integration.NewWrapper(
+		integration.WithMetadata(integration.Metadata{Name: "test", Version: "0.0.0"}),
+		integration.WithHTTPSink(first),
+		integration.WithHTTPSink(second),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = iw.Close() })
+
+	rec := httptest.NewRecorder()
+	iw.ReadyHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	down.Store(true)
+	i, err := iw.Integration()
+	require.NoError(t, err)
+	_ = i.Publish()
+
+	rec = httptest.NewRecorder()
+	iw.ReadyHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, 2, strings.Count(rec.Body.String(), "not ready"), rec.Body.String())
+}
+
+func TestWithHTTPSink_fails_when(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]func(c *config.HTTPSink){
+		"scheme_is_not_supported": func(c *config.HTTPSink) {
+			c.Scheme = "ftp"
+		},
+		"bearer_token_and_basic_auth_are_set": func(c *config.HTTPSink) {
+			c.Auth = config.HTTPSinkAuth{BearerToken: "token", Username: "user"}
+		},
+	}
+
+	for name, modify := range testCases {
+		modify := modify
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			sinkConfig := httpSinkConfig("127.0.0.1", 1)
+			modify(&sinkConfig)
+
+			_, err := integration.NewWrapper(integration.WithHTTPSink(sinkConfig))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWrapper_Close_flushes_file_sink(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "metrics.json")
+
+	iw, err := integration.NewWrapper(
+		integration.WithMetadata(integration.Metadata{Name: "test", Version: "0.0.0"}),
+		integration.WithFileSink(config.FileSink{Path: path}),
+	)
+	require.NoError(t, err)
+
+	i, err := iw.Integration()
+	require.NoError(t, err)
+
+	_, err = i.Entity("test", "test")
+	require.NoError(t, err)
+	require.NoError(t, i.Publish())
+
+	require.NoError(t, iw.Close())
+	require.NoError(t, iw.Close(), "closing twice should be a no-op")
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"name":"test"`)
+
+	assert.ErrorIs(t, i.Publish(), os.ErrClosed)
+}
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -73,8 +74,8 @@ func New(opts ...OptionFunc) (*Client, error) {
 
 // MetricFamiliesGetFunc returns a function that obtains metric families from a list of prometheus queries.
 func (c *Client) MetricFamiliesGetFunc(url string) prometheus.FetchAndFilterMetricsFamilies {
-	return func(queries []prometheus.Query) ([]prometheus.MetricFamily, error) {
-		mFamily, err := prometheus.GetFilteredMetricFamilies(c.http, url, queries, c.logger)
+	return func(ctx context.Context, queries []prometheus.Query) ([]prometheus.MetricFamily, error) {
+		mFamily, err := prometheus.GetFilteredMetricFamilies(ctx, c.http, url, queries, c.logger)
 		if err != nil {
 			return nil, fmt.Errorf("getting filtered metric families: %w", err)
 		}
@@ -1,6 +1,7 @@
 package client_test
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -28,7 +29,7 @@ func Test_Client(t *testing.T) {
 	familyGetter := cpClient.MetricFamiliesGetFunc(server.URL)
 
 	// Fails if timeout
-	_, err = familyGetter(nil)
+	_, err = familyGetter(context.Background(), nil)
 	require.Error(t, err)
 
 	// Test calling retry
@@ -38,7 +39,7 @@ func Test_Client(t *testing.T) {
 	familyGetter = cpClient.MetricFamiliesGetFunc(server.URL)
 
 	// Should retry and not fail with timeout
-	_, err = familyGetter(nil)
+	_, err = familyGetter(context.Background(), nil)
 	require.NoError(t, err)
 
 	require.Equal(t, 3, requestsReceived)
@@ -59,7 +60,7 @@ func Test_Client_Read(t *testing.T) {
 	}
 	queries := []prometheus.Query{query}
 
-	families, err := familyGetter(queries)
+	families, err := familyGetter(context.Background(), queries)
 	require.NoError(t, err)
 
 	// stateset parser failure, did not prevent kube_pod_status_phase from being reported
@@ -1,6 +1,7 @@
 package grouper
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/newrelic/nri-kubernetes/v3/internal/logutil"
@@ -61,8 +62,8 @@ func New(config Config, opts ...OptionFunc) (data.Grouper, error) {
 
 // Group implements Grouper interface by fetching Prometheus metrics from KSM and then modifying it
 // using Service objects fetched from API server.
-func (g *grouper) Group(specGroups definition.SpecGroups) (definition.RawGroups, *data.ErrorGroup) {
-	mFamily, err := g.MetricFamiliesGetter(g.Queries)
+func (g *grouper) Group(ctx context.Context, specGroups definition.SpecGroups) (definition.RawGroups, *data.ErrorGroup) {
+	mFamily, err := g.MetricFamiliesGetter(ctx, g.Queries)
 	if err != nil {
 		return nil, &data.ErrorGroup{
 			Errors: []error{fmt.Errorf("querying KSM: %w", err)},
@@ -3,6 +3,7 @@ package ksm_test
 // This file holds the integration tests for the KSM package.
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -112,7 +113,7 @@ func TestScraper(t *testing.T) {
 
 			i := testutil.NewIntegration(t)
 
-			err = scraper.Run(i)
+			err = scraper.Run(context.Background(), i)
 			if err != nil {
 				t.Fatalf("running scraper: %v", err)
 			}
@@ -154,7 +155,7 @@ func TestScraper_FilterNamespace(t *testing.T) {
 
 		i := testutil.NewIntegration(t)
 
-		err = scraper.Run(i)
+		err = scraper.Run(context.Background(), i)
 		require.NoError(t, err)
 		assert.Equal(t, 34, len(i.Entities))
 	})
@@ -1,6 +1,7 @@
 package ksm
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 
@@ -11,10 +12,16 @@ import (
 	"k8s.io/client-go/kubernetes"
 	listersv1 "k8s.io/client-go/listers/core/v1"
 
+	"github.com/newrelic/nri-kubernetes/v3/internal/attributes"
 	"github.com/newrelic/nri-kubernetes/v3/internal/config"
 	"github.com/newrelic/nri-kubernetes/v3/internal/discovery"
+	"github.com/newrelic/nri-kubernetes/v3/internal/entities"
+	"github.com/newrelic/nri-kubernetes/v3/internal/labels"
+	"github.com/newrelic/nri-kubernetes/v3/internal/storer"
+	"github.com/newrelic/nri-kubernetes/v3/src/dimensional"
 	ksmGrouper "github.com/newrelic/nri-kubernetes/v3/src/ksm/grouper"
 	"github.com/newrelic/nri-kubernetes/v3/src/metric"
+	"github.com/newrelic/nri-kubernetes/v3/src/populator"
 	"github.com/newrelic/nri-kubernetes/v3/src/prometheus"
 	"github.com/newrelic/nri-kubernetes/v3/src/scrape"
 )
@@ -39,6 +46,14 @@ type Scraper struct {
 	endpointsDiscoverer discovery.EndpointsDiscoverer
 	servicesLister      listersv1.ServiceLister
 	informerClosers     []chan<- struct{}
+	samples             *storer.InMemoryStore
+	definitions         metric.Definitions
+	labels              *labels.Guard
+	entities            *entities.Filter
+	attributes          *attributes.Decorator
+	dimensional         *dimensional.Emitter
+	errors              *populator.ErrorReporter
+	optOut              *discovery.OptOutFilter
 	Filterer            discovery.NamespaceFilterer
 }
 
@@ -53,6 +68,41 @@ func WithLogger(logger *log.Logger) ScraperOpt {
 	}
 }
 
+// WithCustomAttributes returns an OptionFunc to add the custom attributes computed by decorator to the entities the
+// scraper populates.
+func WithCustomAttributes(decorator *attributes.Decorator) ScraperOpt {
+	return func(s *Scraper) error {
+		s.attributes = decorator
+		return nil
+	}
+}
+
+// WithDimensionalEmitter returns an OptionFunc to report the metrics the scraper populates as dimensional metrics
+// collected by emitter, instead of metric sets of the integration.
+func WithDimensionalEmitter(emitter *dimensional.Emitter) ScraperOpt {
+	return func(s *Scraper) error {
+		s.dimensional = emitter
+		return nil
+	}
+}
+
+// WithDefinitions returns an OptionFunc to change the specs and queries the scraper uses from the builtin ones.
+func WithDefinitions(definitions metric.Definitions) ScraperOpt {
+	return func(s *Scraper) error {
+		s.definitions = definitions
+		return nil
+	}
+}
+
+// WithOptOutFilter returns an OptionFunc to skip the entities of namespaces and pods opting out of being scraped, as
+// told by filter, which is closed with the scraper.
+func WithOptOutFilter(filter *discovery.OptOutFilter) ScraperOpt {
+	return func(s *Scraper) error {
+		s.optOut = filter
+		return nil
+	}
+}
+
 // WithFilterer returns an OptionFunc to add a Filterer.
 func WithFilterer(filterer discovery.NamespaceFilterer) ScraperOpt {
 	return func(s *Scraper) error {
@@ -65,9 +115,10 @@ func WithFilterer(filterer discovery.NamespaceFilterer) ScraperOpt {
 // Close() to prevent resource leakage.
 func NewScraper(config *config.Config, providers Providers, options ...ScraperOpt) (*Scraper, error) {
 	s := &Scraper{
-		config:    config,
-		Providers: providers,
-		logger:    logutil.Discard,
+		config:      config,
+		Providers:   providers,
+		logger:      logutil.Discard,
+		definitions: metric.Builtin()[metric.TargetKSM],
 	}
 
 	// TODO: Sanity check config
@@ -98,6 +149,20 @@ func NewScraper(config *config.Config, providers Providers, options ...ScraperOp
 
 	s.endpointsDiscoverer = endpointsDiscoverer
 
+	s.labels, err = labels.NewGuard(config.Labels)
+	if err != nil {
+		return nil, fmt.Errorf("building label guard: %w", err)
+	}
+
+	s.entities, err = entities.NewFilter(config.Entities)
+	if err != nil {
+		return nil, fmt.Errorf("building entity filter: %w", err)
+	}
+
+	s.errors = populator.NewErrorReporter(config.PopulateErrors.WarnRatioChange)
+
+	s.samples = storer.NewInMemoryStore(storer.DefaultTTL, storer.DefaultInterval, s.logger)
+
 	servicesLister, servicesCloser := discovery.NewServicesLister(providers.K8s)
 	s.servicesLister = servicesLister
 	s.informerClosers = append(s.informerClosers, servicesCloser)
@@ -106,8 +171,8 @@ func NewScraper(config *config.Config, providers Providers, options ...ScraperOp
 }
 
 // Run runs the scraper, adding all the KSM-related metrics and entities into the integration i.
-// Run must not be called after Close().
-func (s *Scraper) Run(i *integration.Integration) error {
+// Run must not be called after Close(). Cancelling ctx aborts the requests to KSM and stops trying further endpoints.
+func (s *Scraper) Run(ctx context.Context, i *integration.Integration) error {
 	populated := false
 
 	endpoints, err := s.ksmURLs()
@@ -121,7 +186,7 @@ func (s *Scraper) Run(i *integration.Integration) error {
 		s.logger.Debugf("Fetching KSM data from %q", endpoint)
 		grouper, err := ksmGrouper.New(ksmGrouper.Config{
 			MetricFamiliesGetter:       s.KSM.MetricFamiliesGetFunc(endpoint),
-			Queries:                    metric.KSMQueries,
+			Queries:                    s.definitions.Queries,
 			ServicesLister:             s.servicesLister,
 			EnableResourceQuotaSamples: s.config.EnableResourceQuotaSamples,
 		}, ksmGrouper.WithLogger(s.logger))
@@ -130,19 +195,30 @@ func (s *Scraper) Run(i *integration.Integration) error {
 		}
 
 		// TODO: Check if the concept of job still makes sense with the new architecture.
-		job := scrape.NewScrapeJob("kube-state-metrics", grouper, metric.KSMSpecs, scrape.JobWithFilterer(s.Filterer))
+		job := scrape.NewScrapeJob("kube-state-metrics", grouper, s.definitions.Specs,
+			scrape.JobWithFilterer(s.Filterer),
+			scrape.JobWithOptOutFilter(s.optOut),
+			scrape.JobWithSampleStore(s.samples),
+			scrape.JobWithLabelGuard(s.labels),
+			scrape.JobWithCustomAttributes(s.attributes),
+			scrape.JobWithEntityFilter(s.entities),
+			scrape.JobWithDimensionalEmitter(s.dimensional),
+			scrape.JobWithErrorReporter(s.errors),
+			scrape.JobWithWorkers(s.config.PopulateWorkers),
+		)
 
 		s.logger.Debugf("Running KSM job")
-		r := job.Populate(i, s.config.ClusterName, s.logger, s.k8sVersion)
-		if r.Errors != nil {
-			if r.Populated {
-				s.logger.Tracef("Error populating KSM metrics: %v", r.Error())
-			} else {
-				s.logger.Warnf("Error populating KSM metrics: %v", r.Error())
-			}
+		r := job.Populate(ctx, i, s.config.ClusterName, s.logger, s.k8sVersion)
+		// Errors of populated jobs are logged by the error reporter.
+		if r.Errors != nil && !r.Populated {
+			s.logger.Warnf("Error populating KSM metrics: %v", r.Error())
 		}
 
 		if !r.Populated {
+			if err := ctx.Err(); err != nil {
+				return fmt.Errorf("scraping KSM endpoints: %w", err)
+			}
+
 			log.Debug("No metrics were populated, trying next endpoint")
 			continue
 		}
@@ -166,6 +242,9 @@ func (s *Scraper) Close() {
 	for _, ch := range s.informerClosers {
 		close(ch)
 	}
+
+	s.samples.StopVacuum()
+	_ = s.optOut.Close()
 }
 
 // buildDiscoverer returns a discovery.EndpointsDiscoverer, configured to discover KSM endpoints in the cluster,
@@ -87,12 +87,12 @@ func New(connector Connector, opts ...OptionFunc) (*Client, error) {
 }
 
 // Get implements HTTPGetter interface by sending GET request using configured client.
-func (client *Client) Get(urlPath string) (*http.Response, error) {
+func (client *Client) Get(ctx context.Context, urlPath string) (*http.Response, error) {
 	// Notice that this is the client to interact with kubelet. In case of CAdvisor the MetricFamiliesGetFunc is used
 	e := client.endpoint
 	e.Path = path.Join(client.endpoint.Path, urlPath)
 
-	result, err := client.GetURI(e)
+	result, err := client.GetURI(ctx, e)
 	if err != nil {
 		return nil, fmt.Errorf("error getting path %s: %w ", urlPath, err)
 	}
@@ -100,8 +100,8 @@ func (client *Client) Get(urlPath string) (*http.Response, error) {
 	return result, nil
 }
 
-func (client *Client) GetURI(uri url.URL) (*http.Response, error) {
-	r, err := http.NewRequestWithContext(context.Background(), http.MethodGet, uri.String(), nil)
+func (client *Client) GetURI(ctx context.Context, uri url.URL) (*http.Response, error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, uri.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("error creating request to: %s. Got error: %w ", uri.String(), err)
 	}
@@ -117,11 +117,11 @@ func (client *Client) GetURI(uri url.URL) (*http.Response, error) {
 
 // MetricFamiliesGetFunc returns a function that obtains metric families from a list of prometheus queries.
 func (client *Client) MetricFamiliesGetFunc(url string) prometheus.FetchAndFilterMetricsFamilies {
-	return func(queries []prometheus.Query) ([]prometheus.MetricFamily, error) {
+	return func(ctx context.Context, queries []prometheus.Query) ([]prometheus.MetricFamily, error) {
 		e := client.endpoint
 		e.Path = path.Join(client.endpoint.Path, url)
 
-		mFamily, err := prometheus.GetFilteredMetricFamilies(client.doer, e.String(), queries, client.logger)
+		mFamily, err := prometheus.GetFilteredMetricFamilies(ctx, client.doer, e.String(), queries, client.logger)
 		if err != nil {
 			return nil, fmt.Errorf("getting filtered metric families %q: %w", e.String(), err)
 		}
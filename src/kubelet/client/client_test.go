@@ -1,6 +1,7 @@
 package client_test
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -23,6 +24,7 @@ import (
 
 	"github.com/newrelic/nri-kubernetes/v3/internal/config"
 	"github.com/newrelic/nri-kubernetes/v3/src/kubelet/client"
+	"github.com/newrelic/nri-kubernetes/v3/src/prometheus"
 )
 
 const (
@@ -59,7 +61,7 @@ func TestClientCalls(t *testing.T) {
 	})
 
 	t.Run("hits_kubelet_metric", func(t *testing.T) {
-		r, err := kubeletClient.Get(kubeletMetric)
+		r, err := kubeletClient.Get(context.Background(), kubeletMetric)
 		assert.NoError(t, err)
 		assert.Equal(t, r.StatusCode, http.StatusOK)
 
@@ -70,11 +72,11 @@ func TestClientCalls(t *testing.T) {
 	t.Run("hits_prometheus_metric", func(t *testing.T) {
 
 		f := kubeletClient.MetricFamiliesGetFunc(prometheusMetric)
-		_, err = f(nil)
+		_, err = f(context.Background(), nil)
 
 		r, found := requests[prometheusMetric]
 		assert.True(t, found)
-		assert.Equal(t, "text/plain", r.Header["Accept"][0])
+		assert.Equal(t, prometheus.AcceptHeader, r.Header["Accept"][0])
 	})
 }
 
@@ -152,7 +154,7 @@ func TestClientCallsViaAPIProxy(t *testing.T) {
 	t.Run("hits_kubelet_metric_through_proxy", func(t *testing.T) {
 		t.Parallel()
 
-		r, err := kubeletClient.Get(kubeletMetric)
+		r, err := kubeletClient.Get(context.Background(), kubeletMetric)
 		assert.NoError(t, err)
 		assert.Equal(t, r.StatusCode, http.StatusOK)
 
@@ -164,19 +166,19 @@ func TestClientCallsViaAPIProxy(t *testing.T) {
 		t.Parallel()
 
 		f := kubeletClient.MetricFamiliesGetFunc(prometheusMetric)
-		_, err = f(nil)
+		_, err = f(context.Background(), nil)
 
 		r, found := requests[path.Join(apiProxy, prometheusMetric)]
 		assert.True(t, found)
 
-		assert.Equal(t, "text/plain", r.Header["Accept"][0])
+		assert.Equal(t, prometheus.AcceptHeader, r.Header["Accept"][0])
 	})
 
 	t.Run("do_not_hit_prometheus_endpoint", func(t *testing.T) {
 		t.Parallel()
 
 		f := kubeletClient.MetricFamiliesGetFunc("not-existing")
-		_, err = f(nil)
+		_, err = f(context.Background(), nil)
 		assert.Error(t, err)
 	})
 }
@@ -329,7 +331,7 @@ func TestClientTimeoutAndRetries(t *testing.T) {
 	require.NoError(t, err)
 
 	t.Run("gets_200_after_retry", func(t *testing.T) {
-		r, err := kubeletClient.Get(kubeletMetricWithDelay)
+		r, err := kubeletClient.Get(context.Background(), kubeletMetricWithDelay)
 		require.NoError(t, err)
 		assert.Equal(t, r.StatusCode, http.StatusOK)
 
@@ -338,6 +340,40 @@ func TestClientTimeoutAndRetries(t *testing.T) {
 	})
 }
 
+func TestClientAbortsWhenContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	s := httptest.NewTLSServer(http.HandlerFunc(
+		func(rw http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == kubeletMetricWithDelay {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(5 * time.Second):
+				}
+				return
+			}
+			rw.WriteHeader(200)
+		},
+	))
+	t.Cleanup(s.Close)
+
+	c, cf, inClusterConfig := getTestData(s)
+
+	kubeletClient, err := client.New(
+		client.DefaultConnector(c, cf, inClusterConfig, logutil.Debug),
+		client.WithMaxRetries(5),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = kubeletClient.Get(ctx, kubeletMetricWithDelay)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second, "request and retries should be aborted with the context")
+}
+
 func TestClientOptions(t *testing.T) {
 	t.Parallel()
 
@@ -1,6 +1,7 @@
 package grouper
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/newrelic/nri-kubernetes/v3/internal/config"
@@ -62,7 +63,7 @@ func New(config Config, opts ...OptionFunc) (data.Grouper, error) {
 // Group implements Grouper interface by fetching RawGroups using both given fetch functions
 // and hardcoded fetching calls pulling kubelet summary metrics, node information from Kubernetes API
 // and then merging all this information.
-func (r *grouper) Group(definition.SpecGroups) (definition.RawGroups, *data.ErrorGroup) {
+func (r *grouper) Group(ctx context.Context, _ definition.SpecGroups) (definition.RawGroups, *data.ErrorGroup) {
 	rawGroups := definition.RawGroups{
 		"network": {
 			"interfaces": definition.RawMetrics{
@@ -71,7 +72,7 @@ func (r *grouper) Group(definition.SpecGroups) (definition.RawGroups, *data.Erro
 		},
 	}
 	for _, f := range r.Fetchers {
-		g, err := f()
+		g, err := f(ctx)
 		if err != nil {
 			if _, ok := err.(data.ErrorGroup); !ok {
 				return nil, &data.ErrorGroup{
@@ -83,7 +84,7 @@ func (r *grouper) Group(definition.SpecGroups) (definition.RawGroups, *data.Erro
 	}
 
 	// TODO wrap this process in a new fetchFunc
-	response, err := metric.GetMetricsData(r.Client)
+	response, err := metric.GetMetricsData(ctx, r.Client)
 	if err != nil {
 		return nil, &data.ErrorGroup{
 			Errors: []error{fmt.Errorf("error querying Kubelet. %s", err)},
@@ -93,7 +94,7 @@ func (r *grouper) Group(definition.SpecGroups) (definition.RawGroups, *data.Erro
 	// Get pod specs for volume filtering if PodsFetcher and config are available
 	var podSpecs map[string]*v1.Pod
 	if r.PodsFetcher != nil && r.IntegrationConfig != nil {
-		podSpecs, err = r.PodsFetcher.GetPodSpecs()
+		podSpecs, err = r.PodsFetcher.GetPodSpecs(ctx)
 		if err != nil {
 			r.logger.Warnf("Failed to get pod specs for volume filtering: %v", err)
 		}
@@ -1,6 +1,7 @@
 package grouper
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -30,12 +31,12 @@ type testClient struct {
 	handler http.HandlerFunc
 }
 
-func (c *testClient) GetURI(uri url.URL) (*http.Response, error) {
+func (c *testClient) GetURI(_ context.Context, uri url.URL) (*http.Response, error) {
 	req := httptest.NewRequest(http.MethodGet, uri.String(), nil)
 	return c.Do(req)
 }
 
-func (c *testClient) Get(path string) (*http.Response, error) {
+func (c *testClient) Get(_ context.Context, path string) (*http.Response, error) {
 	req := httptest.NewRequest(http.MethodGet, path, nil)
 	return c.Do(req)
 }
@@ -120,7 +121,7 @@ func TestGroup(t *testing.T) {
 	)
 	assert.Nil(t, err)
 
-	r, errGroup := kubeletGrouper.Group(nil)
+	r, errGroup := kubeletGrouper.Group(context.Background(), nil)
 
 	assert.Nil(t, errGroup)
 
@@ -3,6 +3,7 @@ package kubelet_test
 // This file holds the integration tests for the Kubelet package.
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -80,7 +81,7 @@ func TestScraper(t *testing.T) {
 			}
 
 			i := testutil.NewIntegration(t)
-			err = scraper.Run(i)
+			err = scraper.Run(context.Background(), i)
 			if err != nil {
 				t.Fatalf("running scraper: %v", err)
 			}
@@ -125,7 +126,7 @@ func TestScraper_FilterNamespace(t *testing.T) {
 		require.NoError(t, err)
 
 		i := testutil.NewIntegration(t)
-		err = scraper.Run(i)
+		err = scraper.Run(context.Background(), i)
 		require.NoError(t, err)
 
 		// Call the asserter for the entities of this particular sub-test.
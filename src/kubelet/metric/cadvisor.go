@@ -1,6 +1,7 @@
 package metric
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"regexp"
@@ -34,8 +35,8 @@ func getLabel(labels prometheus.Labels, names ...string) (string, bool) {
 
 // CadvisorFetchFunc creates a FetchFunc that fetches data from the kubelet cadvisor metrics path.
 func CadvisorFetchFunc(fetchAndFilterPrometheus prometheus.FetchAndFilterMetricsFamilies, queries []prometheus.Query) data.FetchFunc {
-	return func() (definition.RawGroups, error) {
-		families, err := fetchAndFilterPrometheus(queries)
+	return func(ctx context.Context) (definition.RawGroups, error) {
+		families, err := fetchAndFilterPrometheus(ctx, queries)
 		if err != nil {
 			return nil, fmt.Errorf("error requesting cadvisor metrics endpoint: %w", err)
 		}
@@ -91,8 +92,8 @@ func CadvisorFetchFunc(fetchAndFilterPrometheus prometheus.FetchAndFilterMetrics
 					}
 					metrics["containerImageID"] = m.Labels["image"]
 				default:
-					// by default, we want the actual metric
-					metrics[f.Name] = m.Value
+					// by default, we want the actual metric, along with its timestamp if cAdvisor exposes it
+					metrics[f.Name] = definition.WithTimestamp(m.Value, m.Timestamp)
 				}
 			}
 		}
@@ -1,6 +1,7 @@
 package metric
 
 import (
+	"context"
 	"errors"
 	"io"
 	"net/http"
@@ -54,7 +55,7 @@ func runCAdvisorFetchFunc(t *testing.T, file string) {
 
 	require.NoError(t, err)
 
-	g, err := CadvisorFetchFunc(kubeletClient.MetricFamiliesGetFunc(KubeletCAdvisorMetricsPath), cadvisorQueries)()
+	g, err := CadvisorFetchFunc(kubeletClient.MetricFamiliesGetFunc(KubeletCAdvisorMetricsPath), cadvisorQueries)(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, testdata.ExpectedCadvisorRawData, g)
@@ -85,7 +86,7 @@ container_memory_usage_bytes{container_name="influxdb",id="/kubepods/besteffort/
 	kubeletClient, err := client.New(client.StaticConnector(c, url.URL{}))
 	require.NoError(t, err)
 
-	_, err = CadvisorFetchFunc(kubeletClient.MetricFamiliesGetFunc(KubeletCAdvisorMetricsPath), cadvisorQueries)()
+	_, err = CadvisorFetchFunc(kubeletClient.MetricFamiliesGetFunc(KubeletCAdvisorMetricsPath), cadvisorQueries)(context.Background())
 	assert.Error(t, err)
 
 	expectedErrs := []error{
@@ -1,6 +1,7 @@
 package metric
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -25,8 +26,8 @@ var logConfigOnce sync.Once
 const StatsSummaryPath = "/stats/summary"
 
 // GetMetricsData calls kubelet /stats/summary endpoint and returns unmarshalled response
-func GetMetricsData(c client.HTTPGetter) (*v1.Summary, error) {
-	resp, err := c.Get(StatsSummaryPath)
+func GetMetricsData(ctx context.Context, c client.HTTPGetter) (*v1.Summary, error) {
+	resp, err := c.Get(ctx, StatsSummaryPath)
 	if err != nil {
 		return nil, fmt.Errorf("performing GET request to kubelet endpoint %q: %w", StatsSummaryPath, err)
 	}
@@ -566,6 +567,14 @@ func FromLabelGetNamespace(metrics definition.RawMetrics) string {
 	return ""
 }
 
+// FromRawGetPod returns the name of the pod the raw metrics belong to.
+func FromRawGetPod(metrics definition.RawMetrics) string {
+	if pod, ok := metrics["podName"].(string); ok {
+		return pod
+	}
+	return ""
+}
+
 func getKeys(groupLabel, rawEntityID string, groups definition.RawGroups, keys ...string) ([]string, error) {
 	var s []string
 	gl, ok := groups[groupLabel]
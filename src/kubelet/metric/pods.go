@@ -1,6 +1,7 @@
 package metric
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -38,10 +39,10 @@ type PodsFetcher struct {
 
 // DoPodsFetch used to have a cache that was invalidated each execution of the integration
 // TODO: could we move this to informers?
-func (podsFetcher *PodsFetcher) DoPodsFetch() (definition.RawGroups, error) {
+func (podsFetcher *PodsFetcher) DoPodsFetch(ctx context.Context) (definition.RawGroups, error) {
 	podsFetcher.logger.Debugf("Retrieving the list of pods")
 
-	r, err := podsFetcher.Fetch()
+	r, err := podsFetcher.Fetch(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -80,10 +81,10 @@ func (podsFetcher *PodsFetcher) DoPodsFetch() (definition.RawGroups, error) {
 
 // GetPodSpecs fetches and returns a map of pod specifications keyed by "namespace_podname".
 // This is used for volume filtering to determine volume types.
-func (podsFetcher *PodsFetcher) GetPodSpecs() (map[string]*v1.Pod, error) {
+func (podsFetcher *PodsFetcher) GetPodSpecs(ctx context.Context) (map[string]*v1.Pod, error) {
 	podsFetcher.logger.Debugf("Retrieving pod specifications for volume filtering")
 
-	r, err := podsFetcher.Fetch()
+	r, err := podsFetcher.Fetch(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -169,11 +170,11 @@ func (podsFetcher *PodsFetcher) fillGaps(raw definition.RawGroups, pods v1.PodLi
 	return raw
 }
 
-func (podsFetcher *PodsFetcher) Fetch() (*http.Response, error) {
+func (podsFetcher *PodsFetcher) Fetch(ctx context.Context) (*http.Response, error) {
 	if podsFetcher.useKubeService {
-		return podsFetcher.client.GetURI(podsFetcher.uri) //nolint:wrapcheck
+		return podsFetcher.client.GetURI(ctx, podsFetcher.uri) //nolint:wrapcheck
 	}
-	return podsFetcher.client.Get(KubeletPodsPath) //nolint:wrapcheck
+	return podsFetcher.client.Get(ctx, KubeletPodsPath) //nolint:wrapcheck
 }
 
 // NewPodsFetcher returns a new PodsFetcher.
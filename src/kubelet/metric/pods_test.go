@@ -1,6 +1,7 @@
 package metric
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -23,7 +24,7 @@ type testClient struct {
 	handler http.HandlerFunc
 }
 
-func (c *testClient) Get(urlPath string) (*http.Response, error) {
+func (c *testClient) Get(_ context.Context, urlPath string) (*http.Response, error) {
 	uri, _ := url.Parse("https://127.0.0.1:738")
 	uri.Path = path.Join(uri.Path, urlPath)
 
@@ -31,7 +32,7 @@ func (c *testClient) Get(urlPath string) (*http.Response, error) {
 	return c.Do(req)
 }
 
-func (c *testClient) GetURI(url url.URL) (*http.Response, error) {
+func (c *testClient) GetURI(_ context.Context, url url.URL) (*http.Response, error) {
 	req := httptest.NewRequest(http.MethodGet, url.String(), nil)
 	return c.Do(req)
 }
@@ -61,7 +62,7 @@ func TestFetchFunc(t *testing.T) {
 	}
 
 	f := NewBasicPodsFetcher(logutil.Debug, &c)
-	g, err := f.DoPodsFetch()
+	g, err := f.DoPodsFetch(context.Background())
 
 	assert.NoError(t, err)
 
@@ -85,7 +86,7 @@ func TestFetchFunFromKubeService(test *testing.T) {
 			FetchPodsFromKubeService: true,
 		},
 	})
-	podFetchResult, err := podFetch.DoPodsFetch()
+	podFetchResult, err := podFetch.DoPodsFetch(context.Background())
 
 	assert.NoError(test, err)
 
@@ -129,7 +130,7 @@ func TestShouldUseKubeServiceURL(test *testing.T) { //nolint: paralleltest
 		},
 	})
 
-	_, err := podFetch.DoPodsFetch()
+	_, err := podFetch.DoPodsFetch(context.Background())
 
 	assert.NoError(test, err)
 	assert.Equal(test, expectedURL, scrapedURL)
@@ -146,7 +147,7 @@ func TestShouldUseKubeletURLWhenBasicPodsFetcherBuilt(test *testing.T) {
 	}
 
 	podFetch := NewBasicPodsFetcher(logutil.Debug, &c)
-	_, err := podFetch.DoPodsFetch()
+	_, err := podFetch.DoPodsFetch(context.Background())
 
 	assert.NoError(test, err)
 	assert.Equal(test, "https://127.0.0.1:738/pods", scrapedURL)
@@ -176,7 +177,7 @@ func TestShouldUseKubeletURL(test *testing.T) {
 		},
 	})
 
-	_, err := podFetch.DoPodsFetch()
+	_, err := podFetch.DoPodsFetch(context.Background())
 
 	assert.NoError(test, err)
 	assert.Equal(test, "https://127.0.0.1:738/pods", scrapedURL)
@@ -236,7 +237,7 @@ func assertError(t *testing.T, errorMessage string, handler http.HandlerFunc) {
 	}
 
 	f := NewBasicPodsFetcher(logutil.Debug, &c)
-	g, err := f.DoPodsFetch()
+	g, err := f.DoPodsFetch(context.Background())
 
 	assert.EqualError(t, err, errorMessage)
 	assert.Empty(t, g)
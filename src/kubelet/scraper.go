@@ -1,6 +1,7 @@
 package kubelet
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/newrelic/infra-integrations-sdk/integration"
@@ -9,15 +10,21 @@ import (
 	"k8s.io/client-go/kubernetes"
 	listersv1 "k8s.io/client-go/listers/core/v1"
 
+	"github.com/newrelic/nri-kubernetes/v3/internal/attributes"
 	"github.com/newrelic/nri-kubernetes/v3/internal/config"
 	"github.com/newrelic/nri-kubernetes/v3/internal/discovery"
+	"github.com/newrelic/nri-kubernetes/v3/internal/entities"
+	"github.com/newrelic/nri-kubernetes/v3/internal/labels"
 	"github.com/newrelic/nri-kubernetes/v3/internal/logutil"
+	"github.com/newrelic/nri-kubernetes/v3/internal/storer"
 	"github.com/newrelic/nri-kubernetes/v3/src/client"
 	"github.com/newrelic/nri-kubernetes/v3/src/data"
+	"github.com/newrelic/nri-kubernetes/v3/src/dimensional"
 	"github.com/newrelic/nri-kubernetes/v3/src/kubelet/grouper"
 	kubeletMetric "github.com/newrelic/nri-kubernetes/v3/src/kubelet/metric"
 	"github.com/newrelic/nri-kubernetes/v3/src/metric"
 	"github.com/newrelic/nri-kubernetes/v3/src/network"
+	"github.com/newrelic/nri-kubernetes/v3/src/populator"
 	"github.com/newrelic/nri-kubernetes/v3/src/prometheus"
 	"github.com/newrelic/nri-kubernetes/v3/src/scrape"
 )
@@ -39,6 +46,14 @@ type Scraper struct {
 	defaultNetworkInterface string
 	nodeGetter              listersv1.NodeLister
 	informerClosers         []chan<- struct{}
+	samples                 *storer.InMemoryStore
+	definitions             metric.Definitions
+	labels                  *labels.Guard
+	entities                *entities.Filter
+	attributes              *attributes.Decorator
+	dimensional             *dimensional.Emitter
+	errors                  *populator.ErrorReporter
+	optOut                  *discovery.OptOutFilter
 	currentReruns           int
 	Filterer                discovery.NamespaceFilterer
 }
@@ -54,6 +69,7 @@ func NewScraper(config *config.Config, providers Providers, options ...ScraperOp
 		config:        config,
 		Providers:     providers,
 		logger:        logutil.Discard,
+		definitions:   metric.Builtin()[metric.TargetKubelet],
 		currentReruns: 0,
 	}
 
@@ -73,6 +89,20 @@ func NewScraper(config *config.Config, providers Providers, options ...ScraperOp
 		return nil, fmt.Errorf("fetching K8s version: %w", err)
 	}
 
+	s.labels, err = labels.NewGuard(config.Labels)
+	if err != nil {
+		return nil, fmt.Errorf("building label guard: %w", err)
+	}
+
+	s.entities, err = entities.NewFilter(config.Entities)
+	if err != nil {
+		return nil, fmt.Errorf("building entity filter: %w", err)
+	}
+
+	s.errors = populator.NewErrorReporter(config.PopulateErrors.WarnRatioChange)
+
+	s.samples = storer.NewInMemoryStore(storer.DefaultTTL, storer.DefaultInterval, s.logger)
+
 	nodeGetter, nodeCloser := discovery.NewNodeLister(providers.K8s)
 	s.nodeGetter = nodeGetter
 	s.informerClosers = append(s.informerClosers, nodeCloser)
@@ -86,8 +116,8 @@ func NewScraper(config *config.Config, providers Providers, options ...ScraperOp
 	return s, nil
 }
 
-// Run scraper collect the data populating the integration entities
-func (s *Scraper) Run(i *integration.Integration) error {
+// Run scraper collect the data populating the integration entities. Cancelling ctx aborts the requests to the kubelet.
+func (s *Scraper) Run(ctx context.Context, i *integration.Integration) error {
 	fetchAndFilterPrometheus := s.CAdvisor.MetricFamiliesGetFunc(kubeletMetric.KubeletCAdvisorMetricsPath)
 
 	podsFetcher := kubeletMetric.NewPodsFetcher(s.logger, s.Kubelet, s.config)
@@ -97,7 +127,7 @@ func (s *Scraper) Run(i *integration.Integration) error {
 			NodeGetter: s.nodeGetter,
 			Fetchers: []data.FetchFunc{
 				podsFetcher.DoPodsFetch,
-				kubeletMetric.CadvisorFetchFunc(fetchAndFilterPrometheus, metric.CadvisorQueries),
+				kubeletMetric.CadvisorFetchFunc(fetchAndFilterPrometheus, s.definitions.Queries),
 			},
 			DefaultNetworkInterface: s.defaultNetworkInterface,
 			PodsFetcher:             podsFetcher,
@@ -107,10 +137,21 @@ func (s *Scraper) Run(i *integration.Integration) error {
 		return fmt.Errorf("creating Kubelet grouper: %w", err)
 	}
 
-	job := scrape.NewScrapeJob("kubelet", kubeletGrouper, metric.KubeletSpecs, scrape.JobWithFilterer(s.Filterer))
-
-	r := job.Populate(i, s.config.ClusterName, s.logger, s.k8sVersion)
-	if r.Errors != nil {
+	job := scrape.NewScrapeJob("kubelet", kubeletGrouper, s.definitions.Specs,
+		scrape.JobWithFilterer(s.Filterer),
+		scrape.JobWithOptOutFilter(s.optOut),
+		scrape.JobWithSampleStore(s.samples),
+		scrape.JobWithLabelGuard(s.labels),
+		scrape.JobWithCustomAttributes(s.attributes),
+		scrape.JobWithEntityFilter(s.entities),
+		scrape.JobWithDimensionalEmitter(s.dimensional),
+		scrape.JobWithErrorReporter(s.errors),
+		scrape.JobWithWorkers(s.config.PopulateWorkers),
+	)
+
+	r := job.Populate(ctx, i, s.config.ClusterName, s.logger, s.k8sVersion)
+	// Errors of populated jobs are logged by the error reporter.
+	if r.Errors != nil && !r.Populated {
 		s.logger.Debugf("Errors while scraping Kubelet: %q", r.Errors)
 	}
 
@@ -129,6 +170,41 @@ func WithLogger(logger *log.Logger) ScraperOpt {
 	}
 }
 
+// WithCustomAttributes returns an OptionFunc to add the custom attributes computed by decorator to the entities the
+// scraper populates.
+func WithCustomAttributes(decorator *attributes.Decorator) ScraperOpt {
+	return func(s *Scraper) error {
+		s.attributes = decorator
+		return nil
+	}
+}
+
+// WithDimensionalEmitter returns an OptionFunc to report the metrics the scraper populates as dimensional metrics
+// collected by emitter, instead of metric sets of the integration.
+func WithDimensionalEmitter(emitter *dimensional.Emitter) ScraperOpt {
+	return func(s *Scraper) error {
+		s.dimensional = emitter
+		return nil
+	}
+}
+
+// WithDefinitions returns an OptionFunc to change the specs and queries the scraper uses from the builtin ones.
+func WithDefinitions(definitions metric.Definitions) ScraperOpt {
+	return func(s *Scraper) error {
+		s.definitions = definitions
+		return nil
+	}
+}
+
+// WithOptOutFilter returns an OptionFunc to skip the entities of namespaces and pods opting out of being scraped, as
+// told by filter, which is closed with the scraper.
+func WithOptOutFilter(filter *discovery.OptOutFilter) ScraperOpt {
+	return func(s *Scraper) error {
+		s.optOut = filter
+		return nil
+	}
+}
+
 // WithFilterer returns an OptionFunc to add a Filterer.
 func WithFilterer(filterer discovery.NamespaceFilterer) ScraperOpt {
 	return func(s *Scraper) error {
@@ -142,6 +218,9 @@ func (s *Scraper) Close() {
 	for _, ch := range s.informerClosers {
 		close(ch)
 	}
+
+	s.samples.StopVacuum()
+	_ = s.optOut.Close()
 }
 
 // Increase the kubelet currentReruns counter.
@@ -0,0 +1,141 @@
+package metric
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	sdkMetric "github.com/newrelic/infra-integrations-sdk/data/metric"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/config"
+	"github.com/newrelic/nri-kubernetes/v3/internal/expression"
+	"github.com/newrelic/nri-kubernetes/v3/src/definition"
+)
+
+// ErrInvalidComputedMetric is returned when a computed metric is incomplete, or its expression cannot be computed for
+// any of its entity types.
+var ErrInvalidComputedMetric = errors.New("invalid computed metric")
+
+// ComputeDefinitions returns a copy of defs with the computed metrics in c added to the groups named as their entity
+// types, in order. defs is not modified.
+//
+// Expressions are type-checked against the groups of every target, so a computed metric is only added to the groups
+// defining all the metrics it reads. Groups of the same entity type can be defined for several targets, like the
+// `container` ones of the kubelet and KSM, and a computed metric must be added to at least one group of each of its
+// entity types.
+func ComputeDefinitions(defs map[string]Definitions, c config.Metrics) (map[string]Definitions, error) {
+	computed := make(map[string]Definitions, len(defs))
+	for target, d := range defs {
+		computed[target] = d.clone()
+	}
+
+	for i, cm := range c.Computed {
+		if err := addComputedMetric(computed, cm); err != nil {
+			return nil, fmt.Errorf("computed metric #%d: %w", i, err)
+		}
+	}
+
+	return computed, nil
+}
+
+func addComputedMetric(defs map[string]Definitions, cm config.ComputedMetric) error {
+	if cm.Name == "" || cm.Expression == "" || len(cm.EntityTypes) == 0 {
+		return fmt.Errorf("%w: name, entityTypes and expression are required", ErrInvalidComputedMetric)
+	}
+
+	sourceType := sdkMetric.GAUGE
+	if cm.Type != "" {
+		var ok bool
+		if sourceType, ok = sdkMetric.SourcesNameToType[cm.Type]; !ok || sourceType == sdkMetric.ATTRIBUTE {
+			return fmt.Errorf("%w: %q has unknown or non-numeric type %q", ErrInvalidComputedMetric, cm.Name, cm.Type)
+		}
+	}
+
+	expr, err := expression.Parse(cm.Expression)
+	if err != nil {
+		return fmt.Errorf("%w: parsing expression of %q: %w", ErrInvalidComputedMetric, cm.Name, err)
+	}
+
+	spec := definition.ComputedSpec{
+		Name:        cm.Name,
+		ComputeFunc: computeFunc(expr),
+		Type:        sourceType,
+	}
+
+	for _, entityType := range cm.EntityTypes {
+		var checkErr error
+		added := false
+
+		for _, target := range slices.Sorted(maps.Keys(defs)) {
+			group, ok := defs[target].Specs[entityType]
+			if !ok {
+				continue
+			}
+
+			if err := checkComputedSpec(group, cm.Name, expr); err != nil {
+				if checkErr == nil {
+					checkErr = fmt.Errorf("target %q: %w", target, err)
+				}
+				continue
+			}
+
+			group.Computed = append(group.Computed, spec)
+			defs[target].Specs[entityType] = group
+			added = true
+		}
+
+		switch {
+		case added:
+		case checkErr != nil:
+			return fmt.Errorf("%w: %q for entity type %q: %w", ErrInvalidComputedMetric, cm.Name, entityType, checkErr)
+		default:
+			return fmt.Errorf("%w: %q: unknown entity type %q", ErrInvalidComputedMetric, cm.Name, entityType)
+		}
+	}
+
+	return nil
+}
+
+// checkComputedSpec type-checks expr against the gauge specs of group, and the gauges computed before it. Expressions
+// are evaluated with the values specs read, so other metrics, like rates and deltas, are not what they report.
+func checkComputedSpec(group definition.SpecGroup, name string, expr *expression.Expr) error {
+	types := map[string]sdkMetric.SourceType{}
+	for _, spec := range group.Specs {
+		types[spec.Name] = spec.Type
+	}
+	for _, spec := range group.Computed {
+		types[spec.Name] = spec.Type
+	}
+
+	if _, exists := types[name]; exists {
+		return fmt.Errorf("metric %q is already defined", name)
+	}
+
+	for _, v := range expr.Variables() {
+		if t, ok := types[v]; ok && t != sdkMetric.GAUGE {
+			return fmt.Errorf("%w: metric %q is a %s, only gauges can be read", expression.ErrType, v, sdkMetric.SourcesTypeToName[t])
+		}
+	}
+
+	return expr.Check(func(name string) bool { //nolint: wrapcheck
+		t, ok := types[name]
+		return ok && t == sdkMetric.GAUGE
+	})
+}
+
+// computeFunc returns a ComputeFunc evaluating expr, which returns no value if the entity is missing any metric expr
+// reads, or if the result is not a finite number, like after a division by zero.
+func computeFunc(expr *expression.Expr) definition.ComputeFunc {
+	return func(values map[string]float64) (definition.FetchedValue, error) {
+		v, err := expr.Eval(values)
+		if errors.Is(err, expression.ErrMissingValue) || errors.Is(err, expression.ErrNotFinite) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("evaluating %q: %w", expr, err)
+		}
+
+		return v, nil
+	}
+}
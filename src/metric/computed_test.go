@@ -0,0 +1,112 @@
+package metric_test
+
+import (
+	"testing"
+
+	sdkMetric "github.com/newrelic/infra-integrations-sdk/data/metric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/config"
+	"github.com/newrelic/nri-kubernetes/v3/internal/expression"
+	"github.com/newrelic/nri-kubernetes/v3/src/metric"
+)
+
+func TestComputeDefinitions(t *testing.T) {
+	t.Parallel()
+
+	defs, err := metric.ComputeDefinitions(metric.Builtin(), config.Metrics{Computed: []config.ComputedMetric{
+		{
+			Name:        "memoryWorkingSetRequestRatio",
+			EntityTypes: []string{"container"},
+			Expression:  "memoryWorkingSetBytes / memoryRequestedBytes",
+		},
+		{
+			Name:        "memoryWorkingSetRequestPercent",
+			EntityTypes: []string{"container"},
+			Expression:  "memoryWorkingSetRequestRatio * 100",
+			Type:        "rate",
+		},
+		{
+			Name:        "podsUnavailableRatio",
+			EntityTypes: []string{"deployment"},
+			Expression:  "if(podsDesired > 0, podsUnavailable / podsDesired, 0)",
+		},
+	}})
+	require.NoError(t, err)
+
+	// KSM containers have no working set, so the metrics are only computed for kubelet ones.
+	assert.Empty(t, defs[metric.TargetKSM].Specs["container"].Computed)
+	assert.Empty(t, metric.KubeletSpecs["container"].Computed, "Builtin definitions must not be modified")
+
+	computed := defs[metric.TargetKubelet].Specs["container"].Computed
+	require.Len(t, computed, 2)
+	assert.Equal(t, "memoryWorkingSetRequestRatio", computed[0].Name)
+	assert.Equal(t, sdkMetric.GAUGE, computed[0].Type)
+	assert.Equal(t, sdkMetric.RATE, computed[1].Type)
+
+	ratio, err := computed[0].ComputeFunc(map[string]float64{"memoryWorkingSetBytes": 50, "memoryRequestedBytes": 200})
+	require.NoError(t, err)
+	assert.Equal(t, 0.25, ratio)
+
+	missing, err := computed[0].ComputeFunc(map[string]float64{"memoryWorkingSetBytes": 50})
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	notFinite, err := computed[0].ComputeFunc(map[string]float64{"memoryWorkingSetBytes": 50, "memoryRequestedBytes": 0})
+	require.NoError(t, err)
+	assert.Nil(t, notFinite)
+
+	require.Len(t, defs[metric.TargetKSM].Specs["deployment"].Computed, 1)
+}
+
+func TestComputeDefinitions_Errors(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		computed config.ComputedMetric
+		expected error
+	}{
+		"missing_fields": {
+			computed: config.ComputedMetric{Name: "ratio", Expression: "1"},
+			expected: metric.ErrInvalidComputedMetric,
+		},
+		"unknown_type": {
+			computed: config.ComputedMetric{Name: "ratio", EntityTypes: []string{"pod"}, Expression: "1", Type: "attribute"},
+			expected: metric.ErrInvalidComputedMetric,
+		},
+		"syntax_error": {
+			computed: config.ComputedMetric{Name: "ratio", EntityTypes: []string{"pod"}, Expression: "1 +"},
+			expected: expression.ErrSyntax,
+		},
+		"unknown_metric": {
+			computed: config.ComputedMetric{Name: "ratio", EntityTypes: []string{"pod"}, Expression: "notAMetric / 2"},
+			expected: expression.ErrType,
+		},
+		"attribute_metric": {
+			computed: config.ComputedMetric{Name: "ratio", EntityTypes: []string{"pod"}, Expression: "nodeName * 2"},
+			expected: expression.ErrType,
+		},
+		"non_gauge_metric": {
+			computed: config.ComputedMetric{Name: "ratio", EntityTypes: []string{"api-server"}, Expression: "apiserverRequestsDelta * 2"},
+			expected: expression.ErrType,
+		},
+		"existing_metric": {
+			computed: config.ComputedMetric{Name: "isReady", EntityTypes: []string{"pod"}, Expression: "1"},
+			expected: metric.ErrInvalidComputedMetric,
+		},
+		"unknown_entity_type": {
+			computed: config.ComputedMetric{Name: "ratio", EntityTypes: []string{"notAnEntity"}, Expression: "1"},
+			expected: metric.ErrInvalidComputedMetric,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := metric.ComputeDefinitions(metric.Builtin(), config.Metrics{Computed: []config.ComputedMetric{tc.computed}})
+			require.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
@@ -39,7 +39,8 @@ var APIServerSpecs = definition.SpecGroups{
 					"apiserverRequestsDelta",
 					prometheus.IncludeOnlyLabelsFilter("verb", "code"),
 				),
-				Type: sdkMetric.DELTA,
+				RawMetrics: []string{"apiserver_request_total"},
+				Type:       sdkMetric.DELTA,
 			},
 			{
 				Name: "apiserverRequestsRate",
@@ -48,7 +49,8 @@ var APIServerSpecs = definition.SpecGroups{
 					"apiserverRequestsRate",
 					prometheus.IncludeOnlyLabelsFilter("verb", "code"),
 				),
-				Type: sdkMetric.RATE,
+				RawMetrics: []string{"apiserver_request_total"},
+				Type:       sdkMetric.RATE,
 			},
 			{
 				Name: "apiserverCurrentInflightRequestsMutating",
@@ -59,7 +61,8 @@ var APIServerSpecs = definition.SpecGroups{
 						"request_kind": "mutating",
 					}),
 				),
-				Type: sdkMetric.GAUGE,
+				RawMetrics: []string{"apiserver_current_inflight_requests"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
 				Name: "apiserverCurrentInflightRequestsReadOnly",
@@ -70,7 +73,8 @@ var APIServerSpecs = definition.SpecGroups{
 						"request_kind": "readOnly",
 					}),
 				),
-				Type: sdkMetric.GAUGE,
+				RawMetrics: []string{"apiserver_current_inflight_requests"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
 				Name: "restClientRequestsDelta",
@@ -79,7 +83,8 @@ var APIServerSpecs = definition.SpecGroups{
 					"restClientRequestsDelta",
 					prometheus.IncludeOnlyLabelsFilter("method", "code"),
 				),
-				Type: sdkMetric.DELTA,
+				RawMetrics: []string{"rest_client_requests_total"},
+				Type:       sdkMetric.DELTA,
 			},
 			{
 				Name: "restClientRequestsRate",
@@ -88,14 +93,16 @@ var APIServerSpecs = definition.SpecGroups{
 					"restClientRequestsRate",
 					prometheus.IncludeOnlyLabelsFilter("method", "code"),
 				),
-				Type: sdkMetric.RATE,
+				RawMetrics: []string{"rest_client_requests_total"},
+				Type:       sdkMetric.RATE,
 			},
 			// etcd_object_counts was deprecated in k8s 1.22 and removed in 1.23 (it is replaced by apiserver_storage_objects)
 			{
-				Name:      "etcdObjectCounts",
-				ValueFunc: prometheus.FromValueWithOverriddenName("etcd_object_counts", "etcdObjectCounts"),
-				Type:      sdkMetric.GAUGE,
-				Optional:  true,
+				Name:       "etcdObjectCounts",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("etcd_object_counts", "etcdObjectCounts"),
+				RawMetrics: []string{"etcd_object_counts"},
+				Type:       sdkMetric.GAUGE,
+				Optional:   true,
 			},
 			// apiserver_storage_objects was introduced in k8s 1.21 and replaces etcd_object_counts in 1.23
 			{
@@ -104,27 +111,32 @@ var APIServerSpecs = definition.SpecGroups{
 					prometheus.FromValueWithOverriddenName("apiserver_storage_objects", "apiserverStorageObjects"),
 					prometheus.FromValueWithOverriddenName("etcd_object_counts", "etcdObjectCounts"),
 				),
-				Type: sdkMetric.GAUGE,
+				RawMetrics: []string{"apiserver_storage_objects", "etcd_object_counts"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "processResidentMemoryBytes",
-				ValueFunc: prometheus.FromValueWithOverriddenName("process_resident_memory_bytes", "processResidentMemoryBytes"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "processResidentMemoryBytes",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("process_resident_memory_bytes", "processResidentMemoryBytes"),
+				RawMetrics: []string{"process_resident_memory_bytes"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "processCpuSecondsDelta",
-				ValueFunc: prometheus.FromValueWithOverriddenName("process_cpu_seconds_total", "processCpuSecondsDelta"),
-				Type:      sdkMetric.DELTA,
+				Name:       "processCpuSecondsDelta",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("process_cpu_seconds_total", "processCpuSecondsDelta"),
+				RawMetrics: []string{"process_cpu_seconds_total"},
+				Type:       sdkMetric.DELTA,
 			},
 			{
-				Name:      "goThreads",
-				ValueFunc: prometheus.FromValueWithOverriddenName("go_threads", "goThreads"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "goThreads",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("go_threads", "goThreads"),
+				RawMetrics: []string{"go_threads"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "goGoroutines",
-				ValueFunc: prometheus.FromValueWithOverriddenName("go_goroutines", "goGoroutines"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "goGoroutines",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("go_goroutines", "goGoroutines"),
+				RawMetrics: []string{"go_goroutines"},
+				Type:       sdkMetric.GAUGE,
 			},
 		},
 	},
@@ -170,22 +182,25 @@ var ControllerManagerSpecs = definition.SpecGroups{
 		TypeGenerator: prometheus.ControlPlaneComponentTypeGenerator,
 		Specs: []definition.Spec{
 			{
-				Name:      "workqueueAddsDelta",
-				ValueFunc: prometheus.FromValueWithOverriddenName("workqueue_adds_total", "workqueueAddsDelta"),
-				Type:      sdkMetric.DELTA,
-				Optional:  true,
+				Name:       "workqueueAddsDelta",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("workqueue_adds_total", "workqueueAddsDelta"),
+				RawMetrics: []string{"workqueue_adds_total"},
+				Type:       sdkMetric.DELTA,
+				Optional:   true,
 			},
 			{
-				Name:      "workqueueDepth",
-				ValueFunc: prometheus.FromValueWithOverriddenName("workqueue_depth", "workqueueDepth"),
-				Type:      sdkMetric.GAUGE,
-				Optional:  true,
+				Name:       "workqueueDepth",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("workqueue_depth", "workqueueDepth"),
+				RawMetrics: []string{"workqueue_depth"},
+				Type:       sdkMetric.GAUGE,
+				Optional:   true,
 			},
 			{
-				Name:      "workqueueRetriesDelta",
-				ValueFunc: prometheus.FromValueWithOverriddenName("workqueue_retries_total", "workqueueRetriesDelta"),
-				Type:      sdkMetric.DELTA,
-				Optional:  true,
+				Name:       "workqueueRetriesDelta",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("workqueue_retries_total", "workqueueRetriesDelta"),
+				RawMetrics: []string{"workqueue_retries_total"},
+				Type:       sdkMetric.DELTA,
+				Optional:   true,
 			},
 			{
 				Name: "leaderElectionMasterStatus",
@@ -194,27 +209,32 @@ var ControllerManagerSpecs = definition.SpecGroups{
 					"leaderElectionMasterStatus",
 					prometheus.IgnoreLabelsFilter("name"),
 				),
-				Type: sdkMetric.GAUGE,
+				RawMetrics: []string{"leader_election_master_status"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "processResidentMemoryBytes",
-				ValueFunc: prometheus.FromValueWithOverriddenName("process_resident_memory_bytes", "processResidentMemoryBytes"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "processResidentMemoryBytes",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("process_resident_memory_bytes", "processResidentMemoryBytes"),
+				RawMetrics: []string{"process_resident_memory_bytes"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "processCpuSecondsDelta",
-				ValueFunc: prometheus.FromValueWithOverriddenName("process_cpu_seconds_total", "processCpuSecondsDelta"),
-				Type:      sdkMetric.DELTA,
+				Name:       "processCpuSecondsDelta",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("process_cpu_seconds_total", "processCpuSecondsDelta"),
+				RawMetrics: []string{"process_cpu_seconds_total"},
+				Type:       sdkMetric.DELTA,
 			},
 			{
-				Name:      "goThreads",
-				ValueFunc: prometheus.FromValueWithOverriddenName("go_threads", "goThreads"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "goThreads",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("go_threads", "goThreads"),
+				RawMetrics: []string{"go_threads"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "goGoroutines",
-				ValueFunc: prometheus.FromValueWithOverriddenName("go_goroutines", "goGoroutines"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "goGoroutines",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("go_goroutines", "goGoroutines"),
+				RawMetrics: []string{"go_goroutines"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
 				Name: "nodeCollectorEvictionsDelta",
@@ -223,7 +243,8 @@ var ControllerManagerSpecs = definition.SpecGroups{
 					"nodeCollectorEvictionsDelta",
 					prometheus.IgnoreLabelsFilter("zone"),
 				),
-				Type: sdkMetric.PDELTA,
+				RawMetrics: []string{"node_collector_evictions_total"},
+				Type:       sdkMetric.PDELTA,
 			},
 		},
 	},
@@ -275,38 +296,45 @@ var SchedulerSpecs = definition.SpecGroups{
 					"leaderElectionMasterStatus",
 					prometheus.IgnoreLabelsFilter("name"),
 				),
-				Type: sdkMetric.GAUGE,
+				RawMetrics: []string{"leader_election_master_status"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "restClientRequestsDelta",
-				ValueFunc: prometheus.FromValueWithOverriddenName("rest_client_requests_total", "restClientRequestsDelta"),
-				Type:      sdkMetric.DELTA,
+				Name:       "restClientRequestsDelta",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("rest_client_requests_total", "restClientRequestsDelta"),
+				RawMetrics: []string{"rest_client_requests_total"},
+				Type:       sdkMetric.DELTA,
 			},
 			{
-				Name:      "restClientRequestsRate",
-				ValueFunc: prometheus.FromValueWithOverriddenName("rest_client_requests_total", "restClientRequestsRate"),
-				Type:      sdkMetric.RATE,
+				Name:       "restClientRequestsRate",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("rest_client_requests_total", "restClientRequestsRate"),
+				RawMetrics: []string{"rest_client_requests_total"},
+				Type:       sdkMetric.RATE,
 			},
 			{
-				Name:      "schedulerScheduleAttemptsDelta",
-				ValueFunc: prometheus.FromValueWithOverriddenName("scheduler_schedule_attempts_total", "schedulerScheduleAttemptsDelta"),
-				Type:      sdkMetric.DELTA,
+				Name:       "schedulerScheduleAttemptsDelta",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("scheduler_schedule_attempts_total", "schedulerScheduleAttemptsDelta"),
+				RawMetrics: []string{"scheduler_schedule_attempts_total"},
+				Type:       sdkMetric.DELTA,
 			},
 			{
-				Name:      "schedulerScheduleAttemptsRate",
-				ValueFunc: prometheus.FromValueWithOverriddenName("scheduler_schedule_attempts_total", "schedulerScheduleAttemptsRate"),
-				Type:      sdkMetric.RATE,
+				Name:       "schedulerScheduleAttemptsRate",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("scheduler_schedule_attempts_total", "schedulerScheduleAttemptsRate"),
+				RawMetrics: []string{"scheduler_schedule_attempts_total"},
+				Type:       sdkMetric.RATE,
 			},
 			{
-				Name:      "schedulerSchedulingDurationSeconds",
-				ValueFunc: prometheus.FromSummary("scheduler_scheduling_duration_seconds"),
-				Type:      sdkMetric.GAUGE,
-				Optional:  true,
+				Name:       "schedulerSchedulingDurationSeconds",
+				ValueFunc:  prometheus.FromSummary("scheduler_scheduling_duration_seconds"),
+				RawMetrics: []string{"scheduler_scheduling_duration_seconds"},
+				Type:       sdkMetric.GAUGE,
+				Optional:   true,
 			},
 			{
-				Name:      "schedulerPreemptionAttemptsDelta",
-				ValueFunc: prometheus.FromValueWithOverriddenName("scheduler_total_preemption_attempts", "schedulerPreemptionAttemptsDelta"),
-				Type:      sdkMetric.DELTA,
+				Name:       "schedulerPreemptionAttemptsDelta",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("scheduler_total_preemption_attempts", "schedulerPreemptionAttemptsDelta"),
+				RawMetrics: []string{"scheduler_total_preemption_attempts"},
+				Type:       sdkMetric.DELTA,
 			},
 			{
 				Name: "schedulerPendingPodsActive",
@@ -317,7 +345,8 @@ var SchedulerSpecs = definition.SpecGroups{
 						"queue": "active",
 					}),
 				),
-				Type: sdkMetric.GAUGE,
+				RawMetrics: []string{"scheduler_pending_pods"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
 				Name: "schedulerPendingPodsBackoff",
@@ -328,7 +357,8 @@ var SchedulerSpecs = definition.SpecGroups{
 						"queue": "backoff",
 					}),
 				),
-				Type: sdkMetric.GAUGE,
+				RawMetrics: []string{"scheduler_pending_pods"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
 				Name: "schedulerPendingPodsUnschedulable",
@@ -339,32 +369,38 @@ var SchedulerSpecs = definition.SpecGroups{
 						"queue": "unschedulable",
 					}),
 				),
-				Type: sdkMetric.GAUGE,
+				RawMetrics: []string{"scheduler_pending_pods"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "schedulerPodPreemptionVictims",
-				ValueFunc: prometheus.FromValueWithOverriddenName("scheduler_pod_preemption_victims", "schedulerPodPreemptionVictims"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "schedulerPodPreemptionVictims",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("scheduler_pod_preemption_victims", "schedulerPodPreemptionVictims"),
+				RawMetrics: []string{"scheduler_pod_preemption_victims"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "processResidentMemoryBytes",
-				ValueFunc: prometheus.FromValueWithOverriddenName("process_resident_memory_bytes", "processResidentMemoryBytes"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "processResidentMemoryBytes",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("process_resident_memory_bytes", "processResidentMemoryBytes"),
+				RawMetrics: []string{"process_resident_memory_bytes"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "processCpuSecondsDelta",
-				ValueFunc: prometheus.FromValueWithOverriddenName("process_cpu_seconds_total", "processCpuSecondsDelta"),
-				Type:      sdkMetric.DELTA,
+				Name:       "processCpuSecondsDelta",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("process_cpu_seconds_total", "processCpuSecondsDelta"),
+				RawMetrics: []string{"process_cpu_seconds_total"},
+				Type:       sdkMetric.DELTA,
 			},
 			{
-				Name:      "goThreads",
-				ValueFunc: prometheus.FromValueWithOverriddenName("go_threads", "goThreads"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "goThreads",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("go_threads", "goThreads"),
+				RawMetrics: []string{"go_threads"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "goGoroutines",
-				ValueFunc: prometheus.FromValueWithOverriddenName("go_goroutines", "goGoroutines"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "goGoroutines",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("go_goroutines", "goGoroutines"),
+				RawMetrics: []string{"go_goroutines"},
+				Type:       sdkMetric.GAUGE,
 			},
 		},
 	},
@@ -416,100 +452,119 @@ var EtcdSpecs = definition.SpecGroups{
 		TypeGenerator: prometheus.ControlPlaneComponentTypeGenerator,
 		Specs: []definition.Spec{
 			{
-				Name:      "etcdServerHasLeader",
-				ValueFunc: prometheus.FromValueWithOverriddenName("etcd_server_has_leader", "etcdServerHasLeader"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "etcdServerHasLeader",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("etcd_server_has_leader", "etcdServerHasLeader"),
+				RawMetrics: []string{"etcd_server_has_leader"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "etcdServerLeaderChangesSeenDelta",
-				ValueFunc: prometheus.FromValueWithOverriddenName("etcd_server_leader_changes_seen_total", "etcdServerLeaderChangesSeenDelta"),
-				Type:      sdkMetric.DELTA,
+				Name:       "etcdServerLeaderChangesSeenDelta",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("etcd_server_leader_changes_seen_total", "etcdServerLeaderChangesSeenDelta"),
+				RawMetrics: []string{"etcd_server_leader_changes_seen_total"},
+				Type:       sdkMetric.DELTA,
 			},
 			{
-				Name:      "etcdMvccDbTotalSizeInBytes",
-				ValueFunc: prometheus.FromValueWithOverriddenName("etcd_mvcc_db_total_size_in_bytes", "etcdMvccDbTotalSizeInBytes"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "etcdMvccDbTotalSizeInBytes",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("etcd_mvcc_db_total_size_in_bytes", "etcdMvccDbTotalSizeInBytes"),
+				RawMetrics: []string{"etcd_mvcc_db_total_size_in_bytes"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "etcdServerProposalsCommittedRate",
-				ValueFunc: prometheus.FromValueWithOverriddenName("etcd_server_proposals_committed_total", "etcdServerProposalsCommittedRate"),
-				Type:      sdkMetric.RATE,
+				Name:       "etcdServerProposalsCommittedRate",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("etcd_server_proposals_committed_total", "etcdServerProposalsCommittedRate"),
+				RawMetrics: []string{"etcd_server_proposals_committed_total"},
+				Type:       sdkMetric.RATE,
 			},
 			{
-				Name:      "etcdServerProposalsCommittedDelta",
-				ValueFunc: prometheus.FromValueWithOverriddenName("etcd_server_proposals_committed_total", "etcdServerProposalsCommittedDelta"),
-				Type:      sdkMetric.DELTA,
+				Name:       "etcdServerProposalsCommittedDelta",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("etcd_server_proposals_committed_total", "etcdServerProposalsCommittedDelta"),
+				RawMetrics: []string{"etcd_server_proposals_committed_total"},
+				Type:       sdkMetric.DELTA,
 			},
 			{
-				Name:      "etcdServerProposalsAppliedRate",
-				ValueFunc: prometheus.FromValueWithOverriddenName("etcd_server_proposals_applied_total", "etcdServerProposalsAppliedRate"),
-				Type:      sdkMetric.RATE,
+				Name:       "etcdServerProposalsAppliedRate",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("etcd_server_proposals_applied_total", "etcdServerProposalsAppliedRate"),
+				RawMetrics: []string{"etcd_server_proposals_applied_total"},
+				Type:       sdkMetric.RATE,
 			},
 			{
-				Name:      "etcdServerProposalsAppliedDelta",
-				ValueFunc: prometheus.FromValueWithOverriddenName("etcd_server_proposals_applied_total", "etcdServerProposalsAppliedDelta"),
-				Type:      sdkMetric.DELTA,
+				Name:       "etcdServerProposalsAppliedDelta",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("etcd_server_proposals_applied_total", "etcdServerProposalsAppliedDelta"),
+				RawMetrics: []string{"etcd_server_proposals_applied_total"},
+				Type:       sdkMetric.DELTA,
 			},
 			{
-				Name:      "etcdServerProposalsPending",
-				ValueFunc: prometheus.FromValueWithOverriddenName("etcd_server_proposals_pending", "etcdServerProposalsPending"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "etcdServerProposalsPending",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("etcd_server_proposals_pending", "etcdServerProposalsPending"),
+				RawMetrics: []string{"etcd_server_proposals_pending"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "etcdServerProposalsFailedRate",
-				ValueFunc: prometheus.FromValueWithOverriddenName("etcd_server_proposals_failed_total", "etcdServerProposalsFailedRate"),
-				Type:      sdkMetric.RATE,
+				Name:       "etcdServerProposalsFailedRate",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("etcd_server_proposals_failed_total", "etcdServerProposalsFailedRate"),
+				RawMetrics: []string{"etcd_server_proposals_failed_total"},
+				Type:       sdkMetric.RATE,
 			},
 			{
-				Name:      "etcdServerProposalsFailedDelta",
-				ValueFunc: prometheus.FromValueWithOverriddenName("etcd_server_proposals_failed_total", "etcdServerProposalsFailedDelta"),
-				Type:      sdkMetric.DELTA,
+				Name:       "etcdServerProposalsFailedDelta",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("etcd_server_proposals_failed_total", "etcdServerProposalsFailedDelta"),
+				RawMetrics: []string{"etcd_server_proposals_failed_total"},
+				Type:       sdkMetric.DELTA,
 			},
 			{
-				Name:      "processOpenFds",
-				ValueFunc: processOpenFds,
-				Type:      sdkMetric.GAUGE,
+				Name:       "processOpenFds",
+				ValueFunc:  processOpenFds,
+				RawMetrics: []string{"process_open_fds"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "processMaxFds",
-				ValueFunc: processMaxFds,
-				Type:      sdkMetric.GAUGE,
+				Name:       "processMaxFds",
+				ValueFunc:  processMaxFds,
+				RawMetrics: []string{"process_max_fds"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "etcdNetworkClientGrpcReceivedBytesRate",
-				ValueFunc: prometheus.FromValueWithOverriddenName("etcd_network_client_grpc_received_bytes_total", "etcdNetworkClientGrpcReceivedBytesRate"),
-				Type:      sdkMetric.RATE,
+				Name:       "etcdNetworkClientGrpcReceivedBytesRate",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("etcd_network_client_grpc_received_bytes_total", "etcdNetworkClientGrpcReceivedBytesRate"),
+				RawMetrics: []string{"etcd_network_client_grpc_received_bytes_total"},
+				Type:       sdkMetric.RATE,
 			},
 			{
-				Name:      "etcdNetworkClientGrpcSentBytesRate",
-				ValueFunc: prometheus.FromValueWithOverriddenName("etcd_network_client_grpc_sent_bytes_total", "etcdNetworkClientGrpcSentBytesRate"),
-				Type:      sdkMetric.RATE,
+				Name:       "etcdNetworkClientGrpcSentBytesRate",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("etcd_network_client_grpc_sent_bytes_total", "etcdNetworkClientGrpcSentBytesRate"),
+				RawMetrics: []string{"etcd_network_client_grpc_sent_bytes_total"},
+				Type:       sdkMetric.RATE,
 			},
 			{
-				Name:      "processResidentMemoryBytes",
-				ValueFunc: prometheus.FromValueWithOverriddenName("process_resident_memory_bytes", "processResidentMemoryBytes"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "processResidentMemoryBytes",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("process_resident_memory_bytes", "processResidentMemoryBytes"),
+				RawMetrics: []string{"process_resident_memory_bytes"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "processCpuSecondsDelta",
-				ValueFunc: prometheus.FromValueWithOverriddenName("process_cpu_seconds_total", "processCpuSecondsDelta"),
-				Type:      sdkMetric.DELTA,
+				Name:       "processCpuSecondsDelta",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("process_cpu_seconds_total", "processCpuSecondsDelta"),
+				RawMetrics: []string{"process_cpu_seconds_total"},
+				Type:       sdkMetric.DELTA,
 			},
 			{
-				Name:      "goThreads",
-				ValueFunc: prometheus.FromValueWithOverriddenName("go_threads", "goThreads"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "goThreads",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("go_threads", "goThreads"),
+				RawMetrics: []string{"go_threads"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "goGoroutines",
-				ValueFunc: prometheus.FromValueWithOverriddenName("go_goroutines", "goGoroutines"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "goGoroutines",
+				ValueFunc:  prometheus.FromValueWithOverriddenName("go_goroutines", "goGoroutines"),
+				RawMetrics: []string{"go_goroutines"},
+				Type:       sdkMetric.GAUGE,
 			},
 			// computed
 			{
-				Name:      "processFdsUtilization",
-				ValueFunc: toUtilization(processOpenFds, processMaxFds),
-				Type:      sdkMetric.GAUGE,
+				Name:       "processFdsUtilization",
+				ValueFunc:  toUtilization(processOpenFds, processMaxFds),
+				RawMetrics: []string{"process_open_fds", "process_max_fds"},
+				Type:       sdkMetric.GAUGE,
 			},
 		},
 	},
@@ -574,53 +629,55 @@ var KSMSpecs = definition.SpecGroups{
 		TypeGenerator:   prometheus.FromLabelValueEntityTypeGeneratorWithCustomGroup("kube_persistentvolume_info", "PersistentVolume"),
 		NamespaceGetter: prometheus.FromLabelGetNamespace,
 		MsTypeGuesser:   metricSetTypeGuesserWithCustomGroup("PersistentVolume"),
+		RawMetrics:      []string{"kube_persistentvolume_info"},
 		Specs: []definition.Spec{
-			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_persistentvolume_created"), Type: sdkMetric.GAUGE},
-			{Name: "capacityBytes", ValueFunc: prometheus.FromValue("kube_persistentvolume_capacity_bytes"), Type: sdkMetric.GAUGE},
-			{Name: "statusPhase", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_status_phase", "phase"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "volumeName", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "persistentvolume"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "pvcName", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_claim_ref", "name"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "pvcNamespace", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_claim_ref", "claim_namespace"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_persistentvolume_labels", "label"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "storageClass", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "storageclass"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "hostPath", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "host_path"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "hostPathType", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "host_path_type"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "localFs", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "local_fs"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "localPath", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "local_path"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "csiVolumeHandle", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "csi_volume_handle"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "csiDriver", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "csi_driver"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "nfsPath", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "nfs_path"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "nfsServer", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "nfs_server"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "iscsiInitiatorName", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "iscsi_initiator_name"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "iscsiLun", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "iscsi_lun"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "iscsiIqn", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "iscsi_iqn"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "iscsiTargetPortal", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "iscsi_target_portal"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "fcTargetWwns", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "fc_target_wwns"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "fcLun", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "fc_lun"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "fcWwids", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "fc_wwids"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "azureDiskName", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "azure_disk_name"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "ebsVolumeId", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "ebs_volume_id"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "gcePersistentDiskName", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "gce_persistent_disk_name"), Type: sdkMetric.ATTRIBUTE},
+			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_persistentvolume_created"), RawMetrics: []string{"kube_persistentvolume_created"}, Type: sdkMetric.GAUGE},
+			{Name: "capacityBytes", ValueFunc: prometheus.FromValue("kube_persistentvolume_capacity_bytes"), RawMetrics: []string{"kube_persistentvolume_capacity_bytes"}, Type: sdkMetric.GAUGE},
+			{Name: "statusPhase", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_status_phase", "phase"), RawMetrics: []string{"kube_persistentvolume_status_phase"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "volumeName", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "persistentvolume"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "pvcName", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_claim_ref", "name"), RawMetrics: []string{"kube_persistentvolume_claim_ref"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "pvcNamespace", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_claim_ref", "claim_namespace"), RawMetrics: []string{"kube_persistentvolume_claim_ref"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_persistentvolume_labels", "label"), RawMetrics: []string{"kube_persistentvolume_labels"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "storageClass", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "storageclass"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "hostPath", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "host_path"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "hostPathType", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "host_path_type"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "localFs", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "local_fs"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "localPath", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "local_path"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "csiVolumeHandle", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "csi_volume_handle"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "csiDriver", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "csi_driver"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "nfsPath", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "nfs_path"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "nfsServer", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "nfs_server"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "iscsiInitiatorName", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "iscsi_initiator_name"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "iscsiLun", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "iscsi_lun"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "iscsiIqn", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "iscsi_iqn"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "iscsiTargetPortal", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "iscsi_target_portal"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "fcTargetWwns", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "fc_target_wwns"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "fcLun", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "fc_lun"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "fcWwids", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "fc_wwids"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "azureDiskName", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "azure_disk_name"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "ebsVolumeId", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "ebs_volume_id"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "gcePersistentDiskName", ValueFunc: prometheus.FromLabelValue("kube_persistentvolume_info", "gce_persistent_disk_name"), RawMetrics: []string{"kube_persistentvolume_info"}, Type: sdkMetric.ATTRIBUTE},
 		},
 	},
 	"cronjob": {
 		IDGenerator:     prometheus.FromLabelValueEntityIDGenerator("kube_cronjob_created", "cronjob"),
 		TypeGenerator:   prometheus.FromLabelValueEntityTypeGenerator("kube_cronjob_created"),
 		NamespaceGetter: prometheus.FromLabelGetNamespace,
+		RawMetrics:      []string{"kube_cronjob_created"},
 		Specs: []definition.Spec{
-			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_cronjob_created"), Type: sdkMetric.GAUGE},
-			{Name: "isActive", ValueFunc: prometheus.FromValue("kube_cronjob_status_active"), Type: sdkMetric.GAUGE},
-			{Name: "nextScheduledTime", ValueFunc: prometheus.FromValue("kube_cronjob_next_schedule_time"), Type: sdkMetric.GAUGE},
-			{Name: "lastScheduledTime", ValueFunc: prometheus.FromValue("kube_cronjob_status_last_schedule_time"), Type: sdkMetric.GAUGE},
-			{Name: "isSuspended", ValueFunc: prometheus.FromValue("kube_cronjob_spec_suspend"), Type: sdkMetric.GAUGE},
-			{Name: "specStartingDeadlineSeconds", ValueFunc: prometheus.FromValue("kube_cronjob_spec_starting_deadline_seconds"), Type: sdkMetric.GAUGE},
-			{Name: "metadataResourceVersion", ValueFunc: prometheus.FromValue("kube_cronjob_metadata_resource_version"), Type: sdkMetric.GAUGE},
-			{Name: "cronjobName", ValueFunc: prometheus.FromLabelValue("kube_cronjob_created", "cronjob"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "namespace", ValueFunc: prometheus.FromLabelValue("kube_cronjob_created", "namespace"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_cronjob_created", "namespace"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_cronjob_labels", "label"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "schedule", ValueFunc: prometheus.FromLabelValue("kube_cronjob_info", "schedule"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "concurrencyPolicy", ValueFunc: prometheus.FromLabelValue("kube_cronjob_info", "concurrency_policy"), Type: sdkMetric.ATTRIBUTE},
+			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_cronjob_created"), RawMetrics: []string{"kube_cronjob_created"}, Type: sdkMetric.GAUGE},
+			{Name: "isActive", ValueFunc: prometheus.FromValue("kube_cronjob_status_active"), RawMetrics: []string{"kube_cronjob_status_active"}, Type: sdkMetric.GAUGE},
+			{Name: "nextScheduledTime", ValueFunc: prometheus.FromValue("kube_cronjob_next_schedule_time"), RawMetrics: []string{"kube_cronjob_next_schedule_time"}, Type: sdkMetric.GAUGE},
+			{Name: "lastScheduledTime", ValueFunc: prometheus.FromValue("kube_cronjob_status_last_schedule_time"), RawMetrics: []string{"kube_cronjob_status_last_schedule_time"}, Type: sdkMetric.GAUGE},
+			{Name: "isSuspended", ValueFunc: prometheus.FromValue("kube_cronjob_spec_suspend"), RawMetrics: []string{"kube_cronjob_spec_suspend"}, Type: sdkMetric.GAUGE},
+			{Name: "specStartingDeadlineSeconds", ValueFunc: prometheus.FromValue("kube_cronjob_spec_starting_deadline_seconds"), RawMetrics: []string{"kube_cronjob_spec_starting_deadline_seconds"}, Type: sdkMetric.GAUGE},
+			{Name: "metadataResourceVersion", ValueFunc: prometheus.FromValue("kube_cronjob_metadata_resource_version"), RawMetrics: []string{"kube_cronjob_metadata_resource_version"}, Type: sdkMetric.GAUGE},
+			{Name: "cronjobName", ValueFunc: prometheus.FromLabelValue("kube_cronjob_created", "cronjob"), RawMetrics: []string{"kube_cronjob_created"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "namespace", ValueFunc: prometheus.FromLabelValue("kube_cronjob_created", "namespace"), RawMetrics: []string{"kube_cronjob_created"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_cronjob_created", "namespace"), RawMetrics: []string{"kube_cronjob_created"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_cronjob_labels", "label"), RawMetrics: []string{"kube_cronjob_labels"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "schedule", ValueFunc: prometheus.FromLabelValue("kube_cronjob_info", "schedule"), RawMetrics: []string{"kube_cronjob_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "concurrencyPolicy", ValueFunc: prometheus.FromLabelValue("kube_cronjob_info", "concurrency_policy"), RawMetrics: []string{"kube_cronjob_info"}, Type: sdkMetric.ATTRIBUTE},
 		},
 	},
 	"job_name": {
@@ -628,26 +685,27 @@ var KSMSpecs = definition.SpecGroups{
 		TypeGenerator:   prometheus.FromLabelValueEntityTypeGeneratorWithCustomGroup("kube_job_created", "job"),
 		NamespaceGetter: prometheus.FromLabelGetNamespace,
 		MsTypeGuesser:   metricSetTypeGuesserWithCustomGroup("job"),
+		RawMetrics:      []string{"kube_job_created"},
 		Specs: []definition.Spec{
-			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_job_created"), Type: sdkMetric.GAUGE},
-			{Name: "startedAt", ValueFunc: prometheus.FromValue("kube_job_status_start_time"), Type: sdkMetric.GAUGE},
-			{Name: "completedAt", ValueFunc: prometheus.FromValue("kube_job_status_completion_time"), Type: sdkMetric.GAUGE},
-			{Name: "specParallelism", ValueFunc: prometheus.FromValue("kube_job_spec_parallelism"), Type: sdkMetric.GAUGE},
-			{Name: "specCompletions", ValueFunc: prometheus.FromValue("kube_job_spec_completions"), Type: sdkMetric.GAUGE},
-			{Name: "specActiveDeadlineSeconds", ValueFunc: prometheus.FromValue("kube_job_spec_active_deadline_seconds"), Type: sdkMetric.GAUGE},
-			{Name: "activePods", ValueFunc: prometheus.FromValue("kube_job_status_active"), Type: sdkMetric.GAUGE},
-			{Name: "succeededPods", ValueFunc: prometheus.FromValue("kube_job_status_succeeded"), Type: sdkMetric.GAUGE},
-			{Name: "failedPods", ValueFunc: prometheus.FromValue("kube_job_status_failed"), Type: sdkMetric.GAUGE},
-			{Name: "isComplete", ValueFunc: prometheus.FromLabelValue("kube_job_complete", "condition"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "failed", ValueFunc: prometheus.FromLabelValue("kube_job_failed", "condition"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "failedPodsReason", ValueFunc: prometheus.FromLabelValue("kube_job_status_failed", "reason"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "ownerName", ValueFunc: prometheus.FromLabelValue("kube_job_owner", "owner_name"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "ownerKind", ValueFunc: prometheus.FromLabelValue("kube_job_owner", "owner_kind"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "ownerIsController", ValueFunc: prometheus.FromLabelValue("kube_job_owner", "owner_is_controller"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "jobName", ValueFunc: prometheus.FromLabelValue("kube_job_created", "job_name"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "namespace", ValueFunc: prometheus.FromLabelValue("kube_job_created", "namespace"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_job_created", "namespace"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_job_labels", "label"), Type: sdkMetric.ATTRIBUTE},
+			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_job_created"), RawMetrics: []string{"kube_job_created"}, Type: sdkMetric.GAUGE},
+			{Name: "startedAt", ValueFunc: prometheus.FromValue("kube_job_status_start_time"), RawMetrics: []string{"kube_job_status_start_time"}, Type: sdkMetric.GAUGE},
+			{Name: "completedAt", ValueFunc: prometheus.FromValue("kube_job_status_completion_time"), RawMetrics: []string{"kube_job_status_completion_time"}, Type: sdkMetric.GAUGE},
+			{Name: "specParallelism", ValueFunc: prometheus.FromValue("kube_job_spec_parallelism"), RawMetrics: []string{"kube_job_spec_parallelism"}, Type: sdkMetric.GAUGE},
+			{Name: "specCompletions", ValueFunc: prometheus.FromValue("kube_job_spec_completions"), RawMetrics: []string{"kube_job_spec_completions"}, Type: sdkMetric.GAUGE},
+			{Name: "specActiveDeadlineSeconds", ValueFunc: prometheus.FromValue("kube_job_spec_active_deadline_seconds"), RawMetrics: []string{"kube_job_spec_active_deadline_seconds"}, Type: sdkMetric.GAUGE},
+			{Name: "activePods", ValueFunc: prometheus.FromValue("kube_job_status_active"), RawMetrics: []string{"kube_job_status_active"}, Type: sdkMetric.GAUGE},
+			{Name: "succeededPods", ValueFunc: prometheus.FromValue("kube_job_status_succeeded"), RawMetrics: []string{"kube_job_status_succeeded"}, Type: sdkMetric.GAUGE},
+			{Name: "failedPods", ValueFunc: prometheus.FromValue("kube_job_status_failed"), RawMetrics: []string{"kube_job_status_failed"}, Type: sdkMetric.GAUGE},
+			{Name: "isComplete", ValueFunc: prometheus.FromLabelValue("kube_job_complete", "condition"), RawMetrics: []string{"kube_job_complete"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "failed", ValueFunc: prometheus.FromLabelValue("kube_job_failed", "condition"), RawMetrics: []string{"kube_job_failed"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "failedPodsReason", ValueFunc: prometheus.FromLabelValue("kube_job_status_failed", "reason"), RawMetrics: []string{"kube_job_status_failed"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "ownerName", ValueFunc: prometheus.FromLabelValue("kube_job_owner", "owner_name"), RawMetrics: []string{"kube_job_owner"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "ownerKind", ValueFunc: prometheus.FromLabelValue("kube_job_owner", "owner_kind"), RawMetrics: []string{"kube_job_owner"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "ownerIsController", ValueFunc: prometheus.FromLabelValue("kube_job_owner", "owner_is_controller"), RawMetrics: []string{"kube_job_owner"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "jobName", ValueFunc: prometheus.FromLabelValue("kube_job_created", "job_name"), RawMetrics: []string{"kube_job_created"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "namespace", ValueFunc: prometheus.FromLabelValue("kube_job_created", "namespace"), RawMetrics: []string{"kube_job_created"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_job_created", "namespace"), RawMetrics: []string{"kube_job_created"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_job_labels", "label"), RawMetrics: []string{"kube_job_labels"}, Type: sdkMetric.ATTRIBUTE},
 		},
 	},
 	"persistentvolumeclaim": {
@@ -657,47 +715,50 @@ var KSMSpecs = definition.SpecGroups{
 		TypeGenerator:   prometheus.FromLabelValueEntityTypeGeneratorWithCustomGroup("kube_persistentvolumeclaim_info", "PersistentVolumeClaim"),
 		NamespaceGetter: prometheus.FromLabelGetNamespace,
 		MsTypeGuesser:   metricSetTypeGuesserWithCustomGroup("PersistentVolumeClaim"),
+		RawMetrics:      []string{"kube_persistentvolumeclaim_info"},
 		Specs: []definition.Spec{
 			// createdAt is marked as optional because it is an experimental metric and not available in older KSM versions
-			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_persistentvolumeclaim_created"), Type: sdkMetric.GAUGE},
-			{Name: "requestedStorageBytes", ValueFunc: prometheus.FromValue("kube_persistentvolumeclaim_resource_requests_storage_bytes"), Type: sdkMetric.GAUGE},
-			{Name: "accessMode", ValueFunc: prometheus.FromLabelValue("kube_persistentvolumeclaim_access_mode", "access_mode"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "statusPhase", ValueFunc: prometheus.FromLabelValue("kube_persistentvolumeclaim_status_phase", "phase"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "storageClass", ValueFunc: prometheus.FromLabelValue("kube_persistentvolumeclaim_info", "storageclass"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "pvcName", ValueFunc: prometheus.FromLabelValue("kube_persistentvolumeclaim_info", "persistentvolumeclaim"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "volumeName", ValueFunc: prometheus.FromLabelValue("kube_persistentvolumeclaim_info", "volumename"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "namespace", ValueFunc: prometheus.FromLabelValue("kube_persistentvolumeclaim_info", "namespace"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_persistentvolumeclaim_info", "namespace"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_persistentvolumeclaim_labels", "label"), Type: sdkMetric.ATTRIBUTE},
+			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_persistentvolumeclaim_created"), RawMetrics: []string{"kube_persistentvolumeclaim_created"}, Type: sdkMetric.GAUGE},
+			{Name: "requestedStorageBytes", ValueFunc: prometheus.FromValue("kube_persistentvolumeclaim_resource_requests_storage_bytes"), RawMetrics: []string{"kube_persistentvolumeclaim_resource_requests_storage_bytes"}, Type: sdkMetric.GAUGE},
+			{Name: "accessMode", ValueFunc: prometheus.FromLabelValue("kube_persistentvolumeclaim_access_mode", "access_mode"), RawMetrics: []string{"kube_persistentvolumeclaim_access_mode"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "statusPhase", ValueFunc: prometheus.FromLabelValue("kube_persistentvolumeclaim_status_phase", "phase"), RawMetrics: []string{"kube_persistentvolumeclaim_status_phase"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "storageClass", ValueFunc: prometheus.FromLabelValue("kube_persistentvolumeclaim_info", "storageclass"), RawMetrics: []string{"kube_persistentvolumeclaim_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "pvcName", ValueFunc: prometheus.FromLabelValue("kube_persistentvolumeclaim_info", "persistentvolumeclaim"), RawMetrics: []string{"kube_persistentvolumeclaim_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "volumeName", ValueFunc: prometheus.FromLabelValue("kube_persistentvolumeclaim_info", "volumename"), RawMetrics: []string{"kube_persistentvolumeclaim_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "namespace", ValueFunc: prometheus.FromLabelValue("kube_persistentvolumeclaim_info", "namespace"), RawMetrics: []string{"kube_persistentvolumeclaim_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_persistentvolumeclaim_info", "namespace"), RawMetrics: []string{"kube_persistentvolumeclaim_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_persistentvolumeclaim_labels", "label"), RawMetrics: []string{"kube_persistentvolumeclaim_labels"}, Type: sdkMetric.ATTRIBUTE},
 		},
 	},
 	"replicaset": {
 		IDGenerator:     prometheus.FromLabelValueEntityIDGenerator("kube_replicaset_created", "replicaset"),
 		TypeGenerator:   prometheus.FromLabelValueEntityTypeGenerator("kube_replicaset_created"),
 		NamespaceGetter: prometheus.FromLabelGetNamespace,
+		RawMetrics:      []string{"kube_replicaset_created"},
 		Specs: []definition.Spec{
-			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_replicaset_created"), Type: sdkMetric.GAUGE},
-			{Name: "podsDesired", ValueFunc: prometheus.FromValue("kube_replicaset_spec_replicas"), Type: sdkMetric.GAUGE},
-			{Name: "podsReady", ValueFunc: prometheus.FromValue("kube_replicaset_status_ready_replicas"), Type: sdkMetric.GAUGE},
-			{Name: "podsTotal", ValueFunc: prometheus.FromValue("kube_replicaset_status_replicas"), Type: sdkMetric.GAUGE},
-			{Name: "podsFullyLabeled", ValueFunc: prometheus.FromValue("kube_replicaset_status_fully_labeled_replicas"), Type: sdkMetric.GAUGE},
-			{Name: "observedGeneration", ValueFunc: prometheus.FromValue("kube_replicaset_status_observed_generation"), Type: sdkMetric.GAUGE},
-			{Name: "metadataGeneration", ValueFunc: prometheus.FromValue("kube_replicaset_metadata_generation"), Type: sdkMetric.GAUGE},
-			{Name: "replicasetName", ValueFunc: prometheus.FromLabelValue("kube_replicaset_created", "replicaset"), Type: sdkMetric.ATTRIBUTE},
+			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_replicaset_created"), RawMetrics: []string{"kube_replicaset_created"}, Type: sdkMetric.GAUGE},
+			{Name: "podsDesired", ValueFunc: prometheus.FromValue("kube_replicaset_spec_replicas"), RawMetrics: []string{"kube_replicaset_spec_replicas"}, Type: sdkMetric.GAUGE},
+			{Name: "podsReady", ValueFunc: prometheus.FromValue("kube_replicaset_status_ready_replicas"), RawMetrics: []string{"kube_replicaset_status_ready_replicas"}, Type: sdkMetric.GAUGE},
+			{Name: "podsTotal", ValueFunc: prometheus.FromValue("kube_replicaset_status_replicas"), RawMetrics: []string{"kube_replicaset_status_replicas"}, Type: sdkMetric.GAUGE},
+			{Name: "podsFullyLabeled", ValueFunc: prometheus.FromValue("kube_replicaset_status_fully_labeled_replicas"), RawMetrics: []string{"kube_replicaset_status_fully_labeled_replicas"}, Type: sdkMetric.GAUGE},
+			{Name: "observedGeneration", ValueFunc: prometheus.FromValue("kube_replicaset_status_observed_generation"), RawMetrics: []string{"kube_replicaset_status_observed_generation"}, Type: sdkMetric.GAUGE},
+			{Name: "metadataGeneration", ValueFunc: prometheus.FromValue("kube_replicaset_metadata_generation"), RawMetrics: []string{"kube_replicaset_metadata_generation"}, Type: sdkMetric.GAUGE},
+			{Name: "replicasetName", ValueFunc: prometheus.FromLabelValue("kube_replicaset_created", "replicaset"), RawMetrics: []string{"kube_replicaset_created"}, Type: sdkMetric.ATTRIBUTE},
 			// namespace is here for backwards compatibility, we should use the namespaceName
-			{Name: "namespace", ValueFunc: prometheus.FromLabelValue("kube_replicaset_created", "namespace"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_replicaset_created", "namespace"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "deploymentName", ValueFunc: ksmMetric.GetDeploymentNameForReplicaSet(), Type: sdkMetric.ATTRIBUTE},
-			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_replicaset_labels", "label"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "ownerName", ValueFunc: prometheus.FromLabelValue("kube_replicaset_owner", "owner_name"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "ownerKind", ValueFunc: prometheus.FromLabelValue("kube_replicaset_owner", "owner_kind"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "ownerIsController", ValueFunc: prometheus.FromLabelValue("kube_replicaset_owner", "owner_is_controller"), Type: sdkMetric.ATTRIBUTE},
+			{Name: "namespace", ValueFunc: prometheus.FromLabelValue("kube_replicaset_created", "namespace"), RawMetrics: []string{"kube_replicaset_created"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_replicaset_created", "namespace"), RawMetrics: []string{"kube_replicaset_created"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "deploymentName", ValueFunc: ksmMetric.GetDeploymentNameForReplicaSet(), RawMetrics: []string{"kube_replicaset_created"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_replicaset_labels", "label"), RawMetrics: []string{"kube_replicaset_labels"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "ownerName", ValueFunc: prometheus.FromLabelValue("kube_replicaset_owner", "owner_name"), RawMetrics: []string{"kube_replicaset_owner"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "ownerKind", ValueFunc: prometheus.FromLabelValue("kube_replicaset_owner", "owner_kind"), RawMetrics: []string{"kube_replicaset_owner"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "ownerIsController", ValueFunc: prometheus.FromLabelValue("kube_replicaset_owner", "owner_is_controller"), RawMetrics: []string{"kube_replicaset_owner"}, Type: sdkMetric.ATTRIBUTE},
 			// computed
 			{
 				Name: "podsMissing", ValueFunc: Subtract(
 					definition.Transform(prometheus.FromValue("kube_replicaset_spec_replicas"), fromPrometheusNumeric),
 					definition.Transform(prometheus.FromValue("kube_replicaset_status_ready_replicas"), fromPrometheusNumeric)),
-				Type: sdkMetric.GAUGE,
+				RawMetrics: []string{"kube_replicaset_spec_replicas", "kube_replicaset_status_ready_replicas"},
+				Type:       sdkMetric.GAUGE,
 			},
 		},
 	},
@@ -705,26 +766,28 @@ var KSMSpecs = definition.SpecGroups{
 		IDGenerator:     prometheus.FromLabelValueEntityIDGenerator("kube_statefulset_created", "statefulset"),
 		TypeGenerator:   prometheus.FromLabelValueEntityTypeGenerator("kube_statefulset_created"),
 		NamespaceGetter: prometheus.FromLabelGetNamespace,
+		RawMetrics:      []string{"kube_statefulset_created"},
 		Specs: []definition.Spec{
-			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_statefulset_created"), Type: sdkMetric.GAUGE},
-			{Name: "podsDesired", ValueFunc: prometheus.FromValue("kube_statefulset_replicas"), Type: sdkMetric.GAUGE},
-			{Name: "podsReady", ValueFunc: prometheus.FromValue("kube_statefulset_status_replicas_ready"), Type: sdkMetric.GAUGE},
-			{Name: "podsCurrent", ValueFunc: prometheus.FromValue("kube_statefulset_status_replicas_current"), Type: sdkMetric.GAUGE},
-			{Name: "podsTotal", ValueFunc: prometheus.FromValue("kube_statefulset_status_replicas"), Type: sdkMetric.GAUGE},
-			{Name: "podsUpdated", ValueFunc: prometheus.FromValue("kube_statefulset_status_replicas_updated"), Type: sdkMetric.GAUGE},
-			{Name: "observedGeneration", ValueFunc: prometheus.FromValue("kube_statefulset_status_observed_generation"), Type: sdkMetric.GAUGE},
-			{Name: "metadataGeneration", ValueFunc: prometheus.FromValue("kube_statefulset_metadata_generation"), Type: sdkMetric.GAUGE},
-			{Name: "currentRevision", ValueFunc: prometheus.FromValue("kube_statefulset_status_current_revision"), Type: sdkMetric.GAUGE},
-			{Name: "updateRevision", ValueFunc: prometheus.FromValue("kube_statefulset_status_update_revision"), Type: sdkMetric.GAUGE},
-			{Name: "statefulsetName", ValueFunc: prometheus.FromLabelValue("kube_statefulset_created", "statefulset"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_statefulset_created", "namespace"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_statefulset_labels", "label"), Type: sdkMetric.ATTRIBUTE},
+			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_statefulset_created"), RawMetrics: []string{"kube_statefulset_created"}, Type: sdkMetric.GAUGE},
+			{Name: "podsDesired", ValueFunc: prometheus.FromValue("kube_statefulset_replicas"), RawMetrics: []string{"kube_statefulset_replicas"}, Type: sdkMetric.GAUGE},
+			{Name: "podsReady", ValueFunc: prometheus.FromValue("kube_statefulset_status_replicas_ready"), RawMetrics: []string{"kube_statefulset_status_replicas_ready"}, Type: sdkMetric.GAUGE},
+			{Name: "podsCurrent", ValueFunc: prometheus.FromValue("kube_statefulset_status_replicas_current"), RawMetrics: []string{"kube_statefulset_status_replicas_current"}, Type: sdkMetric.GAUGE},
+			{Name: "podsTotal", ValueFunc: prometheus.FromValue("kube_statefulset_status_replicas"), RawMetrics: []string{"kube_statefulset_status_replicas"}, Type: sdkMetric.GAUGE},
+			{Name: "podsUpdated", ValueFunc: prometheus.FromValue("kube_statefulset_status_replicas_updated"), RawMetrics: []string{"kube_statefulset_status_replicas_updated"}, Type: sdkMetric.GAUGE},
+			{Name: "observedGeneration", ValueFunc: prometheus.FromValue("kube_statefulset_status_observed_generation"), RawMetrics: []string{"kube_statefulset_status_observed_generation"}, Type: sdkMetric.GAUGE},
+			{Name: "metadataGeneration", ValueFunc: prometheus.FromValue("kube_statefulset_metadata_generation"), RawMetrics: []string{"kube_statefulset_metadata_generation"}, Type: sdkMetric.GAUGE},
+			{Name: "currentRevision", ValueFunc: prometheus.FromValue("kube_statefulset_status_current_revision"), RawMetrics: []string{"kube_statefulset_status_current_revision"}, Type: sdkMetric.GAUGE},
+			{Name: "updateRevision", ValueFunc: prometheus.FromValue("kube_statefulset_status_update_revision"), RawMetrics: []string{"kube_statefulset_status_update_revision"}, Type: sdkMetric.GAUGE},
+			{Name: "statefulsetName", ValueFunc: prometheus.FromLabelValue("kube_statefulset_created", "statefulset"), RawMetrics: []string{"kube_statefulset_created"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_statefulset_created", "namespace"), RawMetrics: []string{"kube_statefulset_created"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_statefulset_labels", "label"), RawMetrics: []string{"kube_statefulset_labels"}, Type: sdkMetric.ATTRIBUTE},
 			// computed
 			{
 				Name: "podsMissing", ValueFunc: Subtract(
 					definition.Transform(prometheus.FromValue("kube_statefulset_replicas"), fromPrometheusNumeric),
 					definition.Transform(prometheus.FromValue("kube_statefulset_status_replicas_ready"), fromPrometheusNumeric)),
-				Type: sdkMetric.GAUGE,
+				RawMetrics: []string{"kube_statefulset_replicas", "kube_statefulset_status_replicas_ready"},
+				Type:       sdkMetric.GAUGE,
 			},
 		},
 	},
@@ -732,74 +795,79 @@ var KSMSpecs = definition.SpecGroups{
 		IDGenerator:     prometheus.FromLabelValueEntityIDGenerator("kube_daemonset_created", "daemonset"),
 		TypeGenerator:   prometheus.FromLabelValueEntityTypeGenerator("kube_daemonset_created"),
 		NamespaceGetter: prometheus.FromLabelGetNamespace,
+		RawMetrics:      []string{"kube_daemonset_created"},
 		Specs: []definition.Spec{
-			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_daemonset_created"), Type: sdkMetric.GAUGE},
-			{Name: "podsDesired", ValueFunc: prometheus.FromValue("kube_daemonset_status_desired_number_scheduled"), Type: sdkMetric.GAUGE},
-			{Name: "podsScheduled", ValueFunc: prometheus.FromValue("kube_daemonset_status_current_number_scheduled"), Type: sdkMetric.GAUGE},
-			{Name: "podsAvailable", ValueFunc: prometheus.FromValue("kube_daemonset_status_number_available"), Type: sdkMetric.GAUGE},
-			{Name: "podsReady", ValueFunc: prometheus.FromValue("kube_daemonset_status_number_ready"), Type: sdkMetric.GAUGE},
-			{Name: "podsUnavailable", ValueFunc: prometheus.FromValue("kube_daemonset_status_number_unavailable"), Type: sdkMetric.GAUGE},
-			{Name: "podsMisscheduled", ValueFunc: prometheus.FromValue("kube_daemonset_status_number_misscheduled"), Type: sdkMetric.GAUGE},
-			{Name: "podsUpdatedScheduled", ValueFunc: prometheus.FromValue("kube_daemonset_status_updated_number_scheduled"), Type: sdkMetric.GAUGE},
-			{Name: "observedGeneration", ValueFunc: prometheus.FromValue("kube_daemonset_status_observed_generation"), Type: sdkMetric.GAUGE},
-			{Name: "metadataGeneration", ValueFunc: prometheus.FromValue("kube_daemonset_metadata_generation"), Type: sdkMetric.GAUGE},
-			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_daemonset_created", "namespace"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "daemonsetName", ValueFunc: prometheus.FromLabelValue("kube_daemonset_created", "daemonset"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_daemonset_labels", "label"), Type: sdkMetric.ATTRIBUTE},
+			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_daemonset_created"), RawMetrics: []string{"kube_daemonset_created"}, Type: sdkMetric.GAUGE},
+			{Name: "podsDesired", ValueFunc: prometheus.FromValue("kube_daemonset_status_desired_number_scheduled"), RawMetrics: []string{"kube_daemonset_status_desired_number_scheduled"}, Type: sdkMetric.GAUGE},
+			{Name: "podsScheduled", ValueFunc: prometheus.FromValue("kube_daemonset_status_current_number_scheduled"), RawMetrics: []string{"kube_daemonset_status_current_number_scheduled"}, Type: sdkMetric.GAUGE},
+			{Name: "podsAvailable", ValueFunc: prometheus.FromValue("kube_daemonset_status_number_available"), RawMetrics: []string{"kube_daemonset_status_number_available"}, Type: sdkMetric.GAUGE},
+			{Name: "podsReady", ValueFunc: prometheus.FromValue("kube_daemonset_status_number_ready"), RawMetrics: []string{"kube_daemonset_status_number_ready"}, Type: sdkMetric.GAUGE},
+			{Name: "podsUnavailable", ValueFunc: prometheus.FromValue("kube_daemonset_status_number_unavailable"), RawMetrics: []string{"kube_daemonset_status_number_unavailable"}, Type: sdkMetric.GAUGE},
+			{Name: "podsMisscheduled", ValueFunc: prometheus.FromValue("kube_daemonset_status_number_misscheduled"), RawMetrics: []string{"kube_daemonset_status_number_misscheduled"}, Type: sdkMetric.GAUGE},
+			{Name: "podsUpdatedScheduled", ValueFunc: prometheus.FromValue("kube_daemonset_status_updated_number_scheduled"), RawMetrics: []string{"kube_daemonset_status_updated_number_scheduled"}, Type: sdkMetric.GAUGE},
+			{Name: "observedGeneration", ValueFunc: prometheus.FromValue("kube_daemonset_status_observed_generation"), RawMetrics: []string{"kube_daemonset_status_observed_generation"}, Type: sdkMetric.GAUGE},
+			{Name: "metadataGeneration", ValueFunc: prometheus.FromValue("kube_daemonset_metadata_generation"), RawMetrics: []string{"kube_daemonset_metadata_generation"}, Type: sdkMetric.GAUGE},
+			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_daemonset_created", "namespace"), RawMetrics: []string{"kube_daemonset_created"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "daemonsetName", ValueFunc: prometheus.FromLabelValue("kube_daemonset_created", "daemonset"), RawMetrics: []string{"kube_daemonset_created"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_daemonset_labels", "label"), RawMetrics: []string{"kube_daemonset_labels"}, Type: sdkMetric.ATTRIBUTE},
 			// computed
 			{
 				Name: "podsMissing", ValueFunc: Subtract(
 					definition.Transform(prometheus.FromValue("kube_daemonset_status_desired_number_scheduled"), fromPrometheusNumeric),
 					definition.Transform(prometheus.FromValue("kube_daemonset_status_number_ready"), fromPrometheusNumeric)),
-				Type: sdkMetric.GAUGE,
+				RawMetrics: []string{"kube_daemonset_status_desired_number_scheduled", "kube_daemonset_status_number_ready"},
+				Type:       sdkMetric.GAUGE,
 			},
 		},
 	},
 	"namespace": {
 		TypeGenerator:   prometheus.FromLabelValueEntityTypeGenerator("kube_namespace_created"),
 		NamespaceGetter: prometheus.FromLabelGetNamespace,
+		RawMetrics:      []string{"kube_namespace_created"},
 		Specs: []definition.Spec{
-			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_namespace_created"), Type: sdkMetric.GAUGE},
-			{Name: "namespace", ValueFunc: prometheus.FromLabelValue("kube_namespace_created", "namespace"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_namespace_created", "namespace"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "status", ValueFunc: prometheus.FromLabelValue("kube_namespace_status_phase", "phase"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_namespace_labels", "label"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "annotation.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_namespace_annotations", "annotation"), Type: sdkMetric.ATTRIBUTE},
+			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_namespace_created"), RawMetrics: []string{"kube_namespace_created"}, Type: sdkMetric.GAUGE},
+			{Name: "namespace", ValueFunc: prometheus.FromLabelValue("kube_namespace_created", "namespace"), RawMetrics: []string{"kube_namespace_created"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_namespace_created", "namespace"), RawMetrics: []string{"kube_namespace_created"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "status", ValueFunc: prometheus.FromLabelValue("kube_namespace_status_phase", "phase"), RawMetrics: []string{"kube_namespace_status_phase"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_namespace_labels", "label"), RawMetrics: []string{"kube_namespace_labels"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "annotation.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_namespace_annotations", "annotation"), RawMetrics: []string{"kube_namespace_annotations"}, Type: sdkMetric.ATTRIBUTE},
 		},
 	},
 	"deployment": {
 		IDGenerator:     prometheus.FromLabelValueEntityIDGenerator("kube_deployment_created", "deployment"),
 		TypeGenerator:   prometheus.FromLabelValueEntityTypeGenerator("kube_deployment_created"),
 		NamespaceGetter: prometheus.FromLabelGetNamespace,
+		RawMetrics:      []string{"kube_deployment_created"},
 		Specs: []definition.Spec{
-			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_deployment_created"), Type: sdkMetric.GAUGE},
-			{Name: "podsDesired", ValueFunc: prometheus.FromValue("kube_deployment_spec_replicas"), Type: sdkMetric.GAUGE},
-			{Name: "podsTotal", ValueFunc: prometheus.FromValue("kube_deployment_status_replicas"), Type: sdkMetric.GAUGE},
-			{Name: "podsReady", ValueFunc: prometheus.FromValue("kube_deployment_status_replicas_ready"), Type: sdkMetric.GAUGE},
-			{Name: "podsAvailable", ValueFunc: prometheus.FromValue("kube_deployment_status_replicas_available"), Type: sdkMetric.GAUGE},
-			{Name: "podsUnavailable", ValueFunc: prometheus.FromValue("kube_deployment_status_replicas_unavailable"), Type: sdkMetric.GAUGE},
-			{Name: "podsUpdated", ValueFunc: prometheus.FromValue("kube_deployment_status_replicas_updated"), Type: sdkMetric.GAUGE},
-			{Name: "observedGeneration", ValueFunc: prometheus.FromValue("kube_deployment_status_observed_generation"), Type: sdkMetric.GAUGE},
-			{Name: "isPaused", ValueFunc: prometheus.FromValue("kube_deployment_spec_paused"), Type: sdkMetric.GAUGE},
-			{Name: "rollingUpdateMaxPodsSurge", ValueFunc: prometheus.FromValue("kube_deployment_spec_strategy_rollingupdate_max_surge"), Type: sdkMetric.GAUGE},
-			{Name: "metadataGeneration", ValueFunc: prometheus.FromValue("kube_deployment_metadata_generation"), Type: sdkMetric.GAUGE},
-			{Name: "conditionAvailable", ValueFunc: prometheus.FromLabelValue("kube_deployment_status_condition_available", "status"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "conditionProgressing", ValueFunc: prometheus.FromLabelValue("kube_deployment_status_condition_progressing", "status"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "conditionReplicaFailure", ValueFunc: prometheus.FromLabelValue("kube_deployment_status_condition_replica_failure", "status"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "podsMaxUnavailable", ValueFunc: prometheus.FromValue("kube_deployment_spec_strategy_rollingupdate_max_unavailable"), Type: sdkMetric.GAUGE},
-			{Name: "namespace", ValueFunc: prometheus.FromLabelValue("kube_deployment_created", "namespace"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_deployment_created", "namespace"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "deploymentName", ValueFunc: prometheus.FromLabelValue("kube_deployment_created", "deployment"), Type: sdkMetric.ATTRIBUTE},
+			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_deployment_created"), RawMetrics: []string{"kube_deployment_created"}, Type: sdkMetric.GAUGE},
+			{Name: "podsDesired", ValueFunc: prometheus.FromValue("kube_deployment_spec_replicas"), RawMetrics: []string{"kube_deployment_spec_replicas"}, Type: sdkMetric.GAUGE},
+			{Name: "podsTotal", ValueFunc: prometheus.FromValue("kube_deployment_status_replicas"), RawMetrics: []string{"kube_deployment_status_replicas"}, Type: sdkMetric.GAUGE},
+			{Name: "podsReady", ValueFunc: prometheus.FromValue("kube_deployment_status_replicas_ready"), RawMetrics: []string{"kube_deployment_status_replicas_ready"}, Type: sdkMetric.GAUGE},
+			{Name: "podsAvailable", ValueFunc: prometheus.FromValue("kube_deployment_status_replicas_available"), RawMetrics: []string{"kube_deployment_status_replicas_available"}, Type: sdkMetric.GAUGE},
+			{Name: "podsUnavailable", ValueFunc: prometheus.FromValue("kube_deployment_status_replicas_unavailable"), RawMetrics: []string{"kube_deployment_status_replicas_unavailable"}, Type: sdkMetric.GAUGE},
+			{Name: "podsUpdated", ValueFunc: prometheus.FromValue("kube_deployment_status_replicas_updated"), RawMetrics: []string{"kube_deployment_status_replicas_updated"}, Type: sdkMetric.GAUGE},
+			{Name: "observedGeneration", ValueFunc: prometheus.FromValue("kube_deployment_status_observed_generation"), RawMetrics: []string{"kube_deployment_status_observed_generation"}, Type: sdkMetric.GAUGE},
+			{Name: "isPaused", ValueFunc: prometheus.FromValue("kube_deployment_spec_paused"), RawMetrics: []string{"kube_deployment_spec_paused"}, Type: sdkMetric.GAUGE},
+			{Name: "rollingUpdateMaxPodsSurge", ValueFunc: prometheus.FromValue("kube_deployment_spec_strategy_rollingupdate_max_surge"), RawMetrics: []string{"kube_deployment_spec_strategy_rollingupdate_max_surge"}, Type: sdkMetric.GAUGE},
+			{Name: "metadataGeneration", ValueFunc: prometheus.FromValue("kube_deployment_metadata_generation"), RawMetrics: []string{"kube_deployment_metadata_generation"}, Type: sdkMetric.GAUGE},
+			{Name: "conditionAvailable", ValueFunc: prometheus.FromLabelValue("kube_deployment_status_condition_available", "status"), RawMetrics: []string{"kube_deployment_status_condition_available"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "conditionProgressing", ValueFunc: prometheus.FromLabelValue("kube_deployment_status_condition_progressing", "status"), RawMetrics: []string{"kube_deployment_status_condition_progressing"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "conditionReplicaFailure", ValueFunc: prometheus.FromLabelValue("kube_deployment_status_condition_replica_failure", "status"), RawMetrics: []string{"kube_deployment_status_condition_replica_failure"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "podsMaxUnavailable", ValueFunc: prometheus.FromValue("kube_deployment_spec_strategy_rollingupdate_max_unavailable"), RawMetrics: []string{"kube_deployment_spec_strategy_rollingupdate_max_unavailable"}, Type: sdkMetric.GAUGE},
+			{Name: "namespace", ValueFunc: prometheus.FromLabelValue("kube_deployment_created", "namespace"), RawMetrics: []string{"kube_deployment_created"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_deployment_created", "namespace"), RawMetrics: []string{"kube_deployment_created"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "deploymentName", ValueFunc: prometheus.FromLabelValue("kube_deployment_created", "deployment"), RawMetrics: []string{"kube_deployment_created"}, Type: sdkMetric.ATTRIBUTE},
 			// Important: The order of these lines is important: we could have the same label in different entities, and we would like to keep the value closer to deployment
-			{Name: "label.*", ValueFunc: prometheus.InheritAllLabelsFrom("namespace", "kube_namespace_labels"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_deployment_labels", "label"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "annotation.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_deployment_annotations", "annotation"), Type: sdkMetric.ATTRIBUTE},
+			{Name: "label.*", ValueFunc: prometheus.InheritAllLabelsFrom("namespace", "kube_namespace_labels"), RawMetrics: []string{"kube_namespace_labels"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_deployment_labels", "label"), RawMetrics: []string{"kube_deployment_labels"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "annotation.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_deployment_annotations", "annotation"), RawMetrics: []string{"kube_deployment_annotations"}, Type: sdkMetric.ATTRIBUTE},
 			// computed
 			{
 				Name: "podsMissing", ValueFunc: Subtract(
 					definition.Transform(prometheus.FromValue("kube_deployment_spec_replicas"), fromPrometheusNumeric),
 					definition.Transform(prometheus.FromValue("kube_deployment_status_replicas"), fromPrometheusNumeric)),
-				Type: sdkMetric.GAUGE,
+				RawMetrics: []string{"kube_deployment_spec_replicas", "kube_deployment_status_replicas"},
+				Type:       sdkMetric.GAUGE,
 			},
 		},
 	},
@@ -807,49 +875,58 @@ var KSMSpecs = definition.SpecGroups{
 		IDGenerator:     prometheus.FromLabelValueEntityIDGenerator("kube_service_created", "service"),
 		TypeGenerator:   prometheus.FromLabelValueEntityTypeGenerator("kube_service_created"),
 		NamespaceGetter: prometheus.FromLabelGetNamespace,
+		RawMetrics:      []string{"kube_service_created"},
 		Specs: []definition.Spec{
 			{
-				Name:      "createdAt",
-				ValueFunc: prometheus.FromValue("kube_service_created"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "createdAt",
+				ValueFunc:  prometheus.FromValue("kube_service_created"),
+				RawMetrics: []string{"kube_service_created"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "namespaceName",
-				ValueFunc: prometheus.FromLabelValue("kube_service_created", "namespace"),
-				Type:      sdkMetric.ATTRIBUTE,
+				Name:       "namespaceName",
+				ValueFunc:  prometheus.FromLabelValue("kube_service_created", "namespace"),
+				RawMetrics: []string{"kube_service_created"},
+				Type:       sdkMetric.ATTRIBUTE,
 			},
 			{
-				Name:      "serviceName",
-				ValueFunc: prometheus.FromLabelValue("kube_service_created", "service"),
-				Type:      sdkMetric.ATTRIBUTE,
+				Name:       "serviceName",
+				ValueFunc:  prometheus.FromLabelValue("kube_service_created", "service"),
+				RawMetrics: []string{"kube_service_created"},
+				Type:       sdkMetric.ATTRIBUTE,
 			},
 			{
-				Name:      "loadBalancerIP",
-				ValueFunc: prometheus.FromLabelValue("kube_service_info", "load_balancer_ip"),
-				Type:      sdkMetric.ATTRIBUTE,
-				Optional:  true,
+				Name:       "loadBalancerIP",
+				ValueFunc:  prometheus.FromLabelValue("kube_service_info", "load_balancer_ip"),
+				RawMetrics: []string{"kube_service_info"},
+				Type:       sdkMetric.ATTRIBUTE,
+				Optional:   true,
 			},
 			{
-				Name:      "externalName",
-				ValueFunc: prometheus.FromLabelValue("kube_service_info", "external_name"),
-				Type:      sdkMetric.ATTRIBUTE,
-				Optional:  true,
+				Name:       "externalName",
+				ValueFunc:  prometheus.FromLabelValue("kube_service_info", "external_name"),
+				RawMetrics: []string{"kube_service_info"},
+				Type:       sdkMetric.ATTRIBUTE,
+				Optional:   true,
 			},
 			{
-				Name:      "clusterIP",
-				ValueFunc: prometheus.FromLabelValue("kube_service_info", "cluster_ip"),
-				Type:      sdkMetric.ATTRIBUTE,
-				Optional:  true,
+				Name:       "clusterIP",
+				ValueFunc:  prometheus.FromLabelValue("kube_service_info", "cluster_ip"),
+				RawMetrics: []string{"kube_service_info"},
+				Type:       sdkMetric.ATTRIBUTE,
+				Optional:   true,
 			},
 			{
-				Name:      "label.*",
-				ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_service_labels", "label"),
-				Type:      sdkMetric.ATTRIBUTE,
+				Name:       "label.*",
+				ValueFunc:  prometheus.FromMetricWithPrefixedLabels("kube_service_labels", "label"),
+				RawMetrics: []string{"kube_service_labels"},
+				Type:       sdkMetric.ATTRIBUTE,
 			},
 			{
-				Name:      "specType",
-				ValueFunc: prometheus.FromLabelValue("kube_service_spec_type", "type"),
-				Type:      sdkMetric.ATTRIBUTE,
+				Name:       "specType",
+				ValueFunc:  prometheus.FromLabelValue("kube_service_spec_type", "type"),
+				RawMetrics: []string{"kube_service_spec_type"},
+				Type:       sdkMetric.ATTRIBUTE,
 			},
 			{
 				Name: "selector.*",
@@ -863,39 +940,46 @@ var KSMSpecs = definition.SpecGroups{
 		IDGenerator:     prometheus.FromLabelValueEntityIDGenerator("kube_endpoint_created", "endpoint"),
 		TypeGenerator:   prometheus.FromLabelValueEntityTypeGenerator("kube_endpoint_created"),
 		NamespaceGetter: prometheus.FromLabelGetNamespace,
+		RawMetrics:      []string{"kube_endpoint_created"},
 		Specs: []definition.Spec{
 			{
-				Name:      "createdAt",
-				ValueFunc: prometheus.FromValue("kube_endpoint_created"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "createdAt",
+				ValueFunc:  prometheus.FromValue("kube_endpoint_created"),
+				RawMetrics: []string{"kube_endpoint_created"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
-				Name:      "namespaceName",
-				ValueFunc: prometheus.FromLabelValue("kube_endpoint_created", "namespace"),
-				Type:      sdkMetric.ATTRIBUTE,
+				Name:       "namespaceName",
+				ValueFunc:  prometheus.FromLabelValue("kube_endpoint_created", "namespace"),
+				RawMetrics: []string{"kube_endpoint_created"},
+				Type:       sdkMetric.ATTRIBUTE,
 			},
 			{
-				Name:      "endpointName",
-				ValueFunc: prometheus.FromLabelValue("kube_endpoint_created", "endpoint"),
-				Type:      sdkMetric.ATTRIBUTE,
+				Name:       "endpointName",
+				ValueFunc:  prometheus.FromLabelValue("kube_endpoint_created", "endpoint"),
+				RawMetrics: []string{"kube_endpoint_created"},
+				Type:       sdkMetric.ATTRIBUTE,
 			},
 			{
-				Name:      "label.*",
-				ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_endpoint_labels", "label"),
-				Type:      sdkMetric.ATTRIBUTE,
+				Name:       "label.*",
+				ValueFunc:  prometheus.FromMetricWithPrefixedLabels("kube_endpoint_labels", "label"),
+				RawMetrics: []string{"kube_endpoint_labels"},
+				Type:       sdkMetric.ATTRIBUTE,
 			},
 			// KSM < 2.14 - Legacy metrics (pre-aggregated by KSM)
 			{
-				Name:      "addressAvailable",
-				ValueFunc: prometheus.FromValue("kube_endpoint_address_available"),
-				Type:      sdkMetric.GAUGE,
-				Optional:  true, // Optional: does not exist in KSM >= 2.14
+				Name:       "addressAvailable",
+				ValueFunc:  prometheus.FromValue("kube_endpoint_address_available"),
+				RawMetrics: []string{"kube_endpoint_address_available"},
+				Type:       sdkMetric.GAUGE,
+				Optional:   true, // Optional: does not exist in KSM >= 2.14
 			},
 			{
-				Name:      "addressNotReady",
-				ValueFunc: prometheus.FromValue("kube_endpoint_address_not_ready"),
-				Type:      sdkMetric.GAUGE,
-				Optional:  true, // Optional: does not exist in KSM >= 2.14
+				Name:       "addressNotReady",
+				ValueFunc:  prometheus.FromValue("kube_endpoint_address_not_ready"),
+				RawMetrics: []string{"kube_endpoint_address_not_ready"},
+				Type:       sdkMetric.GAUGE,
+				Optional:   true, // Optional: does not exist in KSM >= 2.14
 			},
 			// KSM >= v2.14 - Detailed metrics (we aggregate by filtering on ready label)
 			{
@@ -907,8 +991,9 @@ var KSMSpecs = definition.SpecGroups{
 						"ready": "true",
 					}),
 				),
-				Type:     sdkMetric.GAUGE,
-				Optional: true, // Optional: may not exist in KSM < 2.14
+				RawMetrics: []string{"kube_endpoint_address"},
+				Type:       sdkMetric.GAUGE,
+				Optional:   true, // Optional: may not exist in KSM < 2.14
 			},
 			{
 				Name: "addressNotReady",
@@ -919,8 +1004,9 @@ var KSMSpecs = definition.SpecGroups{
 						"ready": "false",
 					}),
 				),
-				Type:     sdkMetric.GAUGE,
-				Optional: true, // Optional: may not exist in KSM < 2.14
+				RawMetrics: []string{"kube_endpoint_address"},
+				Type:       sdkMetric.GAUGE,
+				Optional:   true, // Optional: may not exist in KSM < 2.14
 			},
 		},
 	},
@@ -932,22 +1018,24 @@ var KSMSpecs = definition.SpecGroups{
 		IDGenerator:     prometheus.FromLabelsValueEntityIDGeneratorForPendingPods(),
 		TypeGenerator:   prometheus.FromLabelValueEntityTypeGenerator("kube_pod_status_phase"),
 		NamespaceGetter: prometheus.FromLabelGetNamespace,
+		PodGetter:       prometheus.FromLabelGetPod,
+		RawMetrics:      []string{"kube_pod_status_phase", "kube_pod_status_scheduled"},
 		Specs: []definition.Spec{
-			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_pod_created"), Type: sdkMetric.GAUGE},
-			{Name: "createdKind", ValueFunc: prometheus.FromLabelValue("kube_pod_info", "created_by_kind"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "createdBy", ValueFunc: prometheus.FromLabelValue("kube_pod_info", "created_by_name"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "nodeIP", ValueFunc: prometheus.FromLabelValue("kube_pod_info", "host_ip"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "namespace", ValueFunc: prometheus.FromLabelValue("kube_pod_info", "namespace"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_pod_info", "namespace"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "nodeName", ValueFunc: prometheus.FromLabelValue("kube_pod_info", "node"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "podName", ValueFunc: prometheus.FromLabelValue("kube_pod_info", "pod"), Type: sdkMetric.ATTRIBUTE},
+			{Name: "createdAt", ValueFunc: prometheus.FromValue("kube_pod_created"), RawMetrics: []string{"kube_pod_created"}, Type: sdkMetric.GAUGE},
+			{Name: "createdKind", ValueFunc: prometheus.FromLabelValue("kube_pod_info", "created_by_kind"), RawMetrics: []string{"kube_pod_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "createdBy", ValueFunc: prometheus.FromLabelValue("kube_pod_info", "created_by_name"), RawMetrics: []string{"kube_pod_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "nodeIP", ValueFunc: prometheus.FromLabelValue("kube_pod_info", "host_ip"), RawMetrics: []string{"kube_pod_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "namespace", ValueFunc: prometheus.FromLabelValue("kube_pod_info", "namespace"), RawMetrics: []string{"kube_pod_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_pod_info", "namespace"), RawMetrics: []string{"kube_pod_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "nodeName", ValueFunc: prometheus.FromLabelValue("kube_pod_info", "node"), RawMetrics: []string{"kube_pod_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "podName", ValueFunc: prometheus.FromLabelValue("kube_pod_info", "pod"), RawMetrics: []string{"kube_pod_info"}, Type: sdkMetric.ATTRIBUTE},
 			// we are adding as default `false` since all ksm pods used refers to pending pods due to the IDGenerator.
-			{Name: "isReady", ValueFunc: definition.Transform(fetchWithDefault(prometheus.FromLabelValue("kube_pod_status_ready", "condition"), "false"), toNumericBoolean), Type: sdkMetric.GAUGE},
-			{Name: "status", ValueFunc: prometheus.FromLabelValue("kube_pod_status_phase", "phase"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "isScheduled", ValueFunc: definition.Transform(prometheus.FromLabelValue("kube_pod_status_scheduled", "condition"), toNumericBoolean), Type: sdkMetric.GAUGE},
-			{Name: "deploymentName", ValueFunc: ksmMetric.GetDeploymentNameForPod(), Type: sdkMetric.ATTRIBUTE},
-			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_pod_labels", "label"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "annotation.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_pod_annotations", "annotation"), Type: sdkMetric.ATTRIBUTE},
+			{Name: "isReady", ValueFunc: definition.Transform(fetchWithDefault(prometheus.FromLabelValue("kube_pod_status_ready", "condition"), "false"), toNumericBoolean), RawMetrics: []string{"kube_pod_status_ready"}, Type: sdkMetric.GAUGE},
+			{Name: "status", ValueFunc: prometheus.FromLabelValue("kube_pod_status_phase", "phase"), RawMetrics: []string{"kube_pod_status_phase"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "isScheduled", ValueFunc: definition.Transform(prometheus.FromLabelValue("kube_pod_status_scheduled", "condition"), toNumericBoolean), RawMetrics: []string{"kube_pod_status_scheduled"}, Type: sdkMetric.GAUGE},
+			{Name: "deploymentName", ValueFunc: ksmMetric.GetDeploymentNameForPod(), RawMetrics: []string{"kube_pod_info"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_pod_labels", "label"), RawMetrics: []string{"kube_pod_labels"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "annotation.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_pod_annotations", "annotation"), RawMetrics: []string{"kube_pod_annotations"}, Type: sdkMetric.ATTRIBUTE},
 		},
 	},
 	"horizontalpodautoscaler": {
@@ -956,21 +1044,22 @@ var KSMSpecs = definition.SpecGroups{
 		TypeGenerator:   prometheus.FromLabelValueEntityTypeGeneratorWithCustomGroup("kube_horizontalpodautoscaler_status_current_replicas", "hpa"),
 		NamespaceGetter: prometheus.FromLabelGetNamespace,
 		MsTypeGuesser:   metricSetTypeGuesserWithCustomGroup("hpa"), // group customized for backwards compatibility reasons
+		RawMetrics:      []string{"kube_horizontalpodautoscaler_status_current_replicas"},
 		Specs: []definition.Spec{
 			// The generation observed by the HorizontalPodAutoscaler controller. not sure if interesting to get
-			{Name: "metadataGeneration", ValueFunc: prometheus.FromValue("kube_horizontalpodautoscaler_metadata_generation"), Type: sdkMetric.GAUGE},
-			{Name: "maxReplicas", ValueFunc: prometheus.FromValue("kube_horizontalpodautoscaler_spec_max_replicas"), Type: sdkMetric.GAUGE},
-			{Name: "minReplicas", ValueFunc: prometheus.FromValue("kube_horizontalpodautoscaler_spec_min_replicas"), Type: sdkMetric.GAUGE},
+			{Name: "metadataGeneration", ValueFunc: prometheus.FromValue("kube_horizontalpodautoscaler_metadata_generation"), RawMetrics: []string{"kube_horizontalpodautoscaler_metadata_generation"}, Type: sdkMetric.GAUGE},
+			{Name: "maxReplicas", ValueFunc: prometheus.FromValue("kube_horizontalpodautoscaler_spec_max_replicas"), RawMetrics: []string{"kube_horizontalpodautoscaler_spec_max_replicas"}, Type: sdkMetric.GAUGE},
+			{Name: "minReplicas", ValueFunc: prometheus.FromValue("kube_horizontalpodautoscaler_spec_min_replicas"), RawMetrics: []string{"kube_horizontalpodautoscaler_spec_min_replicas"}, Type: sdkMetric.GAUGE},
 			// TODO this metric has a couple of dimensions (metric_name, target_type) that might be useful to add
-			{Name: "targetMetric", ValueFunc: prometheus.FromValue("kube_horizontalpodautoscaler_spec_target_metric"), Type: sdkMetric.GAUGE},
-			{Name: "currentReplicas", ValueFunc: prometheus.FromValue("kube_horizontalpodautoscaler_status_current_replicas"), Type: sdkMetric.GAUGE},
-			{Name: "desiredReplicas", ValueFunc: prometheus.FromValue("kube_horizontalpodautoscaler_status_desired_replicas"), Type: sdkMetric.GAUGE},
-			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_horizontalpodautoscaler_metadata_generation", "namespace"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_horizontalpodautoscaler_labels", "label"), Type: sdkMetric.ATTRIBUTE},
+			{Name: "targetMetric", ValueFunc: prometheus.FromValue("kube_horizontalpodautoscaler_spec_target_metric"), RawMetrics: []string{"kube_horizontalpodautoscaler_spec_target_metric"}, Type: sdkMetric.GAUGE},
+			{Name: "currentReplicas", ValueFunc: prometheus.FromValue("kube_horizontalpodautoscaler_status_current_replicas"), RawMetrics: []string{"kube_horizontalpodautoscaler_status_current_replicas"}, Type: sdkMetric.GAUGE},
+			{Name: "desiredReplicas", ValueFunc: prometheus.FromValue("kube_horizontalpodautoscaler_status_desired_replicas"), RawMetrics: []string{"kube_horizontalpodautoscaler_status_desired_replicas"}, Type: sdkMetric.GAUGE},
+			{Name: "namespaceName", ValueFunc: prometheus.FromLabelValue("kube_horizontalpodautoscaler_metadata_generation", "namespace"), RawMetrics: []string{"kube_horizontalpodautoscaler_metadata_generation"}, Type: sdkMetric.ATTRIBUTE},
+			{Name: "label.*", ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_horizontalpodautoscaler_labels", "label"), RawMetrics: []string{"kube_horizontalpodautoscaler_labels"}, Type: sdkMetric.ATTRIBUTE},
 			// TODO: is* metrics will be either true or `NULL`, but never false if the condition is not reported. This is not ideal.
-			{Name: "isActive", ValueFunc: prometheus.FromValue("kube_horizontalpodautoscaler_status_condition_active")},
-			{Name: "isAble", ValueFunc: prometheus.FromValue("kube_horizontalpodautoscaler_status_condition_able")},
-			{Name: "isLimited", ValueFunc: prometheus.FromValue("kube_horizontalpodautoscaler_status_condition_limited")},
+			{Name: "isActive", ValueFunc: prometheus.FromValue("kube_horizontalpodautoscaler_status_condition_active"), RawMetrics: []string{"kube_horizontalpodautoscaler_status_condition_active"}},
+			{Name: "isAble", ValueFunc: prometheus.FromValue("kube_horizontalpodautoscaler_status_condition_able"), RawMetrics: []string{"kube_horizontalpodautoscaler_status_condition_able"}},
+			{Name: "isLimited", ValueFunc: prometheus.FromValue("kube_horizontalpodautoscaler_status_condition_limited"), RawMetrics: []string{"kube_horizontalpodautoscaler_status_condition_limited"}},
 		},
 	},
 	"resourcequota": {
@@ -978,11 +1067,13 @@ var KSMSpecs = definition.SpecGroups{
 		NamespaceGetter: prometheus.FromLabelGetNamespace,
 		SplitByLabel:    "resource",
 		SliceMetricName: "kube_resourcequota",
+		RawMetrics:      []string{"kube_resourcequota_created"},
 		Specs: []definition.Spec{
 			{
-				Name:      "createdAt",
-				ValueFunc: prometheus.FromValue("kube_resourcequota_created"),
-				Type:      sdkMetric.GAUGE,
+				Name:       "createdAt",
+				ValueFunc:  prometheus.FromValue("kube_resourcequota_created"),
+				RawMetrics: []string{"kube_resourcequota_created"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
 				Name: "namespaceName",
@@ -990,7 +1081,8 @@ var KSMSpecs = definition.SpecGroups{
 					"kube_resourcequota_created",
 					"namespace",
 				),
-				Type: sdkMetric.ATTRIBUTE,
+				RawMetrics: []string{"kube_resourcequota_created"},
+				Type:       sdkMetric.ATTRIBUTE,
 			},
 			{
 				Name: "resourcequotaName", // This will be the name of your new column.
@@ -998,7 +1090,8 @@ var KSMSpecs = definition.SpecGroups{
 					"kube_resourcequota_created", // The stable source metric.
 					"resourcequota",              // The label to extract the value from.
 				),
-				Type: sdkMetric.ATTRIBUTE,
+				RawMetrics: []string{"kube_resourcequota_created"},
+				Type:       sdkMetric.ATTRIBUTE,
 			},
 			{
 				Name: "resource", // This will be the name of your new column.
@@ -1006,7 +1099,8 @@ var KSMSpecs = definition.SpecGroups{
 					"kube_resourcequota", // The stable source metric.
 					"resource",           // The label to extract the value from.
 				),
-				Type: sdkMetric.ATTRIBUTE,
+				RawMetrics: []string{"kube_resourcequota"},
+				Type:       sdkMetric.ATTRIBUTE,
 			},
 			{
 				Name: "resource.*",
@@ -1016,21 +1110,24 @@ var KSMSpecs = definition.SpecGroups{
 					"kube_resourcequota",
 					"type",
 				),
-				Type: sdkMetric.GAUGE,
+				RawMetrics: []string{"kube_resourcequota"},
+				Type:       sdkMetric.GAUGE,
 			},
 			{
 				Name: "label.*",
 				// This uses a generic function to fetch all Kubernetes labels.
-				ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_resourcequota_labels", "label"),
-				Type:      sdkMetric.ATTRIBUTE,
-				Optional:  true,
+				ValueFunc:  prometheus.FromMetricWithPrefixedLabels("kube_resourcequota_labels", "label"),
+				RawMetrics: []string{"kube_resourcequota_labels"},
+				Type:       sdkMetric.ATTRIBUTE,
+				Optional:   true,
 			},
 			{
 				Name: "annotation.*",
 				// This uses a generic function to fetch all Kubernetes annotations.
-				ValueFunc: prometheus.FromMetricWithPrefixedLabels("kube_resourcequota_annotations", "annotation"),
-				Type:      sdkMetric.ATTRIBUTE,
-				Optional:  true,
+				ValueFunc:  prometheus.FromMetricWithPrefixedLabels("kube_resourcequota_annotations", "annotation"),
+				RawMetrics: []string{"kube_resourcequota_annotations"},
+				Type:       sdkMetric.ATTRIBUTE,
+				Optional:   true,
 			},
 		},
 	},
@@ -1326,6 +1423,7 @@ var KubeletSpecs = definition.SpecGroups{
 		IDGenerator:     kubeletMetric.FromRawEntityIDGroupEntityIDGenerator("namespace"),
 		TypeGenerator:   kubeletMetric.FromRawGroupsEntityTypeGenerator,
 		NamespaceGetter: kubeletMetric.FromLabelGetNamespace,
+		PodGetter:       kubeletMetric.FromRawGetPod,
 		Specs: []definition.Spec{
 			// /stats/summary endpoint
 			{Name: "net.rxBytesPerSecond", ValueFunc: kubeletMetric.FromRawWithFallbackToDefaultInterface("rxBytes"), Type: sdkMetric.RATE},
@@ -1364,6 +1462,7 @@ var KubeletSpecs = definition.SpecGroups{
 		IDGenerator:     kubeletMetric.FromRawGroupsEntityIDGenerator("containerName"),
 		TypeGenerator:   kubeletMetric.FromRawGroupsEntityTypeGenerator,
 		NamespaceGetter: kubeletMetric.FromLabelGetNamespace,
+		PodGetter:       kubeletMetric.FromRawGetPod,
 		Specs: []definition.Spec{
 			// /stats/summary endpoint
 			{Name: "memoryUsedBytes", ValueFunc: definition.FromRaw("usageBytes"), Type: sdkMetric.GAUGE},
@@ -1380,15 +1479,15 @@ var KubeletSpecs = definition.SpecGroups{
 			// /metrics/cadvisor endpoint
 			{Name: "containerID", ValueFunc: definition.FromRaw("containerID"), Type: sdkMetric.ATTRIBUTE},
 			{Name: "containerImageID", ValueFunc: definition.FromRaw("containerImageID"), Type: sdkMetric.ATTRIBUTE},
-			{Name: "containerMemoryMappedFileBytes", ValueFunc: definition.FromRaw("container_memory_mapped_file"), Type: sdkMetric.GAUGE, Optional: true},
-			{Name: "containerOOMEventsDelta", ValueFunc: definition.FromRaw("container_oom_events_total"), Type: sdkMetric.PDELTA, Optional: true},
+			{Name: "containerMemoryMappedFileBytes", ValueFunc: definition.FromRaw("container_memory_mapped_file"), RawMetrics: []string{"container_memory_mapped_file"}, Type: sdkMetric.GAUGE, Optional: true},
+			{Name: "containerOOMEventsDelta", ValueFunc: definition.FromRaw("container_oom_events_total"), RawMetrics: []string{"container_oom_events_total"}, Type: sdkMetric.PDELTA, Optional: true},
 			// In openshift (and possibly in other environments) these metrics were missing at first for pods that were not throttled.
-			{Name: "containerCpuCfsPeriodsDelta", ValueFunc: definition.FromRaw("container_cpu_cfs_periods_total"), Type: sdkMetric.DELTA, Optional: true},
-			{Name: "containerCpuCfsThrottledPeriodsDelta", ValueFunc: definition.FromRaw("container_cpu_cfs_throttled_periods_total"), Type: sdkMetric.DELTA, Optional: true},
-			{Name: "containerCpuCfsThrottledSecondsDelta", ValueFunc: definition.FromRaw("container_cpu_cfs_throttled_seconds_total"), Type: sdkMetric.DELTA, Optional: true},
-			{Name: "containerCpuCfsPeriodsTotal", ValueFunc: definition.FromRaw("container_cpu_cfs_periods_total"), Type: sdkMetric.GAUGE, Optional: true},
-			{Name: "containerCpuCfsThrottledPeriodsTotal", ValueFunc: definition.FromRaw("container_cpu_cfs_throttled_periods_total"), Type: sdkMetric.GAUGE, Optional: true},
-			{Name: "containerCpuCfsThrottledSecondsTotal", ValueFunc: definition.FromRaw("container_cpu_cfs_throttled_seconds_total"), Type: sdkMetric.GAUGE, Optional: true},
+			{Name: "containerCpuCfsPeriodsDelta", ValueFunc: definition.FromRaw("container_cpu_cfs_periods_total"), RawMetrics: []string{"container_cpu_cfs_periods_total"}, Type: sdkMetric.DELTA, Optional: true},
+			{Name: "containerCpuCfsThrottledPeriodsDelta", ValueFunc: definition.FromRaw("container_cpu_cfs_throttled_periods_total"), RawMetrics: []string{"container_cpu_cfs_throttled_periods_total"}, Type: sdkMetric.DELTA, Optional: true},
+			{Name: "containerCpuCfsThrottledSecondsDelta", ValueFunc: definition.FromRaw("container_cpu_cfs_throttled_seconds_total"), RawMetrics: []string{"container_cpu_cfs_throttled_seconds_total"}, Type: sdkMetric.DELTA, Optional: true},
+			{Name: "containerCpuCfsPeriodsTotal", ValueFunc: definition.FromRaw("container_cpu_cfs_periods_total"), RawMetrics: []string{"container_cpu_cfs_periods_total"}, Type: sdkMetric.GAUGE, Optional: true},
+			{Name: "containerCpuCfsThrottledPeriodsTotal", ValueFunc: definition.FromRaw("container_cpu_cfs_throttled_periods_total"), RawMetrics: []string{"container_cpu_cfs_throttled_periods_total"}, Type: sdkMetric.GAUGE, Optional: true},
+			{Name: "containerCpuCfsThrottledSecondsTotal", ValueFunc: definition.FromRaw("container_cpu_cfs_throttled_seconds_total"), RawMetrics: []string{"container_cpu_cfs_throttled_seconds_total"}, Type: sdkMetric.GAUGE, Optional: true},
 
 			// /pods endpoint
 			{Name: "containerName", ValueFunc: definition.FromRaw("containerName"), Type: sdkMetric.ATTRIBUTE},
@@ -1471,6 +1570,7 @@ var KubeletSpecs = definition.SpecGroups{
 	"volume": {
 		TypeGenerator:   kubeletMetric.FromRawGroupsEntityTypeGenerator,
 		NamespaceGetter: kubeletMetric.FromLabelGetNamespace,
+		PodGetter:       kubeletMetric.FromRawGetPod,
 		Specs: []definition.Spec{
 			{Name: "volumeName", ValueFunc: definition.FromRaw("volumeName"), Type: sdkMetric.ATTRIBUTE},
 			{Name: "podName", ValueFunc: definition.FromRaw("podName"), Type: sdkMetric.ATTRIBUTE},
@@ -1533,8 +1633,12 @@ func convertValue(v interface{}) (float64, error) {
 		return float64(v), nil
 	case prometheus.GaugeValue:
 		return float64(v), nil
+	case prometheus.CounterValue:
+		return float64(v), nil
 	case float64:
 		return v, nil
+	case definition.TimestampedValue:
+		return convertValue(v.Value)
 	case definition.FetchedValues:
 		if len(v) != 1 {
 			return 0, fmt.Errorf("unable to convert FetchedValues")
@@ -1581,7 +1685,7 @@ func toUtilization(dividendFunc, divisorFunc definition.FetchFunc) definition.Fe
 			return nil, fmt.Errorf("getting divisor metric: %w", err)
 		}
 
-		value, err := computePercentage(dividend, divisor)
+		value, err := computePercentage(definition.WithoutTimestamp(dividend), definition.WithoutTimestamp(divisor))
 		if err != nil {
 			return nil, fmt.Errorf("computing utilization: %w", err)
 		}
@@ -1653,7 +1757,7 @@ func fromPrometheusNumeric(value definition.FetchedValue) (definition.FetchedVal
 	return nil, fmt.Errorf("invalid type value '%v'. Expected 'gauge' or 'counter', got '%T'", value, value)
 }
 
-// Subtract returns a new FetchFunc that subtracts 2 values. It expects that the values are float64
+// Subtract returns a new FetchFunc that subtracts 2 numeric values, like float64s or Prometheus gauges and counters.
 func Subtract(left definition.FetchFunc, right definition.FetchFunc) definition.FetchFunc {
 	return func(groupLabel, entityID string, groups definition.RawGroups) (definition.FetchedValue, error) {
 		leftValue, err := left(groupLabel, entityID, groups)
@@ -1665,8 +1769,17 @@ func Subtract(left definition.FetchFunc, right definition.FetchFunc) definition.
 			return nil, err
 		}
 
-		result := leftValue.(float64) - rightValue.(float64)
-		return result, nil
+		minuend, err := convertValue(leftValue)
+		if err != nil {
+			return nil, fmt.Errorf("casting minuend: %w", err)
+		}
+
+		subtrahend, err := convertValue(rightValue)
+		if err != nil {
+			return nil, fmt.Errorf("casting subtrahend: %w", err)
+		}
+
+		return minuend - subtrahend, nil
 	}
 }
 
@@ -0,0 +1,143 @@
+package metric
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/config"
+	"github.com/newrelic/nri-kubernetes/v3/internal/pattern"
+	"github.com/newrelic/nri-kubernetes/v3/src/definition"
+	"github.com/newrelic/nri-kubernetes/v3/src/prometheus"
+)
+
+type metricRule struct {
+	entityTypes pattern.EntityTypes
+	include     []*regexp.Regexp
+	exclude     []*regexp.Regexp
+}
+
+// FilterDefinitions returns a copy of defs without the specs excluded by the rules in c, and without the queries
+// that only excluded specs read from. defs is not modified.
+//
+// Rules apply to the specs of the groups named as their entity types. The queries specs and groups read from are the
+// ones fetching their RawMetrics. Queries matching metric names by pattern are always kept, and so are all the queries
+// of targets with specs loaded from files that do not declare the raw metrics they read.
+func FilterDefinitions(defs map[string]Definitions, c config.Metrics) (map[string]Definitions, error) {
+	rules := make([]metricRule, 0, len(c.Rules))
+	for i, r := range c.Rules {
+		include, err := pattern.Compile(r.Include)
+		if err != nil {
+			return nil, fmt.Errorf("compiling include patterns of metric rule #%d: %w", i, err)
+		}
+
+		exclude, err := pattern.Compile(r.Exclude)
+		if err != nil {
+			return nil, fmt.Errorf("compiling exclude patterns of metric rule #%d: %w", i, err)
+		}
+
+		rules = append(rules, metricRule{entityTypes: r.EntityTypes, include: include, exclude: exclude})
+	}
+
+	filtered := make(map[string]Definitions, len(defs))
+	for target, d := range defs {
+		filtered[target] = d.filter(rules)
+	}
+
+	return filtered, nil
+}
+
+// excluded returns whether the rules exclude the spec of the given group.
+func excluded(rules []metricRule, group, name string) bool {
+	hasInclude := false
+	included := false
+
+	for _, r := range rules {
+		if !r.entityTypes.Matches(group) {
+			continue
+		}
+
+		if pattern.MatchesAny(r.exclude, name) {
+			return true
+		}
+
+		if len(r.include) > 0 {
+			hasInclude = true
+			included = included || pattern.MatchesAny(r.include, name)
+		}
+	}
+
+	return hasInclude && !included
+}
+
+func (d Definitions) filter(rules []metricRule) Definitions {
+	if len(rules) == 0 {
+		return d.clone()
+	}
+
+	specs := make(definition.SpecGroups, len(d.Specs))
+	var dropped []definition.Spec
+
+	for label, group := range d.Specs {
+		kept := make([]definition.Spec, 0, len(group.Specs))
+		for _, spec := range group.Specs {
+			if excluded(rules, label, spec.Name) {
+				dropped = append(dropped, spec)
+				continue
+			}
+			kept = append(kept, spec)
+		}
+
+		group.Specs = kept
+		specs[label] = group
+	}
+
+	queries := slices.Clone(d.Queries)
+	if !d.rawMetricsUnknown {
+		queries = unusedQueriesRemoved(queries, specs, dropped)
+	}
+
+	return Definitions{
+		Specs:             specs,
+		Queries:           queries,
+		rawMetricsUnknown: d.rawMetricsUnknown,
+	}
+}
+
+// unusedQueriesRemoved returns the queries without the ones fetching raw metrics dropped specs declare they read,
+// unless kept specs or groups declare they read them as well.
+func unusedQueriesRemoved(queries []prometheus.Query, kept definition.SpecGroups, dropped []definition.Spec) []prometheus.Query {
+	read := map[string]bool{}
+	for _, group := range kept {
+		read[group.SliceMetricName] = true
+		for _, name := range group.RawMetrics {
+			read[name] = true
+		}
+
+		for _, spec := range group.Specs {
+			for _, name := range spec.RawMetrics {
+				read[name] = true
+			}
+		}
+	}
+
+	unused := map[string]bool{}
+	for _, spec := range dropped {
+		for _, name := range spec.RawMetrics {
+			unused[name] = !read[name]
+		}
+	}
+
+	return slices.DeleteFunc(queries, func(q prometheus.Query) bool {
+		return q.MetricNameMatcher == nil && unused[queryKey(q)]
+	})
+}
+
+// queryKey returns the name of the raw metrics fetched by a query.
+func queryKey(q prometheus.Query) string {
+	if q.CustomName != "" {
+		return q.CustomName
+	}
+
+	return q.MetricName
+}
@@ -0,0 +1,193 @@
+package metric_test
+
+import (
+	"testing"
+
+	sdkMetric "github.com/newrelic/infra-integrations-sdk/data/metric"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/config"
+	"github.com/newrelic/nri-kubernetes/v3/internal/pattern"
+	"github.com/newrelic/nri-kubernetes/v3/src/definition"
+	"github.com/newrelic/nri-kubernetes/v3/src/metric"
+	"github.com/newrelic/nri-kubernetes/v3/src/prometheus"
+)
+
+func specNames(group definition.SpecGroup) []string {
+	names := make([]string, 0, len(group.Specs))
+	for _, spec := range group.Specs {
+		names = append(names, spec.Name)
+	}
+
+	return names
+}
+
+func queryNames(queries []prometheus.Query) []string {
+	names := make([]string, 0, len(queries))
+	for _, q := range queries {
+		names = append(names, q.MetricName)
+	}
+
+	return names
+}
+
+func TestFilterDefinitions(t *testing.T) {
+	t.Parallel()
+
+	cfsQueries := []string{
+		"container_cpu_cfs_periods_total",
+		"container_cpu_cfs_throttled_periods_total",
+		"container_cpu_cfs_throttled_seconds_total",
+	}
+
+	t.Run("keeps_queries_read_by_remaining_specs", func(t *testing.T) {
+		t.Parallel()
+
+		defs, err := metric.FilterDefinitions(metric.Builtin(), config.Metrics{Rules: []config.MetricRule{
+			{EntityTypes: []string{"container"}, Exclude: []string{"containerCpuCfs*Total"}},
+		}})
+		require.NoError(t, err)
+
+		kubelet := defs[metric.TargetKubelet]
+		containerSpecs := specNames(kubelet.Specs["container"])
+		assert.NotContains(t, containerSpecs, "containerCpuCfsPeriodsTotal")
+		assert.Contains(t, containerSpecs, "containerCpuCfsPeriodsDelta")
+		assert.Len(t, kubelet.Specs["container"].Specs, len(metric.KubeletSpecs["container"].Specs)-3)
+		assert.Subset(t, queryNames(kubelet.Queries), cfsQueries)
+	})
+
+	t.Run("drops_queries_only_read_by_excluded_specs", func(t *testing.T) {
+		t.Parallel()
+
+		defs, err := metric.FilterDefinitions(metric.Builtin(), config.Metrics{Rules: []config.MetricRule{
+			{EntityTypes: []string{"container"}, Exclude: []string{"containerCpuCfs*"}},
+			{Exclude: []string{"metadataResourceVersion"}},
+		}})
+		require.NoError(t, err)
+
+		kubeletQueries := queryNames(defs[metric.TargetKubelet].Queries)
+		for _, q := range cfsQueries {
+			assert.NotContains(t, kubeletQueries, q)
+		}
+		assert.Len(t, kubeletQueries, len(metric.CadvisorQueries)-len(cfsQueries))
+
+		ksm := defs[metric.TargetKSM]
+		assert.NotContains(t, specNames(ksm.Specs["cronjob"]), "metadataResourceVersion")
+		assert.NotContains(t, queryNames(ksm.Queries), "kube_cronjob_metadata_resource_version")
+		assert.Len(t, ksm.Queries, len(metric.KSMQueries)-1)
+	})
+
+	t.Run("keeps_queries_read_as_fallback_by_remaining_specs", func(t *testing.T) {
+		t.Parallel()
+
+		defs, err := metric.FilterDefinitions(metric.Builtin(), config.Metrics{Rules: []config.MetricRule{
+			{EntityTypes: []string{"api-server"}, Exclude: []string{"etcdObjectCounts"}},
+		}})
+		require.NoError(t, err)
+
+		apiServer := defs[metric.TargetAPIServer]
+		assert.NotContains(t, specNames(apiServer.Specs["api-server"]), "etcdObjectCounts")
+		assert.Contains(t, queryNames(apiServer.Queries), "etcd_object_counts")
+	})
+
+	t.Run("applies_include_patterns_to_matching_entity_types", func(t *testing.T) {
+		t.Parallel()
+
+		defs, err := metric.FilterDefinitions(metric.Builtin(), config.Metrics{Rules: []config.MetricRule{
+			{EntityTypes: []string{"cronjob"}, Include: []string{"regex:(is|next).*"}},
+		}})
+		require.NoError(t, err)
+
+		ksm := defs[metric.TargetKSM]
+		for _, name := range specNames(ksm.Specs["cronjob"]) {
+			assert.Regexp(t, "^(is|next)", name)
+		}
+		assert.NotEmpty(t, ksm.Specs["cronjob"].Specs)
+		assert.Len(t, ksm.Specs["job"].Specs, len(metric.KSMSpecs["job"].Specs))
+		assert.NotContains(t, specNames(ksm.Specs["cronjob"]), "createdAt")
+		assert.Contains(t, queryNames(ksm.Queries), "kube_cronjob_created")
+		assert.NotContains(t, queryNames(ksm.Queries), "kube_cronjob_info")
+	})
+
+	t.Run("does_not_modify_definitions_without_rules", func(t *testing.T) {
+		t.Parallel()
+
+		builtin := metric.Builtin()
+		defs, err := metric.FilterDefinitions(builtin, config.Metrics{})
+		require.NoError(t, err)
+
+		assert.Len(t, defs[metric.TargetKSM].Queries, len(metric.KSMQueries))
+		assert.Len(t, defs[metric.TargetKubelet].Specs["container"].Specs, len(metric.KubeletSpecs["container"].Specs))
+	})
+}
+
+func TestFilterDefinitions_KeepsQueriesReadByGenerators(t *testing.T) {
+	t.Parallel()
+
+	defs := map[string]metric.Definitions{
+		metric.TargetKSM: {
+			Specs: definition.SpecGroups{
+				"widget": {
+					IDGenerator:   prometheus.FromLabelValueEntityIDGenerator("kube_widget_info", "widget"),
+					TypeGenerator: prometheus.FromLabelValueEntityTypeGenerator("kube_widget_info"),
+					RawMetrics:    []string{"kube_widget_info"},
+					Specs: []definition.Spec{
+						{
+							Name:       "widgetName",
+							ValueFunc:  prometheus.FromLabelValue("kube_widget_info", "widget"),
+							RawMetrics: []string{"kube_widget_info"},
+							Type:       sdkMetric.ATTRIBUTE,
+						},
+						{
+							Name:       "used",
+							ValueFunc:  prometheus.FromValue("kube_widget_used"),
+							RawMetrics: []string{"kube_widget_used"},
+							Type:       sdkMetric.GAUGE,
+						},
+					},
+				},
+			},
+			Queries: []prometheus.Query{
+				{MetricName: "kube_widget_info"},
+				{MetricName: "kube_widget_used"},
+			},
+		},
+	}
+
+	filtered, err := metric.FilterDefinitions(defs, config.Metrics{Rules: []config.MetricRule{
+		{Exclude: []string{"widgetName", "used"}},
+	}})
+	require.NoError(t, err)
+
+	assert.Empty(t, filtered[metric.TargetKSM].Specs["widget"].Specs)
+	assert.Equal(t, []string{"kube_widget_info"}, queryNames(filtered[metric.TargetKSM].Queries))
+	assert.Len(t, defs[metric.TargetKSM].Queries, 2)
+}
+
+func TestFilterDefinitions_KeepsQueriesOfUndeclaredSpecFiles(t *testing.T) {
+	t.Parallel()
+
+	defs, err := metric.LoadDefinitionFiles(metric.Builtin(), "testdata/specs.yml")
+	require.NoError(t, err)
+
+	filtered, err := metric.FilterDefinitions(defs, config.Metrics{Rules: []config.MetricRule{
+		{Exclude: []string{"metadataResourceVersion"}},
+		{EntityTypes: []string{"container"}, Exclude: []string{"containerCpuCfs*"}},
+	}})
+	require.NoError(t, err)
+
+	ksm := filtered[metric.TargetKSM]
+	assert.NotContains(t, specNames(ksm.Specs["cronjob"]), "metadataResourceVersion")
+	assert.Len(t, ksm.Queries, len(defs[metric.TargetKSM].Queries))
+	assert.Len(t, filtered[metric.TargetKubelet].Queries, len(defs[metric.TargetKubelet].Queries)-3)
+}
+
+func TestFilterDefinitions_InvalidPattern(t *testing.T) {
+	t.Parallel()
+
+	_, err := metric.FilterDefinitions(metric.Builtin(), config.Metrics{Rules: []config.MetricRule{
+		{Exclude: []string{"regex:cpu("}},
+	}})
+	assert.ErrorIs(t, err, pattern.ErrInvalidPattern)
+}
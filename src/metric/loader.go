@@ -0,0 +1,478 @@
+package metric
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+
+	sdkMetric "github.com/newrelic/infra-integrations-sdk/data/metric"
+	"gopkg.in/yaml.v3"
+
+	"github.com/newrelic/nri-kubernetes/v3/src/definition"
+	"github.com/newrelic/nri-kubernetes/v3/src/prometheus"
+)
+
+// Targets are the names of the scrape targets definitions can be loaded for. The ones of the control plane
+// components match controlplane.ComponentName.
+const (
+	TargetKSM               = "ksm"
+	TargetKubelet           = "kubelet"
+	TargetScheduler         = "scheduler"
+	TargetEtcd              = "etcd"
+	TargetControllerManager = "controller-manager"
+	TargetAPIServer         = "api-server"
+)
+
+var (
+	// ErrUnknownTarget is returned when a spec file defines metrics for a target that does not exist.
+	ErrUnknownTarget = errors.New("unknown target")
+	// ErrUnknownFunc is returned when a spec file references a function that is not registered.
+	ErrUnknownFunc = errors.New("unknown function")
+	// ErrInvalidSpec is returned when a spec file is malformed, or a spec or query in it is incomplete.
+	ErrInvalidSpec = errors.New("invalid spec")
+)
+
+// Definitions are the spec groups used to populate the metrics of a target, and the Prometheus queries that fetch
+// the raw metrics they are computed from. Targets that are not scraped with Prometheus queries, like the kubelet
+// API, only use them for the part of their metrics that is.
+type Definitions struct {
+	Specs   definition.SpecGroups
+	Queries []prometheus.Query
+	// rawMetricsUnknown is set when specs or groups loaded from files do not declare the raw metrics they read, so
+	// queries cannot be told to be unused.
+	rawMetricsUnknown bool
+}
+
+// Builtin returns the definitions the integration ships with, indexed by target.
+func Builtin() map[string]Definitions {
+	return map[string]Definitions{
+		TargetKSM:               {Specs: KSMSpecs, Queries: KSMQueries},
+		TargetKubelet:           {Specs: KubeletSpecs, Queries: CadvisorQueries},
+		TargetScheduler:         {Specs: SchedulerSpecs, Queries: SchedulerQueries},
+		TargetEtcd:              {Specs: EtcdSpecs, Queries: EtcdQueries},
+		TargetControllerManager: {Specs: ControllerManagerSpecs, Queries: ControllerManagerQueries},
+		TargetAPIServer:         {Specs: APIServerSpecs, Queries: APIServerQueries},
+	}
+}
+
+// LoadDefinitionFiles returns a copy of base with the definitions of the given spec files applied, in order. base is
+// not modified.
+//
+// Spec files are YAML documents indexed by target, which can hold the queries and spec groups to add to it:
+//
+//	ksm:
+//	  queries:
+//	    - metricName: kube_deployment_spec_paused
+//	  specs:
+//	    deployment:
+//	      specs:
+//	        - name: isPaused
+//	          type: gauge
+//	          rawMetrics: [kube_deployment_spec_paused]
+//	          value:
+//	            func: Transform
+//	            args:
+//	              - func: FromValue
+//	                args: [kube_deployment_spec_paused]
+//	              - fromPrometheusNumeric
+//
+// Values, and the generators and guessers of spec groups, are built from the functions in the registries of this
+// package, referenced by name, with their arguments. Specs named as an existing one of the group replace it, and queries
+// for the same metric and custom name as an existing one replace it as well. Groups not present in the target must
+// define at least their idGenerator and typeGenerator.
+//
+// Specs, and groups setting generators, should list the raw metrics they read in rawMetrics, as Spec.RawMetrics.
+// Otherwise, the queries of their target are never removed by FilterDefinitions, as they cannot be told to be unused.
+func LoadDefinitionFiles(base map[string]Definitions, paths ...string) (map[string]Definitions, error) {
+	defs := make(map[string]Definitions, len(base))
+	for target, d := range base {
+		defs[target] = d.clone()
+	}
+
+	for _, path := range paths {
+		if err := loadDefinitionFile(defs, path); err != nil {
+			return nil, fmt.Errorf("loading spec file %q: %w", path, err)
+		}
+	}
+
+	return defs, nil
+}
+
+func loadDefinitionFile(defs map[string]Definitions, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening file: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+
+	var file map[string]definitionsFile
+	if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%w: %v", ErrInvalidSpec, err) //nolint: errorlint // yaml errors are not meant to be matched.
+	}
+
+	for _, target := range slices.Sorted(maps.Keys(file)) {
+		d, ok := defs[target]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownTarget, target)
+		}
+
+		if err := file[target].applyTo(&d); err != nil {
+			return fmt.Errorf("target %q: %w", target, err)
+		}
+
+		defs[target] = d
+	}
+
+	return nil
+}
+
+// clone returns a copy of the definitions which can be modified without affecting the original.
+func (d Definitions) clone() Definitions {
+	specs := make(definition.SpecGroups, len(d.Specs))
+	for name, group := range d.Specs {
+		group.Specs = slices.Clone(group.Specs)
+		group.Computed = slices.Clone(group.Computed)
+		group.RawMetrics = slices.Clone(group.RawMetrics)
+		specs[name] = group
+	}
+
+	return Definitions{
+		Specs:             specs,
+		Queries:           slices.Clone(d.Queries),
+		rawMetricsUnknown: d.rawMetricsUnknown,
+	}
+}
+
+type definitionsFile struct {
+	Queries []queryFile              `yaml:"queries"`
+	Specs   map[string]specGroupFile `yaml:"specs"`
+}
+
+type queryFile struct {
+	MetricName       string            `yaml:"metricName"`
+	MetricNamePrefix string            `yaml:"metricNamePrefix"`
+	MetricNameRegex  string            `yaml:"metricNameRegex"`
+	CustomName       string            `yaml:"customName"`
+	Labels           map[string]string `yaml:"labels"`
+	// LabelsOperator is either "and", the default, or "nor".
+	LabelsOperator string `yaml:"labelsOperator"`
+	// Matchers must all match the labels of a metric for the query to return it, besides Labels.
+	Matchers []matcherFile `yaml:"matchers"`
+}
+
+// matcherFile is a prometheus.LabelMatcher comparing the value of a label with one of the PromQL operators `=`, `!=`,
+// `=~` and `!~`. The `in` and `notIn` operators compare it with a list of values instead, and `present` and `absent`
+// take no value.
+type matcherFile struct {
+	Label  string   `yaml:"label"`
+	Op     string   `yaml:"op"`
+	Value  string   `yaml:"value"`
+	Values []string `yaml:"values"`
+}
+
+// matchTypes are the match types of the operators matcherFile supports.
+var matchTypes = map[string]prometheus.LabelMatchType{
+	"=":       prometheus.LabelEqual,
+	"!=":      prometheus.LabelNotEqual,
+	"=~":      prometheus.LabelRegexp,
+	"!~":      prometheus.LabelNotRegexp,
+	"in":      prometheus.LabelIn,
+	"notIn":   prometheus.LabelNotIn,
+	"present": prometheus.LabelPresent,
+	"absent":  prometheus.LabelAbsent,
+}
+
+func (mf matcherFile) build() (prometheus.LabelMatcher, error) {
+	matchType, ok := matchTypes[mf.Op]
+	if !ok {
+		return prometheus.LabelMatcher{}, fmt.Errorf("%w: unknown matcher operator %q", ErrInvalidSpec, mf.Op)
+	}
+
+	var values []string
+	switch matchType {
+	case prometheus.LabelIn, prometheus.LabelNotIn:
+		if mf.Value != "" {
+			return prometheus.LabelMatcher{}, fmt.Errorf("%w: operator %q takes a list of values", ErrInvalidSpec, mf.Op)
+		}
+		values = mf.Values
+	case prometheus.LabelPresent, prometheus.LabelAbsent:
+		if mf.Value != "" || len(mf.Values) > 0 {
+			return prometheus.LabelMatcher{}, fmt.Errorf("%w: operator %q takes no value", ErrInvalidSpec, mf.Op)
+		}
+	default:
+		if len(mf.Values) > 0 {
+			return prometheus.LabelMatcher{}, fmt.Errorf("%w: operator %q takes a single value", ErrInvalidSpec, mf.Op)
+		}
+		values = []string{mf.Value}
+	}
+
+	m, err := prometheus.NewLabelMatcher(matchType, mf.Label, values...)
+	if err != nil {
+		return m, fmt.Errorf("%w: %v", ErrInvalidSpec, err) //nolint: errorlint // only one error can be wrapped.
+	}
+
+	return m, nil
+}
+
+type specGroupFile struct {
+	IDGenerator     *valueExpr `yaml:"idGenerator"`
+	TypeGenerator   *valueExpr `yaml:"typeGenerator"`
+	NamespaceGetter string     `yaml:"namespaceGetter"`
+	PodGetter       string     `yaml:"podGetter"`
+	MsTypeGuesser   *valueExpr `yaml:"msTypeGuesser"`
+	SplitByLabel    string     `yaml:"splitByLabel"`
+	SliceMetricName string     `yaml:"sliceMetricName"`
+	RawMetrics      []string   `yaml:"rawMetrics"`
+	Specs           []specFile `yaml:"specs"`
+}
+
+type specFile struct {
+	Name       string     `yaml:"name"`
+	Type       string     `yaml:"type"`
+	Optional   bool       `yaml:"optional"`
+	RawMetrics []string   `yaml:"rawMetrics"`
+	Value      *valueExpr `yaml:"value"`
+}
+
+// valueExpr is a call to a registered function.
+type valueExpr struct {
+	Func string    `yaml:"func"`
+	Args []exprArg `yaml:"args"`
+}
+
+// exprArg is an argument of a valueExpr: either a string or a nested call.
+type exprArg struct {
+	scalar string
+	expr   *valueExpr
+}
+
+// UnmarshalYAML decodes scalars as strings and mappings as nested calls.
+func (a *exprArg) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		a.scalar = node.Value
+		return nil
+	case yaml.MappingNode:
+		// Decoding from a node does not honor KnownFields, so unexpected keys are checked here.
+		for i := 0; i < len(node.Content); i += 2 {
+			if key := node.Content[i].Value; key != "func" && key != "args" {
+				return fmt.Errorf("line %d: unexpected field %q in argument", node.Content[i].Line, key)
+			}
+		}
+
+		a.expr = &valueExpr{}
+		return node.Decode(a.expr) //nolint: wrapcheck
+	default:
+		return fmt.Errorf("line %d: arguments must be strings or function calls", node.Line)
+	}
+}
+
+func (f definitionsFile) applyTo(d *Definitions) error {
+	for i, qf := range f.Queries {
+		q, err := qf.build()
+		if err != nil {
+			return fmt.Errorf("query #%d: %w", i, err)
+		}
+
+		d.Queries = mergeQuery(d.Queries, q)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(f.Specs)) {
+		group, exists := d.Specs[name]
+		if err := f.Specs[name].applyTo(&group, exists); err != nil {
+			return fmt.Errorf("spec group %q: %w", name, err)
+		}
+
+		d.Specs[name] = group
+		d.rawMetricsUnknown = d.rawMetricsUnknown || !f.Specs[name].declaresRawMetrics()
+	}
+
+	return nil
+}
+
+func (qf queryFile) build() (prometheus.Query, error) {
+	q := prometheus.Query{
+		MetricName: qf.MetricName,
+		CustomName: qf.CustomName,
+		Labels:     prometheus.QueryLabels{Labels: qf.Labels},
+	}
+
+	names := 0
+	for _, name := range []string{qf.MetricName, qf.MetricNamePrefix, qf.MetricNameRegex} {
+		if name != "" {
+			names++
+		}
+	}
+	if names != 1 {
+		return q, fmt.Errorf("%w: exactly one of metricName, metricNamePrefix or metricNameRegex must be set", ErrInvalidSpec)
+	}
+
+	switch {
+	case qf.MetricNamePrefix != "":
+		q.MetricNameMatcher = prometheus.MetricNamePrefix(qf.MetricNamePrefix)
+	case qf.MetricNameRegex != "":
+		matcher, err := prometheus.NewMetricNameRegexp(qf.MetricNameRegex)
+		if err != nil {
+			return q, fmt.Errorf("%w: %v", ErrInvalidSpec, err) //nolint: errorlint // only one error can be wrapped.
+		}
+		q.MetricNameMatcher = matcher
+	}
+
+	switch qf.LabelsOperator {
+	case "", "and":
+		q.Labels.Operator = prometheus.QueryOpAnd
+	case "nor":
+		q.Labels.Operator = prometheus.QueryOpNor
+	default:
+		return q, fmt.Errorf("%w: unknown labels operator %q", ErrInvalidSpec, qf.LabelsOperator)
+	}
+
+	for i, mf := range qf.Matchers {
+		m, err := mf.build()
+		if err != nil {
+			return q, fmt.Errorf("matcher #%d: %w", i, err)
+		}
+		q.Labels.Matchers = append(q.Labels.Matchers, m)
+	}
+
+	return q, nil
+}
+
+// mergeQuery replaces the query of queries for the same metric and custom name as q, or appends q if there is none.
+// Queries matching metric names by prefix or regular expression are always appended.
+func mergeQuery(queries []prometheus.Query, q prometheus.Query) []prometheus.Query {
+	if q.MetricNameMatcher == nil {
+		for i, existing := range queries {
+			if existing.MetricNameMatcher == nil && existing.MetricName == q.MetricName && existing.CustomName == q.CustomName {
+				queries[i] = q
+				return queries
+			}
+		}
+	}
+
+	return append(queries, q)
+}
+
+func (gf specGroupFile) applyTo(group *definition.SpecGroup, exists bool) error { //nolint: cyclop
+	if !exists && (gf.IDGenerator == nil || gf.TypeGenerator == nil) {
+		return fmt.Errorf("%w: new groups must define idGenerator and typeGenerator", ErrInvalidSpec)
+	}
+
+	var err error
+	if gf.IDGenerator != nil {
+		if group.IDGenerator, err = build(idGenerators, *gf.IDGenerator); err != nil {
+			return fmt.Errorf("idGenerator: %w", err)
+		}
+	}
+
+	if gf.TypeGenerator != nil {
+		if group.TypeGenerator, err = build(typeGenerators, *gf.TypeGenerator); err != nil {
+			return fmt.Errorf("typeGenerator: %w", err)
+		}
+	}
+
+	if gf.MsTypeGuesser != nil {
+		if group.MsTypeGuesser, err = build(msTypeGuessers, *gf.MsTypeGuesser); err != nil {
+			return fmt.Errorf("msTypeGuesser: %w", err)
+		}
+	}
+
+	if gf.NamespaceGetter != "" {
+		getter, ok := namespaceGetters[gf.NamespaceGetter]
+		if !ok {
+			return fmt.Errorf("namespaceGetter: %w: %q", ErrUnknownFunc, gf.NamespaceGetter)
+		}
+		group.NamespaceGetter = getter
+	}
+
+	if gf.PodGetter != "" {
+		getter, ok := podGetters[gf.PodGetter]
+		if !ok {
+			return fmt.Errorf("podGetter: %w: %q", ErrUnknownFunc, gf.PodGetter)
+		}
+		group.PodGetter = getter
+	}
+
+	if gf.SplitByLabel != "" {
+		group.SplitByLabel = gf.SplitByLabel
+	}
+
+	if gf.SliceMetricName != "" {
+		group.SliceMetricName = gf.SliceMetricName
+	}
+
+	group.RawMetrics = append(group.RawMetrics, gf.RawMetrics...)
+
+	for i, sf := range gf.Specs {
+		spec, err := sf.build()
+		if err != nil {
+			return fmt.Errorf("spec #%d: %w", i, err)
+		}
+
+		group.Specs = mergeSpec(group.Specs, spec)
+	}
+
+	return nil
+}
+
+// declaresRawMetrics returns whether the group, if it sets generators, and all of its specs list the raw metrics they
+// read. An empty list declares that nothing is read.
+func (gf specGroupFile) declaresRawMetrics() bool {
+	if gf.RawMetrics == nil && (gf.IDGenerator != nil || gf.TypeGenerator != nil) {
+		return false
+	}
+
+	for _, sf := range gf.Specs {
+		if sf.RawMetrics == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (sf specFile) build() (definition.Spec, error) {
+	if sf.Name == "" {
+		return definition.Spec{}, fmt.Errorf("%w: name is required", ErrInvalidSpec)
+	}
+
+	sourceType, ok := sdkMetric.SourcesNameToType[sf.Type]
+	if !ok {
+		return definition.Spec{}, fmt.Errorf("%w: %q has unknown type %q", ErrInvalidSpec, sf.Name, sf.Type)
+	}
+
+	if sf.Value == nil {
+		return definition.Spec{}, fmt.Errorf("%w: %q has no value", ErrInvalidSpec, sf.Name)
+	}
+
+	valueFunc, err := buildFetchFunc(*sf.Value)
+	if err != nil {
+		return definition.Spec{}, fmt.Errorf("value of %q: %w", sf.Name, err)
+	}
+
+	return definition.Spec{
+		Name:       sf.Name,
+		ValueFunc:  valueFunc,
+		Type:       sourceType,
+		Optional:   sf.Optional,
+		RawMetrics: sf.RawMetrics,
+	}, nil
+}
+
+// mergeSpec replaces the spec of specs with the same name as spec, or appends spec if there is none.
+func mergeSpec(specs []definition.Spec, spec definition.Spec) []definition.Spec {
+	for i, existing := range specs {
+		if existing.Name == spec.Name {
+			specs[i] = spec
+			return specs
+		}
+	}
+
+	return append(specs, spec)
+}
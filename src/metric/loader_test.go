@@ -0,0 +1,438 @@
+package metric_test
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	sdkMetric "github.com/newrelic/infra-integrations-sdk/data/metric"
+	model "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/newrelic/nri-kubernetes/v3/src/definition"
+	"github.com/newrelic/nri-kubernetes/v3/src/metric"
+	"github.com/newrelic/nri-kubernetes/v3/src/prometheus"
+)
+
+func findSpec(t *testing.T, group definition.SpecGroup, name string) definition.Spec {
+	t.Helper()
+
+	for _, spec := range group.Specs {
+		if spec.Name == name {
+			return spec
+		}
+	}
+
+	require.Failf(t, "spec not found", "%q", name)
+	return definition.Spec{}
+}
+
+func TestLoadDefinitionFiles(t *testing.T) {
+	t.Parallel()
+
+	builtin := metric.Builtin()
+	defs, err := metric.LoadDefinitionFiles(builtin, "testdata/specs.yml")
+	require.NoError(t, err)
+
+	t.Run("does_not_modify_base_definitions", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Len(t, builtin[metric.TargetKSM].Specs["deployment"].Specs, len(metric.KSMSpecs["deployment"].Specs))
+		assert.Len(t, builtin[metric.TargetKSM].Queries, len(metric.KSMQueries))
+		assert.NotContains(t, metric.KSMSpecs, "widget")
+		assert.Equal(t, sdkMetric.GAUGE, findSpec(t, metric.KSMSpecs["deployment"], "createdAt").Type)
+	})
+
+	t.Run("replaces_and_appends_queries", func(t *testing.T) {
+		t.Parallel()
+
+		queries := defs[metric.TargetKSM].Queries
+		require.Len(t, queries, len(metric.KSMQueries)+1)
+
+		for _, q := range queries {
+			if q.MetricName == "kube_deployment_created" {
+				assert.Equal(t, prometheus.QueryOpNor, q.Labels.Operator)
+				assert.Equal(t, prometheus.Labels{"deployment": "ignored"}, q.Labels.Labels)
+			}
+		}
+
+		last := queries[len(queries)-1]
+		require.NotNil(t, last.MetricNameMatcher)
+		assert.True(t, last.MetricNameMatcher.Matches("kube_widget_info"))
+	})
+
+	t.Run("builds_query_matchers", func(t *testing.T) {
+		t.Parallel()
+
+		queries := defs[metric.TargetKubelet].Queries
+		require.Len(t, queries, len(builtin[metric.TargetKubelet].Queries)+1)
+
+		q := queries[len(queries)-1]
+		require.Equal(t, "container_memory_cache", q.MetricName)
+		require.Len(t, q.Labels.Matchers, 4)
+
+		sample := func(value float64, labels ...string) *model.Metric {
+			m := &model.Metric{Gauge: &model.Gauge{Value: proto.Float64(value)}}
+			for i := 0; i < len(labels); i += 2 {
+				m.Label = append(m.Label, &model.LabelPair{Name: proto.String(labels[i]), Value: proto.String(labels[i+1])})
+			}
+			return m
+		}
+
+		gauge := model.MetricType_GAUGE
+		family := q.Execute(&model.MetricFamily{
+			Name: proto.String("container_memory_cache"),
+			Type: &gauge,
+			Metric: []*model.Metric{
+				sample(1, "container", "coredns", "namespace", "kube-system", "image", "coredns:1.11", "pod", "coredns-2"),
+				sample(2, "container", "POD", "namespace", "kube-system", "image", "pause:3.9", "pod", "coredns-2"),
+				sample(3, "container", "nginx", "namespace", "default", "image", "nginx:1.27", "pod", "nginx-0"),
+				sample(4, "container", "coredns", "namespace", "kube-system", "pod", "coredns-2"),
+				sample(5, "container", "coredns", "namespace", "kube-system", "image", "coredns:1.11", "pod", "coredns-0"),
+			},
+		})
+		require.Len(t, family.Metrics, 1)
+		assert.Equal(t, prometheus.GaugeValue(1), family.Metrics[0].Value)
+	})
+
+	t.Run("builds_matching_metrics_specs", func(t *testing.T) {
+		t.Parallel()
+
+		status := findSpec(t, defs[metric.TargetKSM].Specs["widget"], "status")
+		raw := definition.RawGroups{
+			"widget": {
+				"default_w": {
+					"kube_widget_status_ready":  prometheus.Metric{Value: prometheus.GaugeValue(1)},
+					"kube_widget_status_broken": prometheus.Metric{Value: prometheus.GaugeValue(0)},
+					"kube_widget_used":          prometheus.Metric{Value: prometheus.GaugeValue(25)},
+				},
+			},
+		}
+		value, err := status.ValueFunc("widget", "default_w", raw)
+		require.NoError(t, err)
+		assert.Equal(t, definition.FetchedValues{
+			"kube_widget_status_ready":  prometheus.GaugeValue(1),
+			"kube_widget_status_broken": prometheus.GaugeValue(0),
+		}, value)
+	})
+
+	t.Run("builds_histogram_specs", func(t *testing.T) {
+		t.Parallel()
+
+		apiServer := defs[metric.TargetAPIServer].Specs["api-server"]
+		assert.Equal(t, sdkMetric.DELTA, findSpec(t, apiServer, "requestDurationCount").Type)
+
+		histogram := func(verb string, count, sum float64) prometheus.Metric {
+			return prometheus.Metric{
+				Labels: prometheus.Labels{"verb": verb},
+				Value: prometheus.HistogramValue{
+					SampleCount: count,
+					SampleSum:   sum,
+					Buckets: []prometheus.HistogramBucket{
+						{UpperBound: 1, CumulativeCount: count / 2},
+						{UpperBound: 2, CumulativeCount: count},
+						{UpperBound: math.Inf(1), CumulativeCount: count},
+					},
+				},
+			}
+		}
+		raw := definition.RawGroups{
+			"api-server": {
+				"api-server": {
+					"apiserver_request_duration_seconds": []prometheus.Metric{
+						histogram("GET", 10, 12),
+						histogram("LIST", 30, 45),
+					},
+				},
+			},
+		}
+
+		expected := map[string]definition.FetchedValues{
+			"requestDurationP99": {
+				"requestDurationP99_verb_GET":  1.98,
+				"requestDurationP99_verb_LIST": 1.98,
+			},
+			"requestDurationAverage": {
+				"requestDurationAverage_verb_GET":  1.2,
+				"requestDurationAverage_verb_LIST": 1.5,
+			},
+			"requestDurationCount": {
+				"requestDurationCount_verb_GET":  10.0,
+				"requestDurationCount_verb_LIST": 30.0,
+			},
+			"requestDurationSum": {
+				"requestDurationSum": 57.0,
+			},
+		}
+		for name, want := range expected {
+			value, err := findSpec(t, apiServer, name).ValueFunc("api-server", "api-server", raw)
+			require.NoError(t, err, name)
+			got, ok := value.(definition.FetchedValues)
+			require.True(t, ok, name)
+			require.Len(t, got, len(want), name)
+			for k, v := range want {
+				assert.InDelta(t, v, got[k], 1e-9, "%s: %s", name, k)
+			}
+		}
+	})
+
+	t.Run("replaces_and_appends_specs", func(t *testing.T) {
+		t.Parallel()
+
+		deployment := defs[metric.TargetKSM].Specs["deployment"]
+		assert.Len(t, deployment.Specs, len(metric.KSMSpecs["deployment"].Specs))
+		assert.NotNil(t, deployment.IDGenerator)
+		assert.Equal(t, sdkMetric.ATTRIBUTE, findSpec(t, deployment, "createdAt").Type)
+
+		isPaused := findSpec(t, deployment, "isPaused")
+		assert.True(t, isPaused.Optional)
+
+		raw := definition.RawGroups{
+			"deployment": {
+				"default_nginx": {
+					"kube_deployment_spec_paused": prometheus.Metric{Value: prometheus.GaugeValue(1)},
+				},
+			},
+		}
+		value, err := isPaused.ValueFunc("deployment", "default_nginx", raw)
+		require.NoError(t, err)
+		assert.Equal(t, float64(1), value)
+
+		etcd := defs[metric.TargetEtcd].Specs["etcd"]
+		assert.Len(t, etcd.Specs, len(metric.EtcdSpecs["etcd"].Specs))
+	})
+
+	t.Run("adds_groups", func(t *testing.T) {
+		t.Parallel()
+
+		widget, ok := defs[metric.TargetKSM].Specs["widget"]
+		require.True(t, ok)
+		require.NotNil(t, widget.IDGenerator)
+		require.NotNil(t, widget.TypeGenerator)
+		require.NotNil(t, widget.NamespaceGetter)
+		require.NotNil(t, widget.PodGetter)
+		assert.Equal(t, []string{"kube_widget_info"}, widget.RawMetrics)
+		assert.Equal(t, []string{"kube_widget_used", "kube_widget_capacity"}, findSpec(t, widget, "usedPercent").RawMetrics)
+
+		raw := definition.RawGroups{
+			"widget": {
+				"default_w": {
+					"kube_widget_used":     prometheus.Metric{Value: prometheus.GaugeValue(25)},
+					"kube_widget_capacity": prometheus.Metric{Value: prometheus.CounterValue(50), Timestamp: time.Unix(1700000000, 0)},
+				},
+			},
+		}
+		value, err := findSpec(t, widget, "usedPercent").ValueFunc("widget", "default_w", raw)
+		require.NoError(t, err)
+		assert.Equal(t, float64(50), value)
+
+		value, err = findSpec(t, widget, "free").ValueFunc("widget", "default_w", raw)
+		require.NoError(t, err)
+		assert.Equal(t, float64(25), value)
+	})
+}
+
+func TestLoadDefinitionFiles_Errors(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		spec string
+		err  error
+	}{
+		"unknown_target": {
+			spec: `
+ksm2:
+  queries:
+    - metricName: kube_pod_info
+`,
+			err: metric.ErrUnknownTarget,
+		},
+		"unknown_field": {
+			spec: `
+ksm:
+  query:
+    - metricName: kube_pod_info
+`,
+			err: metric.ErrInvalidSpec,
+		},
+		"unknown_value_func": {
+			spec: `
+ksm:
+  specs:
+    pod:
+      specs:
+        - name: foo
+          type: gauge
+          value: {func: FromNowhere, args: [kube_pod_foo]}
+`,
+			err: metric.ErrUnknownFunc,
+		},
+		"unknown_transform": {
+			spec: `
+ksm:
+  specs:
+    pod:
+      specs:
+        - name: foo
+          type: gauge
+          value:
+            func: Transform
+            args: [{func: FromValue, args: [kube_pod_foo]}, toSomething]
+`,
+			err: metric.ErrUnknownFunc,
+		},
+		"wrong_number_of_arguments": {
+			spec: `
+ksm:
+  specs:
+    pod:
+      specs:
+        - name: foo
+          type: gauge
+          value: {func: FromLabelValue, args: [kube_pod_foo]}
+`,
+			err: metric.ErrInvalidSpec,
+		},
+		"unknown_type": {
+			spec: `
+ksm:
+  specs:
+    pod:
+      specs:
+        - name: foo
+          type: counter
+          value: {func: FromValue, args: [kube_pod_foo]}
+`,
+			err: metric.ErrInvalidSpec,
+		},
+		"new_group_without_generators": {
+			spec: `
+ksm:
+  specs:
+    widget:
+      specs:
+        - name: foo
+          type: gauge
+          value: {func: FromValue, args: [kube_widget_foo]}
+`,
+			err: metric.ErrInvalidSpec,
+		},
+		"query_without_name": {
+			spec: `
+ksm:
+  queries:
+    - customName: foo
+`,
+			err: metric.ErrInvalidSpec,
+		},
+		"unknown_matcher_operator": {
+			spec: `
+kubelet:
+  queries:
+    - metricName: container_memory_cache
+      matchers:
+        - {label: container, op: "<>", value: POD}
+`,
+			err: metric.ErrInvalidSpec,
+		},
+		"invalid_matcher_regex": {
+			spec: `
+kubelet:
+  queries:
+    - metricName: container_memory_cache
+      matchers:
+        - {label: container, op: "=~", value: "("}
+`,
+			err: metric.ErrInvalidSpec,
+		},
+		"matcher_value_with_set_operator": {
+			spec: `
+kubelet:
+  queries:
+    - metricName: container_memory_cache
+      matchers:
+        - {label: container, op: in, value: POD}
+`,
+			err: metric.ErrInvalidSpec,
+		},
+		"matcher_values_with_presence_operator": {
+			spec: `
+kubelet:
+  queries:
+    - metricName: container_memory_cache
+      matchers:
+        - {label: container, op: absent, values: [POD]}
+`,
+			err: metric.ErrInvalidSpec,
+		},
+		"invalid_histogram_quantile": {
+			spec: `
+api-server:
+  specs:
+    api-server:
+      specs:
+        - name: foo
+          type: gauge
+          value: {func: FromHistogramQuantile, args: [apiserver_request_duration_seconds, foo, "99"]}
+`,
+			err: metric.ErrInvalidSpec,
+		},
+		"matching_metrics_without_matcher": {
+			spec: `
+ksm:
+  specs:
+    pod:
+      specs:
+        - name: foo
+          type: gauge
+          value: {func: FromMatchingMetrics, args: [kube_pod_]}
+`,
+			err: metric.ErrInvalidSpec,
+		},
+		"invalid_metric_name_regexp": {
+			spec: `
+ksm:
+  specs:
+    pod:
+      specs:
+        - name: foo
+          type: gauge
+          value:
+            func: FromMatchingMetrics
+            args: [{func: MetricNameRegexp, args: ["kube_("]}]
+`,
+			err: metric.ErrInvalidSpec,
+		},
+		"invalid_query_regex": {
+			spec: `
+ksm:
+  queries:
+    - metricNameRegex: "kube_("
+`,
+			err: metric.ErrInvalidSpec,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "specs.yml")
+			require.NoError(t, os.WriteFile(path, []byte(tc.spec), 0o600))
+
+			_, err := metric.LoadDefinitionFiles(metric.Builtin(), path)
+			assert.ErrorIs(t, err, tc.err)
+		})
+	}
+}
+
+func TestLoadDefinitionFiles_MissingFile(t *testing.T) {
+	t.Parallel()
+
+	_, err := metric.LoadDefinitionFiles(metric.Builtin(), "testdata/missing.yml")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
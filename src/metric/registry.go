@@ -0,0 +1,359 @@
+package metric
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/newrelic/nri-kubernetes/v3/src/definition"
+	kubeletMetric "github.com/newrelic/nri-kubernetes/v3/src/kubelet/metric"
+	"github.com/newrelic/nri-kubernetes/v3/src/prometheus"
+)
+
+// The registries below hold the functions that can be referenced by name from spec files. Each entry builds the
+// function out of the arguments given in the file, checking their number and kind.
+
+// fetchFuncs returns the functions that can be used as the value of a spec. It is a function rather than a variable
+// because some of them take other values as arguments, and build them from this same registry.
+func fetchFuncs() map[string]func(args []exprArg) (definition.FetchFunc, error) {
+	return map[string]func(args []exprArg) (definition.FetchFunc, error){
+		"FromRaw": func(args []exprArg) (definition.FetchFunc, error) {
+			s, err := stringArgs(args, 1)
+			if err != nil {
+				return nil, err
+			}
+			return definition.FromRaw(s[0]), nil
+		},
+		"FromValue": func(args []exprArg) (definition.FetchFunc, error) {
+			s, filters, err := stringArgsWithFilters(args, 1)
+			if err != nil {
+				return nil, err
+			}
+			return prometheus.FromValue(s[0], filters...), nil
+		},
+		"FromValueWithOverriddenName": func(args []exprArg) (definition.FetchFunc, error) {
+			s, filters, err := stringArgsWithFilters(args, 2)
+			if err != nil {
+				return nil, err
+			}
+			return prometheus.FromValueWithOverriddenName(s[0], s[1], filters...), nil
+		},
+		"FromValueWithLabelsFilter": func(args []exprArg) (definition.FetchFunc, error) {
+			s, filters, err := stringArgsWithFilters(args, 2)
+			if err != nil {
+				return nil, err
+			}
+			return prometheus.FromValueWithLabelsFilter(s[0], s[1], filters...), nil
+		},
+		"FromLabelValue": func(args []exprArg) (definition.FetchFunc, error) {
+			s, err := stringArgs(args, 2)
+			if err != nil {
+				return nil, err
+			}
+			return prometheus.FromLabelValue(s[0], s[1]), nil
+		},
+		"FromMetricWithPrefixedLabels": func(args []exprArg) (definition.FetchFunc, error) {
+			s, err := stringArgs(args, 2)
+			if err != nil {
+				return nil, err
+			}
+			return prometheus.FromMetricWithPrefixedLabels(s[0], s[1]), nil
+		},
+		"FromSummary": func(args []exprArg) (definition.FetchFunc, error) {
+			s, err := stringArgs(args, 1)
+			if err != nil {
+				return nil, err
+			}
+			return prometheus.FromSummary(s[0]), nil
+		},
+		"FromMatchingMetrics": func(args []exprArg) (definition.FetchFunc, error) {
+			if len(args) == 0 || args[0].expr == nil {
+				return nil, fmt.Errorf("%w: expected a metric name matcher", ErrInvalidSpec)
+			}
+
+			matcher, err := build(metricNameMatchers, *args[0].expr)
+			if err != nil {
+				return nil, err
+			}
+
+			_, filters, err := stringArgsWithFilters(args[1:], 0)
+			if err != nil {
+				return nil, err
+			}
+			return prometheus.FromMatchingMetrics(matcher, filters...), nil
+		},
+		"FromHistogramQuantile": func(args []exprArg) (definition.FetchFunc, error) {
+			s, filters, err := stringArgsWithFilters(args, 3)
+			if err != nil {
+				return nil, err
+			}
+
+			quantile, err := strconv.ParseFloat(s[2], 64)
+			if err != nil || quantile < 0 || quantile > 1 {
+				return nil, fmt.Errorf("%w: quantile must be a number between 0 and 1, got %q", ErrInvalidSpec, s[2])
+			}
+			return prometheus.FromHistogramQuantile(s[0], s[1], quantile, filters...), nil
+		},
+		"FromHistogramAverage": func(args []exprArg) (definition.FetchFunc, error) {
+			s, filters, err := stringArgsWithFilters(args, 2)
+			if err != nil {
+				return nil, err
+			}
+			return prometheus.FromHistogramAverage(s[0], s[1], filters...), nil
+		},
+		"FromHistogramCount": func(args []exprArg) (definition.FetchFunc, error) {
+			s, filters, err := stringArgsWithFilters(args, 2)
+			if err != nil {
+				return nil, err
+			}
+			return prometheus.FromHistogramCount(s[0], s[1], filters...), nil
+		},
+		"FromHistogramSum": func(args []exprArg) (definition.FetchFunc, error) {
+			s, filters, err := stringArgsWithFilters(args, 2)
+			if err != nil {
+				return nil, err
+			}
+			return prometheus.FromHistogramSum(s[0], s[1], filters...), nil
+		},
+		"Transform": func(args []exprArg) (definition.FetchFunc, error) {
+			if len(args) != 2 || args[0].expr == nil || args[1].expr != nil {
+				return nil, fmt.Errorf("%w: expected a value and the name of a transform", ErrInvalidSpec)
+			}
+
+			fetch, err := buildFetchFunc(*args[0].expr)
+			if err != nil {
+				return nil, err
+			}
+
+			transform, ok := transformFuncs[args[1].scalar]
+			if !ok {
+				return nil, fmt.Errorf("%w: transform %q", ErrUnknownFunc, args[1].scalar)
+			}
+
+			return definition.Transform(fetch, transform), nil
+		},
+		"toUtilization": func(args []exprArg) (definition.FetchFunc, error) {
+			f, err := fetchArgs(args, 2)
+			if err != nil {
+				return nil, err
+			}
+			return toUtilization(f[0], f[1]), nil
+		},
+		"Subtract": func(args []exprArg) (definition.FetchFunc, error) {
+			f, err := fetchArgs(args, 2)
+			if err != nil {
+				return nil, err
+			}
+			return Subtract(f[0], f[1]), nil
+		},
+	}
+}
+
+// transformFuncs are the functions that can be applied to a value with Transform.
+var transformFuncs = map[string]definition.TransformFunc{
+	"fromNano":              fromNano,
+	"fromNanoToMilli":       fromNanoToMilli,
+	"toTimestamp":           toTimestamp,
+	"toNumericBoolean":      toNumericBoolean,
+	"toCores":               toCores,
+	"fromPrometheusNumeric": fromPrometheusNumeric,
+}
+
+// labelsFilters are the functions that can be passed to the fetch functions accepting prometheus.LabelsFilter.
+var labelsFilters = map[string]func(args []exprArg) (prometheus.LabelsFilter, error){
+	"IncludeOnlyLabelsFilter": func(args []exprArg) (prometheus.LabelsFilter, error) {
+		s, err := stringArgs(args, -1)
+		if err != nil {
+			return nil, err
+		}
+		return prometheus.IncludeOnlyLabelsFilter(s...), nil
+	},
+	"IgnoreLabelsFilter": func(args []exprArg) (prometheus.LabelsFilter, error) {
+		s, err := stringArgs(args, -1)
+		if err != nil {
+			return nil, err
+		}
+		return prometheus.IgnoreLabelsFilter(s...), nil
+	},
+}
+
+// metricNameMatchers are the functions that can be passed to FromMatchingMetrics, matching metric names as the
+// metricNamePrefix and metricNameRegex fields of queries do.
+var metricNameMatchers = map[string]func(args []exprArg) (*prometheus.MetricNameMatcher, error){
+	"MetricNamePrefix": func(args []exprArg) (*prometheus.MetricNameMatcher, error) {
+		s, err := stringArgs(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return prometheus.MetricNamePrefix(s[0]), nil
+	},
+	"MetricNameRegexp": func(args []exprArg) (*prometheus.MetricNameMatcher, error) {
+		s, err := stringArgs(args, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		matcher, err := prometheus.NewMetricNameRegexp(s[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSpec, err) //nolint: errorlint // only one error can be wrapped.
+		}
+		return matcher, nil
+	},
+}
+
+// idGenerators are the functions that can be used as the IDGenerator of a spec group.
+var idGenerators = map[string]func(args []exprArg) (definition.EntityIDGeneratorFunc, error){
+	"FromLabelValueEntityIDGenerator": func(args []exprArg) (definition.EntityIDGeneratorFunc, error) {
+		s, err := stringArgs(args, 2)
+		if err != nil {
+			return nil, err
+		}
+		return prometheus.FromLabelValueEntityIDGenerator(s[0], s[1]), nil
+	},
+	"FromRawEntityIDGenerator": func(args []exprArg) (definition.EntityIDGeneratorFunc, error) {
+		if _, err := stringArgs(args, 0); err != nil {
+			return nil, err
+		}
+		return prometheus.FromRawEntityIDGenerator, nil
+	},
+}
+
+// typeGenerators are the functions that can be used as the TypeGenerator of a spec group.
+var typeGenerators = map[string]func(args []exprArg) (definition.EntityTypeGeneratorFunc, error){
+	"FromLabelValueEntityTypeGenerator": func(args []exprArg) (definition.EntityTypeGeneratorFunc, error) {
+		s, err := stringArgs(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return prometheus.FromLabelValueEntityTypeGenerator(s[0]), nil
+	},
+	"FromLabelValueEntityTypeGeneratorWithCustomGroup": func(args []exprArg) (definition.EntityTypeGeneratorFunc, error) {
+		s, err := stringArgs(args, 2)
+		if err != nil {
+			return nil, err
+		}
+		return prometheus.FromLabelValueEntityTypeGeneratorWithCustomGroup(s[0], s[1]), nil
+	},
+	"ControlPlaneComponentTypeGenerator": func(args []exprArg) (definition.EntityTypeGeneratorFunc, error) {
+		if _, err := stringArgs(args, 0); err != nil {
+			return nil, err
+		}
+		return prometheus.ControlPlaneComponentTypeGenerator, nil
+	},
+}
+
+// msTypeGuessers are the functions that can be used as the MsTypeGuesser of a spec group.
+var msTypeGuessers = map[string]func(args []exprArg) (definition.GuessFunc, error){
+	"K8sMetricSetTypeGuesser": func(args []exprArg) (definition.GuessFunc, error) {
+		if _, err := stringArgs(args, 0); err != nil {
+			return nil, err
+		}
+		return definition.K8sMetricSetTypeGuesser, nil
+	},
+	"metricSetTypeGuesserWithCustomGroup": func(args []exprArg) (definition.GuessFunc, error) {
+		s, err := stringArgs(args, 1)
+		if err != nil {
+			return nil, err
+		}
+		return metricSetTypeGuesserWithCustomGroup(s[0]), nil
+	},
+}
+
+// namespaceGetters are the functions that can be used as the NamespaceGetter of a spec group.
+var namespaceGetters = map[string]definition.NamespaceGetterFunc{
+	"FromLabelGetNamespace":         prometheus.FromLabelGetNamespace,
+	"kubelet.FromLabelGetNamespace": kubeletMetric.FromLabelGetNamespace,
+}
+
+// podGetters are the functions that can be used as the PodGetter of a spec group.
+var podGetters = map[string]definition.PodGetterFunc{
+	"FromLabelGetPod":       prometheus.FromLabelGetPod,
+	"kubelet.FromRawGetPod": kubeletMetric.FromRawGetPod,
+}
+
+// build looks up the function of the expression in the registry and builds it.
+func build[T any](registry map[string]func(args []exprArg) (T, error), e valueExpr) (T, error) {
+	builder, ok := registry[e.Func]
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf("%w: %q", ErrUnknownFunc, e.Func)
+	}
+
+	built, err := builder(e.Args)
+	if err != nil {
+		var zero T
+		return zero, fmt.Errorf("building %s: %w", e.Func, err)
+	}
+
+	return built, nil
+}
+
+func buildFetchFunc(e valueExpr) (definition.FetchFunc, error) {
+	return build(fetchFuncs(), e)
+}
+
+// stringArgs returns the arguments as strings, checking that there are exactly n of them, or any number if n is
+// negative.
+func stringArgs(args []exprArg, n int) ([]string, error) {
+	if n >= 0 && len(args) != n {
+		return nil, fmt.Errorf("%w: expected %d arguments, got %d", ErrInvalidSpec, n, len(args))
+	}
+
+	s := make([]string, 0, len(args))
+	for i, arg := range args {
+		if arg.expr != nil {
+			return nil, fmt.Errorf("%w: argument #%d must be a string", ErrInvalidSpec, i)
+		}
+		s = append(s, arg.scalar)
+	}
+
+	return s, nil
+}
+
+// stringArgsWithFilters returns the first n arguments as strings, and the rest as labels filters.
+func stringArgsWithFilters(args []exprArg, n int) ([]string, []prometheus.LabelsFilter, error) {
+	if len(args) < n {
+		return nil, nil, fmt.Errorf("%w: expected at least %d arguments, got %d", ErrInvalidSpec, n, len(args))
+	}
+
+	s, err := stringArgs(args[:n], n)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var filters []prometheus.LabelsFilter
+	for i, arg := range args[n:] {
+		if arg.expr == nil {
+			return nil, nil, fmt.Errorf("%w: argument #%d must be a labels filter", ErrInvalidSpec, n+i)
+		}
+
+		filter, err := build(labelsFilters, *arg.expr)
+		if err != nil {
+			return nil, nil, err
+		}
+		filters = append(filters, filter)
+	}
+
+	return s, filters, nil
+}
+
+// fetchArgs builds the arguments as fetch functions, checking that there are exactly n of them.
+func fetchArgs(args []exprArg, n int) ([]definition.FetchFunc, error) {
+	if len(args) != n {
+		return nil, fmt.Errorf("%w: expected %d arguments, got %d", ErrInvalidSpec, n, len(args))
+	}
+
+	funcs := make([]definition.FetchFunc, 0, len(args))
+	for i, arg := range args {
+		if arg.expr == nil {
+			return nil, fmt.Errorf("%w: argument #%d must be a value", ErrInvalidSpec, i)
+		}
+
+		f, err := buildFetchFunc(*arg.expr)
+		if err != nil {
+			return nil, err
+		}
+		funcs = append(funcs, f)
+	}
+
+	return funcs, nil
+}
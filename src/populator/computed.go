@@ -0,0 +1,73 @@
+package populator
+
+import (
+	"github.com/newrelic/infra-integrations-sdk/data/metric"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/entities"
+	"github.com/newrelic/nri-kubernetes/v3/src/definition"
+)
+
+// computedValues collects the values of the specs of an entity computed specs read from. Only single numeric values
+// are kept, as read by the specs, before rates and deltas are computed from them.
+type computedValues map[string]float64
+
+// newComputedValues returns the computedValues for an entity of specGroup, or nil if it has no computed specs.
+func newComputedValues(specGroup definition.SpecGroup) computedValues {
+	if len(specGroup.Computed) == 0 {
+		return nil
+	}
+
+	return computedValues{}
+}
+
+// add keeps the value of spec, if it is a number. It is safe to call on nil computedValues.
+func (cv computedValues) add(spec definition.Spec, val definition.FetchedValue) {
+	if cv == nil || spec.Type == metric.ATTRIBUTE {
+		return
+	}
+
+	switch val.(type) {
+	case definition.FetchedValues, definition.Summaries:
+		return
+	}
+
+	if f, err := toFloat(definition.WithoutTimestamp(val)); err == nil {
+		cv[spec.Name] = f
+	}
+}
+
+// populateComputed computes the computed specs of specGroup reported by decision, in order, and populates them with
+// populate. Each computed value is added to values, so later computed specs can read it.
+func populateComputed(
+	specGroup definition.SpecGroup,
+	decision entities.Decision,
+	values computedValues,
+	populate func(spec definition.Spec, val definition.FetchedValue) (bool, error),
+) (bool, []*EntityError) {
+	var populated bool
+	var errs []*EntityError
+
+	for _, computed := range specGroup.Computed {
+		val, err := computed.ComputeFunc(values)
+		if err != nil {
+			errs = append(errs, &EntityError{Spec: computed.Name, Err: err})
+			continue
+		}
+		if val == nil {
+			continue
+		}
+
+		values.add(definition.Spec{Name: computed.Name, Type: computed.Type}, val)
+		if !decision.Reports(computed.Name, computed.Type) {
+			continue
+		}
+
+		p, err := populate(definition.Spec{Name: computed.Name, Type: computed.Type}, val)
+		if err != nil {
+			errs = append(errs, &EntityError{Spec: computed.Name, Err: err})
+		}
+		populated = populated || p
+	}
+
+	return populated, errs
+}
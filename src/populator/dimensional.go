@@ -0,0 +1,233 @@
+package populator
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/newrelic/infra-integrations-sdk/data/attribute"
+	"github.com/newrelic/infra-integrations-sdk/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/integration"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/entities"
+	"github.com/newrelic/nri-kubernetes/v3/internal/labels"
+	"github.com/newrelic/nri-kubernetes/v3/src/definition"
+	"github.com/newrelic/nri-kubernetes/v3/src/dimensional"
+)
+
+// dimensionalTypes maps the source types of specs to the types of the dimensional metrics they are reported as.
+// Differences are computed by the agent out of the cumulative values, so no samples are kept between runs.
+var dimensionalTypes = map[metric.SourceType]string{
+	metric.GAUGE:  dimensional.MetricTypeGauge,
+	metric.RATE:   dimensional.MetricTypeCumulativeRate,
+	metric.PRATE:  dimensional.MetricTypeCumulativeRate,
+	metric.DELTA:  dimensional.MetricTypeCumulativeCount,
+	metric.PDELTA: dimensional.MetricTypeCumulativeCount,
+}
+
+// dimensionalMetricPrefix returns the prefix of the names of the dimensional metrics of the entities of a group, like
+// `k8s.container.`, so metrics named the same by different groups are told apart once they lose their event type.
+func dimensionalMetricPrefix(groupLabel string) string {
+	return "k8s." + groupLabel + "."
+}
+
+// processDimensionalUnit populates the dimensional metrics of a single entity (or sub-entity). Attribute specs are
+// added as attributes common to every metric of the entity.
+func processDimensionalUnit(
+	unit processingUnit,
+	config *definition.IntegrationPopulateConfig,
+	specGroup definition.SpecGroup,
+	groupLabel string,
+	groups definition.RawGroups,
+	decision entities.Decision,
+	extraAttributes []attribute.Attribute,
+) (bool, []error) {
+	var errs []error
+
+	e := config.Dimensional.Entity(unit.entityID, unit.entityType)
+	e.AddAttributes(extraAttributes...)
+	if config.Attributes != nil {
+		e.AddAttributes(config.Attributes.Attributes(customAttributesEntity(specGroup, groupLabel, unit.rawMetrics))...)
+	}
+	e.AddAttributes(
+		attribute.Attr("clusterName", config.ClusterName),
+		attribute.Attr("displayName", unit.entityID),
+	)
+
+	entityLabels := config.Labels.ForEntity(groupLabel)
+
+	populated, populateErrs := dimensionalPopulate(e, entityLabels, decision, groupLabel, unit.originalEntityID, groups, config.Specs)
+	errs = append(errs, entityErrors(groupLabel, unit.entityID, populateErrs)...)
+
+	if dropped := entityLabels.Dropped(); dropped > 0 {
+		e.AddMetric(dimensional.Metric{Name: dimensionalMetricPrefix(groupLabel) + definition.DroppedLabelsMetric, Type: dimensional.MetricTypeGauge, Value: float64(dropped)})
+	}
+
+	return populated, errs
+}
+
+// dimensionalPopulate is the counterpart of metricSetPopulate for dimensional metrics.
+func dimensionalPopulate(e *dimensional.Entity, entityLabels *labels.Entity, decision entities.Decision, groupLabel, entityID string, groups definition.RawGroups, specs definition.SpecGroups) (bool, []*EntityError) {
+	var populated bool
+	var errs []*EntityError
+
+	specGroup, ok := specs[groupLabel]
+	if !ok {
+		return false, nil
+	}
+
+	prefix := dimensionalMetricPrefix(groupLabel)
+	values := newComputedValues(specGroup)
+	for _, spec := range specGroup.Specs {
+		// Specs not reported are still fetched if computed specs may read them.
+		reported := decision.Reports(spec.Name, spec.Type)
+		if !reported && values == nil {
+			continue
+		}
+
+		val, err := spec.ValueFunc(groupLabel, entityID, groups)
+		if err != nil {
+			if reported && !spec.Optional {
+				errs = append(errs, &EntityError{Spec: spec.Name, Err: fmt.Errorf("cannot fetch value for metric %q: %w", spec.Name, err)})
+			}
+			continue
+		}
+		if val == nil {
+			continue
+		}
+
+		values.add(spec, val)
+		if !reported {
+			continue
+		}
+
+		p, err := populateDimensionalValue(e, entityLabels, prefix, spec, val)
+		if err != nil && !spec.Optional {
+			errs = append(errs, &EntityError{Spec: spec.Name, Err: err})
+		}
+		if p {
+			populated = true
+		}
+	}
+
+	p, computedErrs := populateComputed(specGroup, decision, values, func(spec definition.Spec, val definition.FetchedValue) (bool, error) {
+		return populateDimensionalValue(e, entityLabels, prefix, spec, val)
+	})
+	errs = append(errs, computedErrs...)
+
+	return populated || p, errs
+}
+
+// populateDimensionalValue adds the value of the spec to the entity, as attributes, or as metrics named with the given
+// prefix.
+func populateDimensionalValue(e *dimensional.Entity, entityLabels *labels.Entity, prefix string, spec definition.Spec, val definition.FetchedValue) (bool, error) {
+	if s, ok := val.(definition.Summaries); ok {
+		if spec.Type != metric.ATTRIBUTE {
+			for _, m := range summaries(s) {
+				m.Name = prefix + m.Name
+				e.AddMetric(m)
+			}
+			return len(s) > 0, nil
+		}
+		val = s.Flatten()
+	}
+
+	values, ok := val.(definition.FetchedValues)
+	if !ok {
+		values = definition.FetchedValues{spec.Name: val}
+	}
+
+	if spec.Type == metric.ATTRIBUTE {
+		var populated bool
+		for _, k := range slices.Sorted(maps.Keys(values)) {
+			v := definition.WithoutTimestamp(values[k])
+			if !entityLabels.Allow(k, v) {
+				continue
+			}
+			e.AddAttribute(k, v)
+			populated = true
+		}
+		return populated, nil
+	}
+
+	metricType, ok := dimensionalTypes[spec.Type]
+	if !ok {
+		return false, fmt.Errorf("%w %q: unsupported source type %v", ErrSetMetric, spec.Name, spec.Type)
+	}
+
+	var populated bool
+	for _, k := range slices.Sorted(maps.Keys(values)) {
+		m, err := dimensionalMetric(prefix+k, metricType, values[k])
+		if err != nil {
+			return populated, err
+		}
+		e.AddMetric(m)
+		populated = true
+	}
+
+	return populated, nil
+}
+
+func dimensionalMetric(name, metricType string, val definition.FetchedValue) (dimensional.Metric, error) {
+	m := dimensional.Metric{Name: name, Type: metricType}
+
+	if tv, ok := val.(definition.TimestampedValue); ok {
+		m.Timestamp = tv.Timestamp.UnixMilli()
+		val = tv.Value
+	}
+
+	value, err := toFloat(val)
+	if err != nil {
+		return m, fmt.Errorf("%w %q: %w", ErrSetMetric, name, err)
+	}
+	m.Value = value
+
+	return m, nil
+}
+
+// summaries returns the dimensional metrics of the summaries, in name order. Sums and quantiles that are not finite
+// are left out, as they cannot be encoded.
+func summaries(s definition.Summaries) []dimensional.Metric {
+	metrics := make([]dimensional.Metric, 0, len(s))
+
+	for _, name := range slices.Sorted(maps.Keys(s)) {
+		summary := dimensional.PrometheusSummary{SampleCount: float64(s[name].SampleCount), Quantiles: []dimensional.Quantile{}}
+
+		if sum := s[name].SampleSum; !math.IsInf(sum, 0) && !math.IsNaN(sum) {
+			summary.SampleSum = sum
+		}
+
+		for _, q := range s[name].Quantiles {
+			if math.IsInf(q.Value, 0) || math.IsNaN(q.Value) {
+				continue
+			}
+			summary.Quantiles = append(summary.Quantiles, dimensional.Quantile{Quantile: q.Quantile, Value: q.Value})
+		}
+
+		metrics = append(metrics, dimensional.Metric{Name: name, Type: dimensional.MetricTypePrometheusSummary, Value: summary})
+	}
+
+	return metrics
+}
+
+// populateDimensionalCluster is the counterpart of populateCluster for dimensional metrics. The cluster entity has no
+// metrics, only attributes and inventory.
+func populateDimensionalCluster(em *dimensional.Emitter, i *integration.Integration, clusterName string, k8sVersion fmt.Stringer, skipped int64) {
+	e := em.Entity(clusterName, "k8s:cluster")
+	k8sVersionStr := k8sVersion.String()
+
+	e.SetInventoryItem("cluster", "name", clusterName)
+	e.SetInventoryItem("cluster", "k8sVersion", k8sVersionStr)
+	e.SetInventoryItem("cluster", "newrelic.integrationVersion", i.IntegrationVersion)
+	e.SetInventoryItem("cluster", "newrelic.integrationName", i.Name)
+
+	e.AddAttributes(
+		attribute.Attr("clusterName", clusterName),
+		attribute.Attr("clusterK8sVersion", k8sVersionStr),
+	)
+
+	if skipped > 0 {
+		e.AddMetric(dimensional.Metric{Name: dimensionalMetricPrefix("cluster") + definition.SkippedEntitiesMetric, Type: dimensional.MetricTypeGauge, Value: float64(skipped)})
+	}
+}
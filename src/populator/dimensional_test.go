@@ -0,0 +1,144 @@
+package populator
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/newrelic/infra-integrations-sdk/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/integration"
+	model "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/newrelic/nri-kubernetes/v3/src/definition"
+	"github.com/newrelic/nri-kubernetes/v3/src/dimensional"
+	"github.com/newrelic/nri-kubernetes/v3/src/prometheus"
+)
+
+func TestIntegrationPopulator_Dimensional(t *testing.T) {
+	t.Parallel()
+
+	intgr, err := integration.New("nr.test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+
+	sampled := time.Unix(1700000000, 0)
+	emitter := dimensional.NewEmitter("nr.test", "1.0.0", &bytes.Buffer{})
+
+	populateConfig := testConfig(intgr)
+	populateConfig.Dimensional = emitter
+	populateConfig.Groups = definition.RawGroups{
+		"scheduler": {
+			"kube-scheduler": {
+				"name":           "kube-scheduler",
+				"cpuUsedCores":   0.5,
+				"restartCount":   definition.TimestampedValue{Value: float64(3), Timestamp: sampled},
+				"networkRxBytes": float64(1024),
+				"pendingCount":   uint64(4),
+				"schedulingTotal": []prometheus.Metric{{
+					Labels: prometheus.Labels{},
+					Value: &model.Summary{
+						SampleCount: proto.Uint64(10),
+						SampleSum:   proto.Float64(2.5),
+						Quantile:    []*model.Quantile{{Quantile: proto.Float64(0.5), Value: proto.Float64(0.2)}},
+					},
+				}},
+			},
+		},
+	}
+	populateConfig.Specs = definition.SpecGroups{
+		"scheduler": {
+			TypeGenerator: fromGroupEntityTypeGuessFunc,
+			Specs: []definition.Spec{
+				{Name: "name", ValueFunc: definition.FromRaw("name"), Type: metric.ATTRIBUTE},
+				{Name: "cpuUsedCores", ValueFunc: definition.FromRaw("cpuUsedCores"), Type: metric.GAUGE},
+				{Name: "restartCount", ValueFunc: definition.FromRaw("restartCount"), Type: metric.PDELTA},
+				{Name: "networkRxBytesPerSecond", ValueFunc: definition.FromRaw("networkRxBytes"), Type: metric.RATE},
+				{Name: "pods_count", ValueFunc: definition.FromRaw("pendingCount"), Type: metric.GAUGE},
+				{Name: "schedulingDuration", ValueFunc: prometheus.FromSummary("schedulingTotal"), Type: metric.GAUGE},
+			},
+		},
+	}
+
+	populated, errs := IntegrationPopulator(populateConfig)
+	require.True(t, populated)
+	require.Empty(t, errs)
+
+	assert.Empty(t, intgr.Entities, "metrics must not be populated as metric sets")
+
+	entities := map[string]*dimensional.Entity{}
+	for _, e := range emitter.Entities() {
+		entities[e.Name()] = e
+	}
+	require.Len(t, entities, 2)
+
+	scheduler := entities["kube-scheduler"]
+	require.NotNil(t, scheduler)
+	assert.Equal(t, map[string]interface{}{
+		"name":        "kube-scheduler",
+		"clusterName": defaultNS,
+		"displayName": "kube-scheduler",
+	}, scheduler.Common.Attributes)
+	assert.ElementsMatch(t, []dimensional.Metric{
+		{Name: "k8s.scheduler.cpuUsedCores", Type: dimensional.MetricTypeGauge, Value: 0.5},
+		{Name: "k8s.scheduler.restartCount", Type: dimensional.MetricTypeCumulativeCount, Timestamp: sampled.UnixMilli(), Value: float64(3)},
+		{Name: "k8s.scheduler.networkRxBytesPerSecond", Type: dimensional.MetricTypeCumulativeRate, Value: float64(1024)},
+		{Name: "k8s.scheduler.pods_count", Type: dimensional.MetricTypeGauge, Value: float64(4)},
+		{
+			Name: "k8s.scheduler.schedulingTotal",
+			Type: dimensional.MetricTypePrometheusSummary,
+			Value: dimensional.PrometheusSummary{
+				SampleCount: 10,
+				SampleSum:   2.5,
+				Quantiles:   []dimensional.Quantile{{Quantile: 0.5, Value: 0.2}},
+			},
+		},
+	}, scheduler.Metrics)
+
+	cluster := entities[defaultNS]
+	require.NotNil(t, cluster)
+	assert.Equal(t, "v1.15.42", cluster.Common.Attributes["clusterK8sVersion"])
+	assert.Equal(t, "v1.15.42", cluster.Inventory["cluster"]["k8sVersion"])
+}
+
+func TestIntegrationPopulator_Dimensional_prefixes_metrics_with_entity_type(t *testing.T) {
+	t.Parallel()
+
+	intgr, err := integration.New("nr.test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+
+	emitter := dimensional.NewEmitter("nr.test", "1.0.0", &bytes.Buffer{})
+
+	populateConfig := testConfig(intgr)
+	populateConfig.Dimensional = emitter
+	populateConfig.Groups = definition.RawGroups{
+		"pod":       {"default_web": {"cpuUsedCores": 0.5}},
+		"container": {"default_web_app": {"cpuUsedCores": 0.25}},
+	}
+	populateConfig.Specs = definition.SpecGroups{
+		"pod": {
+			TypeGenerator: fromGroupEntityTypeGuessFunc,
+			Specs:         []definition.Spec{{Name: "cpuUsedCores", ValueFunc: definition.FromRaw("cpuUsedCores"), Type: metric.GAUGE}},
+		},
+		"container": {
+			TypeGenerator: fromGroupEntityTypeGuessFunc,
+			Specs:         []definition.Spec{{Name: "cpuUsedCores", ValueFunc: definition.FromRaw("cpuUsedCores"), Type: metric.GAUGE}},
+		},
+	}
+
+	populated, errs := IntegrationPopulator(populateConfig)
+	require.True(t, populated)
+	require.Empty(t, errs)
+
+	metrics := map[string]float64{}
+	for _, e := range emitter.Entities() {
+		for _, m := range e.Metrics {
+			metrics[m.Name] = m.Value.(float64)
+		}
+	}
+
+	assert.Equal(t, 0.5, metrics["k8s.pod.cpuUsedCores"])
+	assert.Equal(t, 0.25, metrics["k8s.container.cpuUsedCores"])
+	assert.NotContains(t, metrics, "cpuUsedCores")
+}
@@ -3,11 +3,18 @@ package populator
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"runtime"
+	"slices"
 	"strconv"
+	"sync"
 
 	"github.com/newrelic/infra-integrations-sdk/data/attribute"
 	"github.com/newrelic/infra-integrations-sdk/data/metric"
 	"github.com/newrelic/infra-integrations-sdk/integration"
+	"github.com/newrelic/nri-kubernetes/v3/internal/attributes"
+	"github.com/newrelic/nri-kubernetes/v3/internal/entities"
+	"github.com/newrelic/nri-kubernetes/v3/internal/labels"
 	"github.com/newrelic/nri-kubernetes/v3/src/definition"
 	"github.com/newrelic/nri-kubernetes/v3/src/prometheus"
 )
@@ -27,43 +34,69 @@ type processingUnit struct {
 	rawMetrics       definition.RawMetrics
 }
 
+// groupEntity is an entity of a group, as returned by the grouper, waiting to be populated by a worker.
+type groupEntity struct {
+	groupLabel string
+	entityID   string
+	rawMetrics definition.RawMetrics
+}
+
 // IntegrationPopulator is the main orchestrator that populates an integration.Integration
 // object from the grouped metric data. It prepares "processing units" for each entity
-// or sub-entity and then populates them.
+// or sub-entity and then populates them. Entities are populated concurrently by config.Workers
+// goroutines, or GOMAXPROCS of them if it is not positive, so errors are returned in no particular order.
 func IntegrationPopulator(config *definition.IntegrationPopulateConfig) (bool, []error) {
-	var populated bool
-	var errs []error
+	var (
+		wg        sync.WaitGroup
+		lock      sync.Mutex
+		populated bool
+		errs      []error
+	)
+
+	workers := config.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
 
-	for groupLabel, entities := range config.Groups {
-		for entityID, rawMetrics := range entities {
-			specGroup, ok := config.Specs[groupLabel]
-			if !ok {
-				continue
+	registry := newEntityRegistry(config.Integration)
+	queue := make(chan groupEntity, workers)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var workerPopulated bool
+			var workerErrs []error
+			for ge := range queue {
+				pop, perr := populateGroupEntity(config, registry, ge)
+				workerErrs = append(workerErrs, perr...)
+				workerPopulated = workerPopulated || pop
 			}
 
-			extraAttributes, skip := filterGroup(config, specGroup, groupLabel, rawMetrics)
-			if skip {
-				continue
-			}
+			lock.Lock()
+			defer lock.Unlock()
+			errs = append(errs, workerErrs...)
+			populated = populated || workerPopulated
+		}()
+	}
 
-			unitsToProcess, err := prepareProcessingUnits(config, groupLabel, entityID, rawMetrics)
-			if err != nil {
-				errs = append(errs, err)
-				continue
-			}
+	for groupLabel, entities := range config.Groups {
+		if _, ok := config.Specs[groupLabel]; !ok {
+			continue
+		}
 
-			pop, perr := processEntities(unitsToProcess, config, specGroup, groupLabel, extraAttributes)
-			if len(perr) > 0 {
-				errs = append(errs, perr...)
-			}
-			if pop {
-				populated = true
-			}
+		for entityID, rawMetrics := range entities {
+			queue <- groupEntity{groupLabel: groupLabel, entityID: entityID, rawMetrics: rawMetrics}
 		}
 	}
-
-	if populated {
-		if err := populateCluster(config.Integration, config.ClusterName, config.K8sVersion); err != nil {
+	close(queue)
+	wg.Wait()
+
+	skipped := config.OptOut.TakeSkipped()
+	if populated && config.Dimensional != nil {
+		populateDimensionalCluster(config.Dimensional, config.Integration, config.ClusterName, config.K8sVersion, skipped)
+	} else if populated {
+		if err := populateCluster(config.Integration, config.ClusterName, config.K8sVersion, skipped); err != nil {
 			errs = append(errs, err)
 		}
 	}
@@ -71,6 +104,42 @@ func IntegrationPopulator(config *definition.IntegrationPopulateConfig) (bool, [
 	return populated, errs
 }
 
+// populateGroupEntity populates an entity of a group, or its sub-entities if the group is split by label.
+func populateGroupEntity(config *definition.IntegrationPopulateConfig, registry *entityRegistry, ge groupEntity) (bool, []error) {
+	specGroup := config.Specs[ge.groupLabel]
+
+	if optedOut(config, specGroup, ge.rawMetrics) {
+		return false, nil
+	}
+
+	extraAttributes, skip := filterGroup(config, specGroup, ge.groupLabel, ge.rawMetrics)
+	if skip {
+		return false, nil
+	}
+
+	unitsToProcess, err := prepareProcessingUnits(config, ge.groupLabel, ge.entityID, ge.rawMetrics)
+	if err != nil {
+		return false, []error{&EntityError{Group: ge.groupLabel, EntityID: ge.entityID, Err: err}}
+	}
+
+	return processEntities(unitsToProcess, config, registry, specGroup, ge.groupLabel, extraAttributes)
+}
+
+// optedOut returns whether the entity belongs to a namespace, or a pod, opting out of being scraped. Groups without a
+// NamespaceGetter are never skipped, and only the ones with a PodGetter are skipped with their pods.
+func optedOut(config *definition.IntegrationPopulateConfig, specGroup definition.SpecGroup, rawMetrics definition.RawMetrics) bool {
+	if config.OptOut == nil || specGroup.NamespaceGetter == nil {
+		return false
+	}
+
+	var pod string
+	if specGroup.PodGetter != nil {
+		pod = specGroup.PodGetter(rawMetrics)
+	}
+
+	return config.OptOut.Skip(specGroup.NamespaceGetter(rawMetrics), pod)
+}
+
 // filterGroup checks if an entity group should be filtered by namespace.
 // It returns true if the group should be filtered. For namespace-group entities,
 // it returns extra attributes to be added.
@@ -96,55 +165,155 @@ func filterGroup(
 }
 
 // processEntities handles the creation and population of a single entity (or sub-entity).
-func processEntities(unitsToProcess []processingUnit, config *definition.IntegrationPopulateConfig, specGroup definition.SpecGroup, groupLabel string, extraAttributes []attribute.Attribute) (bool, []error) {
+func processEntities(unitsToProcess []processingUnit, config *definition.IntegrationPopulateConfig, registry *entityRegistry, specGroup definition.SpecGroup, groupLabel string, extraAttributes []attribute.Attribute) (bool, []error) {
 	var populated bool
 	var errs []error
 
 	for _, unit := range unitsToProcess {
-		e, err := config.Integration.Entity(unit.entityID, unit.entityType)
-		if err != nil {
-			errs = append(errs, err)
+		groupsForThisEntity := definition.RawGroups{}
+		for groupName, groupValue := range config.Groups {
+			groupsForThisEntity[groupName] = groupValue
+		}
+		// Use originalEntityID for RawGroups key to match grouper's format
+		groupsForThisEntity[groupLabel] = map[string]definition.RawMetrics{unit.originalEntityID: unit.rawMetrics}
+
+		var decision entities.Decision
+		if config.Entities.AppliesTo(groupLabel) {
+			attrs := entityAttributes(specGroup, groupLabel, unit.originalEntityID, groupsForThisEntity)
+			decision = config.Entities.Decide(groupLabel, unit.entityID, attrs)
+		}
+		if decision.Drop() {
 			continue
 		}
 
-		attrs := make([]attribute.Attribute, len(extraAttributes), len(extraAttributes)+2)
-		copy(attrs, extraAttributes)
-		attrs = append(attrs,
-			attribute.Attr("clusterName", config.ClusterName),
-			attribute.Attr("displayName", e.Metadata.Name),
-		)
-		e.AddAttributes(attrs...)
+		var wasPopulated bool
+		var unitErrs []error
+		if config.Dimensional != nil {
+			wasPopulated, unitErrs = processDimensionalUnit(unit, config, specGroup, groupLabel, groupsForThisEntity, decision, extraAttributes)
+		} else {
+			wasPopulated, unitErrs = processUnit(unit, config, registry, specGroup, groupLabel, groupsForThisEntity, decision, extraAttributes)
+		}
+		errs = append(errs, unitErrs...)
+		populated = populated || wasPopulated
+	}
+	return populated, errs
+}
 
-		msTypeGuesser := config.MsTypeGuesser
-		if customGuesser := specGroup.MsTypeGuesser; customGuesser != nil {
-			msTypeGuesser = customGuesser
+// processUnit populates the metric set of a single entity (or sub-entity), which is locked meanwhile in case other
+// units share its ID.
+func processUnit(
+	unit processingUnit,
+	config *definition.IntegrationPopulateConfig,
+	registry *entityRegistry,
+	specGroup definition.SpecGroup,
+	groupLabel string,
+	groups definition.RawGroups,
+	decision entities.Decision,
+	extraAttributes []attribute.Attribute,
+) (bool, []error) {
+	var errs []error
+
+	e, unlock, err := registry.acquire(unit.entityID, unit.entityType)
+	if err != nil {
+		return false, []error{&EntityError{Group: groupLabel, EntityID: unit.entityID, Err: err}}
+	}
+	defer unlock()
+
+	attrs := make([]attribute.Attribute, len(extraAttributes), len(extraAttributes)+2)
+	copy(attrs, extraAttributes)
+	if config.Attributes != nil {
+		attrs = append(attrs, config.Attributes.Attributes(customAttributesEntity(specGroup, groupLabel, unit.rawMetrics))...)
+	}
+	attrs = append(attrs,
+		attribute.Attr("clusterName", config.ClusterName),
+		attribute.Attr("displayName", e.Metadata.Name),
+	)
+	e.AddAttributes(attrs...)
+
+	msTypeGuesser := config.MsTypeGuesser
+	if customGuesser := specGroup.MsTypeGuesser; customGuesser != nil {
+		msTypeGuesser = customGuesser
+	}
+	msType, err := msTypeGuesser(groupLabel)
+	if err != nil {
+		return false, []error{&EntityError{Group: groupLabel, EntityID: unit.entityID, Err: err}}
+	}
+	ms := e.NewMetricSet(msType)
+	samples := newSampleSet(config.Samples, e, msType)
+	entityLabels := config.Labels.ForEntity(groupLabel)
+
+	// Use originalEntityID for metric lookups (InheritAllLabelsFrom needs this)
+	populated, populateErrs := metricSetPopulate(ms, samples, entityLabels, decision, groupLabel, unit.originalEntityID, groups, config.Specs)
+	errs = append(errs, entityErrors(groupLabel, unit.entityID, populateErrs)...)
+	if dropped := entityLabels.Dropped(); dropped > 0 {
+		if _, err := populateSingleMetric(ms, definition.DroppedLabelsMetric, dropped, metric.GAUGE); err != nil {
+			errs = append(errs, &EntityError{Group: groupLabel, EntityID: unit.entityID, Spec: definition.DroppedLabelsMetric, Err: err})
 		}
-		msType, err := msTypeGuesser(groupLabel)
-		if err != nil {
-			errs = append(errs, err)
+	}
+
+	return populated, errs
+}
+
+// entityAttributes returns the values of the attribute specs of an entity, which entity rules match against, as
+// strings. Specs returning several attributes, like `label.*`, add each of them. Attributes that cannot be fetched
+// are skipped.
+func entityAttributes(specGroup definition.SpecGroup, groupLabel, entityID string, groups definition.RawGroups) map[string]string {
+	attrs := map[string]string{}
+
+	for _, spec := range specGroup.Specs {
+		if spec.Type != metric.ATTRIBUTE {
 			continue
 		}
-		ms := e.NewMetricSet(msType)
 
-		groupsForThisEntity := definition.RawGroups{}
-		for groupName, groupValue := range config.Groups {
-			groupsForThisEntity[groupName] = groupValue
+		val, err := spec.ValueFunc(groupLabel, entityID, groups)
+		if err != nil || val == nil {
+			continue
 		}
-		// Use originalEntityID for RawGroups key to match grouper's format
-		groupsForThisEntity[groupLabel] = map[string]definition.RawMetrics{unit.originalEntityID: unit.rawMetrics}
 
-		// Use originalEntityID for metric lookups (InheritAllLabelsFrom needs this)
-		wasPopulated, populateErrs := metricSetPopulate(ms, groupLabel, unit.originalEntityID, groupsForThisEntity, config.Specs)
-		if len(populateErrs) > 0 {
-			for _, err := range populateErrs {
-				errs = append(errs, fmt.Errorf("error populating metric for entity ID %s: %w", unit.entityID, err))
+		val = definition.WithoutTimestamp(val)
+		if values, ok := val.(definition.FetchedValues); ok {
+			for k, v := range values {
+				attrs[k] = fmt.Sprint(v)
 			}
+			continue
 		}
-		if wasPopulated {
-			populated = true
+
+		attrs[spec.Name] = fmt.Sprint(val)
+	}
+
+	return attrs
+}
+
+// customAttributesEntity returns the entity custom attributes are computed for, with the node and namespace its raw
+// metrics refer to.
+func customAttributesEntity(specGroup definition.SpecGroup, groupLabel string, rawMetrics definition.RawMetrics) attributes.Entity {
+	entity := attributes.Entity{
+		Type: groupLabel,
+		Node: nodeName(rawMetrics),
+	}
+
+	if specGroup.NamespaceGetter != nil {
+		entity.Namespace = specGroup.NamespaceGetter(rawMetrics)
+	}
+
+	return entity
+}
+
+// nodeName returns the name of the node an entity is in, from the `nodeName` metric of kubelet entities or the
+// `node` label of the Prometheus metrics of KSM ones. It returns an empty string if neither is present.
+func nodeName(rawMetrics definition.RawMetrics) string {
+	if name, ok := rawMetrics["nodeName"].(string); ok {
+		return name
+	}
+
+	for _, raw := range rawMetrics {
+		m, ok := raw.(prometheus.Metric)
+		if ok && m.Labels["node"] != "" {
+			return m.Labels["node"]
 		}
 	}
-	return populated, errs
+
+	return ""
 }
 
 // prepareProcessingUnits takes a raw entity group and, based on its SpecGroup rules,
@@ -284,10 +453,13 @@ func splitGroup(rawMetrics definition.RawMetrics, sliceMetricName, groupLabel, s
 	return subGroups, nil
 }
 
-// metricSetPopulate acts as a dispatcher, populating a metric set based on the spec definitions.
-func metricSetPopulate(ms *metric.Set, groupLabel, entityID string, groups definition.RawGroups, specs definition.SpecGroups) (bool, []error) {
+// metricSetPopulate acts as a dispatcher, populating a metric set based on the spec definitions, and then the computed
+// specs of the group.
+// Label and annotation attributes not allowed by entityLabels, and specs not reported by decision, are skipped.
+// The errors returned only hold the spec and its cause, to be completed by entityErrors.
+func metricSetPopulate(ms *metric.Set, samples sampleSet, entityLabels *labels.Entity, decision entities.Decision, groupLabel, entityID string, groups definition.RawGroups, specs definition.SpecGroups) (bool, []*EntityError) {
 	var populated bool
-	var errs []error
+	var errs []*EntityError
 
 	// 1. Look up the specific SpecGroup from the map using the groupLabel.
 	specGroup, ok := specs[groupLabel]
@@ -297,11 +469,18 @@ func metricSetPopulate(ms *metric.Set, groupLabel, entityID string, groups defin
 	}
 
 	// 2. The rest of the logic remains the same, using 'specGroup' which we just found.
+	values := newComputedValues(specGroup)
 	for _, spec := range specGroup.Specs {
+		// Specs not reported are still fetched if computed specs may read them.
+		reported := decision.Reports(spec.Name, spec.Type)
+		if !reported && values == nil {
+			continue
+		}
+
 		val, err := spec.ValueFunc(groupLabel, entityID, groups)
 		if err != nil {
-			if !spec.Optional {
-				errs = append(errs, fmt.Errorf("cannot fetch value for metric %q: %w", spec.Name, err))
+			if reported && !spec.Optional {
+				errs = append(errs, &EntityError{Spec: spec.Name, Err: fmt.Errorf("cannot fetch value for metric %q: %w", spec.Name, err)})
 			}
 			continue
 		}
@@ -309,38 +488,74 @@ func metricSetPopulate(ms *metric.Set, groupLabel, entityID string, groups defin
 			continue
 		}
 
-		p, e := populateValue(ms, &spec, val)
+		values.add(spec, val)
+		if !reported {
+			continue
+		}
+
+		p, e := populateValue(ms, samples, entityLabels, &spec, val)
 		if e != nil && !spec.Optional {
-			errs = append(errs, fmt.Errorf("populating entity %q: %w", entityID, e))
+			errs = append(errs, &EntityError{Spec: spec.Name, Err: e})
 		}
 		if p {
 			populated = true
 		}
 	}
-	return populated, errs
+
+	p, computedErrs := populateComputed(specGroup, decision, values, func(spec definition.Spec, val definition.FetchedValue) (bool, error) {
+		return populateValue(ms, samples, entityLabels, &spec, val)
+	})
+	errs = append(errs, computedErrs...)
+
+	return populated || p, errs
 }
 
 // populateValue is a helper that adds a fetched value to a metric set by determining its type.
-func populateValue(ms *metric.Set, spec *definition.Spec, val definition.FetchedValue) (bool, error) {
+func populateValue(ms *metric.Set, samples sampleSet, entityLabels *labels.Entity, spec *definition.Spec, val definition.FetchedValue) (bool, error) {
 	switch v := val.(type) {
 	case definition.FetchedValues:
-		return populateMetricsFromMap(ms, v, spec.Type)
+		return populateMetricsFromMap(ms, samples, entityLabels, v, spec.Type)
+	case definition.Summaries:
+		return populateMetricsFromMap(ms, samples, entityLabels, v.Flatten(), spec.Type)
+	case definition.TimestampedValue:
+		return samples.populate(ms, spec.Name, v, spec.Type)
 	default:
+		if spec.Type == metric.ATTRIBUTE && !entityLabels.Allow(spec.Name, v) {
+			return false, nil
+		}
 		return populateSingleMetric(ms, spec.Name, v, spec.Type)
 	}
 }
 
 // populateMetricsFromMap adds multiple metrics that all share a single type from the spec.
-func populateMetricsFromMap(ms *metric.Set, metrics definition.FetchedValues, sourceType metric.SourceType) (bool, error) {
+// Metrics are added in alphabetical order, so the same attributes are kept when a label limit is hit.
+func populateMetricsFromMap(ms *metric.Set, samples sampleSet, entityLabels *labels.Entity, metrics definition.FetchedValues, sourceType metric.SourceType) (bool, error) {
 	if len(metrics) == 0 {
 		return false, nil
 	}
-	for k, v := range metrics {
+
+	var populated bool
+	for _, k := range slices.Sorted(maps.Keys(metrics)) {
+		v := metrics[k]
+		if sourceType == metric.ATTRIBUTE && !entityLabels.Allow(k, v) {
+			continue
+		}
+
+		if tv, ok := v.(definition.TimestampedValue); ok {
+			p, err := samples.populate(ms, k, tv, sourceType)
+			if err != nil {
+				return false, err
+			}
+			populated = populated || p
+			continue
+		}
+
 		if err := ms.SetMetric(k, v, sourceType); err != nil {
 			return false, fmt.Errorf("%w %q: %w", ErrSetMetric, k, err)
 		}
+		populated = true
 	}
-	return true, nil
+	return populated, nil
 }
 
 // populateSingleMetric adds a single metric to the metric set.
@@ -352,7 +567,7 @@ func populateSingleMetric(ms *metric.Set, name string, value interface{}, source
 }
 
 // populateCluster fills cluster-level data.
-func populateCluster(i *integration.Integration, clusterName string, k8sVersion fmt.Stringer) error {
+func populateCluster(i *integration.Integration, clusterName string, k8sVersion fmt.Stringer, skipped int64) error {
 	e, err := i.Entity(clusterName, "k8s:cluster")
 	if err != nil {
 		// Add context to the error from the SDK.
@@ -390,5 +605,11 @@ func populateCluster(i *integration.Integration, clusterName string, k8sVersion
 		return fmt.Errorf("could not set clusterK8sVersion metric: %w", err)
 	}
 
+	if skipped > 0 {
+		if err = ms.SetMetric(definition.SkippedEntitiesMetric, float64(skipped), metric.GAUGE); err != nil {
+			return fmt.Errorf("could not set %s metric: %w", definition.SkippedEntitiesMetric, err)
+		}
+	}
+
 	return nil
 }
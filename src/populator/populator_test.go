@@ -6,12 +6,19 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/newrelic/infra-integrations-sdk/data/inventory"
 	"github.com/newrelic/infra-integrations-sdk/data/metric"
 	"github.com/newrelic/infra-integrations-sdk/integration"
+	"github.com/newrelic/nri-kubernetes/v3/internal/attributes"
+	"github.com/newrelic/nri-kubernetes/v3/internal/config"
+	"github.com/newrelic/nri-kubernetes/v3/internal/discovery"
+	"github.com/newrelic/nri-kubernetes/v3/internal/entities"
+	"github.com/newrelic/nri-kubernetes/v3/internal/labels"
+	"github.com/newrelic/nri-kubernetes/v3/internal/logutil"
 	"github.com/newrelic/nri-kubernetes/v3/src/definition"
 	kubeletMetric "github.com/newrelic/nri-kubernetes/v3/src/kubelet/metric"
 	"github.com/newrelic/nri-kubernetes/v3/src/prometheus"
@@ -19,7 +26,10 @@ import (
 	"github.com/stretchr/testify/require"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 const defaultNS = "playground"
@@ -29,6 +39,7 @@ var (
 	errTestGenerateType      = errors.New("error generating entity type")
 	errTestSettingEventType  = errors.New("error setting event type")
 	errTestIDGeneratorFailed = errors.New("id generator failed")
+	errTestDivisionByZero    = errors.New("division by zero")
 )
 
 func getRawGroupsSample() definition.RawGroups {
@@ -376,7 +387,12 @@ func TestIntegrationPopulator_EntitiesDataNotPopulated_ErrorSettingEntities(t *t
 
 	populated, errs := IntegrationPopulator(config)
 	assert.False(t, populated)
-	assert.EqualError(t, errs[0], "entity name and type are required when defining one")
+	assert.EqualError(t, errs[0], "error populating test entity ID : entity name and type are required when defining one")
+
+	var entityErr *EntityError
+	require.ErrorAs(t, errs[0], &entityErr)
+	assert.Equal(t, "test", entityErr.Group)
+	assert.Empty(t, entityErr.Spec)
 	assert.Equal(t, expectedData, intgr.Entities)
 }
 
@@ -538,8 +554,8 @@ func TestIntegrationPopulator_EntityIDGeneratorFuncWithError(t *testing.T) {
 
 	populated, errs := IntegrationPopulator(config)
 
-	expectedErr1 := "could not generate entity ID for entity_id_1: error generating entity ID"
-	expectedErr2 := "could not generate entity ID for entity_id_2: error generating entity ID"
+	expectedErr1 := "error populating test entity ID entity_id_1: could not generate entity ID for entity_id_1: error generating entity ID"
+	expectedErr2 := "error populating test entity ID entity_id_2: could not generate entity ID for entity_id_2: error generating entity ID"
 
 	errStrings := make([]string, len(errs))
 	for i, err := range errs {
@@ -550,6 +566,7 @@ func TestIntegrationPopulator_EntityIDGeneratorFuncWithError(t *testing.T) {
 	assert.Len(t, errs, 2)
 	assert.Contains(t, errStrings, expectedErr1)
 	assert.Contains(t, errStrings, expectedErr2)
+	assert.ErrorIs(t, errs[0], ErrGenerateID)
 	assert.Equal(t, intgr.Entities, []*integration.Entity{})
 }
 
@@ -1009,7 +1026,7 @@ func TestPopulateCluster(t *testing.T) {
 	k8sVersion := mockVersion{version: k8sVersionStr}
 
 	// --- 2. Execute the function under test ---
-	err = populateCluster(intgr, clusterName, k8sVersion)
+	err = populateCluster(intgr, clusterName, k8sVersion, 0)
 
 	// --- 3. Assertions ---
 
@@ -1040,6 +1057,7 @@ func TestPopulateCluster(t *testing.T) {
 	assert.Equal(t, "K8sClusterSample", metricSet.Metrics["event_type"])
 	assert.Equal(t, clusterName, metricSet.Metrics["clusterName"])
 	assert.Equal(t, k8sVersionStr, metricSet.Metrics["clusterK8sVersion"])
+	assert.NotContains(t, metricSet.Metrics, definition.SkippedEntitiesMetric, "No entity was skipped")
 }
 
 func TestMetricSetPopulate_SkipsNilValues(t *testing.T) {
@@ -1077,7 +1095,7 @@ func TestMetricSetPopulate_SkipsNilValues(t *testing.T) {
 	groups := definition.RawGroups{"test": {"test-entity": {}}}
 
 	// 2. Execute
-	populated, errs := metricSetPopulate(ms, "test", "test-entity", groups, specs)
+	populated, errs := metricSetPopulate(ms, sampleSet{}, nil, entities.Decision{}, "test", "test-entity", groups, specs)
 
 	// 3. Assert
 	assert.True(t, populated, "Expected populated to be true because one metric was set")
@@ -1088,6 +1106,336 @@ func TestMetricSetPopulate_SkipsNilValues(t *testing.T) {
 	assert.NotContains(t, ms.Metrics, "nil_metric")
 }
 
+func TestMetricSetPopulate_LabelGuard(t *testing.T) {
+	intgr, err := integration.New("nr.test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+
+	guard, err := labels.NewGuard(config.Labels{
+		MaxPerEntity: 2,
+		Rules:        []config.LabelRule{{EntityTypes: []string{"test"}, Deny: []string{"label.*-hash"}}},
+	})
+	require.NoError(t, err)
+
+	specs := definition.SpecGroups{
+		"test": {
+			Specs: []definition.Spec{
+				{
+					Name: "label.*",
+					ValueFunc: fromMultiple(definition.FetchedValues{
+						"label.pod-template-hash": "5d59d67564",
+						"label.c":                 "3",
+						"label.b":                 "2",
+						"label.a":                 "1",
+					}),
+					Type: metric.ATTRIBUTE,
+				},
+				{
+					Name: "status",
+					ValueFunc: func(_, _ string, _ definition.RawGroups) (definition.FetchedValue, error) {
+						return "Running", nil
+					},
+					Type: metric.ATTRIBUTE,
+				},
+			},
+		},
+	}
+
+	e, err := intgr.Entity("test-entity", "k8s:test")
+	require.NoError(t, err)
+	ms := e.NewMetricSet("TestSample")
+	groups := definition.RawGroups{"test": {"test-entity": {}}}
+	entityLabels := guard.ForEntity("test")
+
+	populated, errs := metricSetPopulate(ms, sampleSet{}, entityLabels, entities.Decision{}, "test", "test-entity", groups, specs)
+	assert.True(t, populated)
+	assert.Empty(t, errs)
+
+	assert.Equal(t, "1", ms.Metrics["label.a"])
+	assert.Equal(t, "2", ms.Metrics["label.b"])
+	assert.NotContains(t, ms.Metrics, "label.c")
+	assert.NotContains(t, ms.Metrics, "label.pod-template-hash")
+	assert.Equal(t, "Running", ms.Metrics["status"])
+	assert.Equal(t, 2, entityLabels.Dropped())
+}
+
+func TestIntegrationPopulator_LabelGuardReportsDroppedLabels(t *testing.T) {
+	intgr, err := integration.New("nr.test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+
+	guard, err := labels.NewGuard(config.Labels{MaxPerEntity: 1})
+	require.NoError(t, err)
+
+	populateConfig := testConfig(intgr)
+	populateConfig.Labels = guard
+	populateConfig.Specs = definition.SpecGroups{
+		"test": {
+			TypeGenerator: fromGroupEntityTypeGuessFunc,
+			Specs: []definition.Spec{
+				{
+					Name:      "label.*",
+					ValueFunc: fromMultiple(definition.FetchedValues{"label.a": "1", "label.b": "2", "label.c": "3"}),
+					Type:      metric.ATTRIBUTE,
+				},
+			},
+		},
+	}
+
+	populated, errs := IntegrationPopulator(populateConfig)
+	require.True(t, populated)
+	require.Empty(t, errs)
+
+	for _, e := range intgr.Entities {
+		if e.Metadata.Name == defaultNS {
+			continue // Cluster entity.
+		}
+
+		require.Len(t, e.Metrics, 1)
+		assert.Equal(t, "1", e.Metrics[0].Metrics["label.a"])
+		assert.Equal(t, float64(2), e.Metrics[0].Metrics[definition.DroppedLabelsMetric])
+	}
+}
+
+type fakeLabelsGetter map[string]string
+
+func (f fakeLabelsGetter) NodeLabels(name string) (map[string]string, error) {
+	return map[string]string{"region": f[name]}, nil
+}
+
+func (f fakeLabelsGetter) NamespaceLabels(name string) (map[string]string, error) {
+	return map[string]string{"team": f[name]}, nil
+}
+
+func TestIntegrationPopulator_CustomAttributes(t *testing.T) {
+	intgr, err := integration.New("nr.test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+
+	decorator, err := attributes.NewDecorator([]config.CustomAttribute{
+		{Name: "environment", Value: "production"},
+		{Name: "region", Value: `{{ .NodeLabel "region" }}`},
+		{Name: "team", Value: `{{ .NamespaceLabel "team" }}`, EntityTypes: []string{"pod"}},
+	}, fakeLabelsGetter{"node-1": "eu-west-1", "team-a": "a"})
+	require.NoError(t, err)
+
+	populateConfig := testConfig(intgr)
+	populateConfig.Attributes = decorator
+	populateConfig.Groups = definition.RawGroups{
+		"pod": {
+			"team-a_nginx": {"podName": "nginx", "nodeName": "node-1", "namespace": "team-a"},
+		},
+		"node": {
+			"node-1": {"nodeName": "node-1"},
+		},
+	}
+	populateConfig.Specs = definition.SpecGroups{
+		"pod": {
+			TypeGenerator:   fromGroupEntityTypeGuessFunc,
+			NamespaceGetter: kubeletMetric.FromLabelGetNamespace,
+			Specs: []definition.Spec{
+				{Name: "podName", ValueFunc: definition.FromRaw("podName"), Type: metric.ATTRIBUTE},
+			},
+		},
+		"node": {
+			TypeGenerator: fromGroupEntityTypeGuessFunc,
+			Specs: []definition.Spec{
+				{Name: "nodeName", ValueFunc: definition.FromRaw("nodeName"), Type: metric.ATTRIBUTE},
+			},
+		},
+	}
+
+	populated, errs := IntegrationPopulator(populateConfig)
+	require.True(t, populated)
+	require.Empty(t, errs)
+
+	expected := map[string]map[string]interface{}{
+		"team-a_nginx": {"environment": "production", "region": "eu-west-1", "team": "a"},
+		"node-1":       {"environment": "production", "region": "eu-west-1", "team": nil},
+	}
+
+	checked := 0
+	for _, e := range intgr.Entities {
+		attrs, ok := expected[e.Metadata.Name]
+		if !ok {
+			continue // Cluster entity.
+		}
+
+		checked++
+		require.Len(t, e.Metrics, 1)
+		for name, value := range attrs {
+			assert.Equal(t, value, e.Metrics[0].Metrics[name], "%s of %s", name, e.Metadata.Name)
+		}
+		assert.Equal(t, defaultNS, e.Metrics[0].Metrics["clusterName"])
+	}
+	assert.Equal(t, len(expected), checked)
+}
+
+func TestIntegrationPopulator_EntityRules(t *testing.T) {
+	intgr, err := integration.New("nr.test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+
+	filter, err := entities.NewFilter(config.Entities{Rules: []config.EntityRule{
+		{
+			EntityTypes: []string{"pod"},
+			Match:       []config.EntityMatcher{{Attribute: "label.ci", Value: "true"}},
+			Action:      config.EntityActionDrop,
+		},
+		{
+			EntityTypes: []string{"container"},
+			Match:       []config.EntityMatcher{{Attribute: "containerName", Value: "istio-*"}},
+			Action:      config.EntityActionKeepMetrics,
+			Metrics:     []string{"containerName", "cpu*"},
+		},
+	}})
+	require.NoError(t, err)
+
+	populateConfig := testConfig(intgr)
+	populateConfig.Entities = filter
+	populateConfig.Groups = definition.RawGroups{
+		"pod": {
+			"ns_ci-job":  {"podName": "ci-job", "labels": map[string]string{"ci": "true"}},
+			"ns_web-app": {"podName": "web-app", "labels": map[string]string{"ci": "false"}},
+		},
+		"container": {
+			"ns_web-app_istio-proxy": {"containerName": "istio-proxy", "cpuUsedCores": 0.1, "memoryUsedBytes": 1024},
+			"ns_web-app_app":         {"containerName": "app", "cpuUsedCores": 0.5, "memoryUsedBytes": 2048},
+			"ns_web-app_istio-init": {
+				"containerName":   definition.WithTimestamp("istio-init", time.Unix(1700000000, 0)),
+				"cpuUsedCores":    0.2,
+				"memoryUsedBytes": 512,
+			},
+		},
+	}
+	populateConfig.Specs = definition.SpecGroups{
+		"pod": {
+			TypeGenerator: fromGroupEntityTypeGuessFunc,
+			Specs: []definition.Spec{
+				{Name: "podName", ValueFunc: definition.FromRaw("podName"), Type: metric.ATTRIBUTE},
+				{Name: "label.*", ValueFunc: definition.Transform(definition.FromRaw("labels"), kubeletMetric.OneMetricPerLabel), Type: metric.ATTRIBUTE},
+			},
+		},
+		"container": {
+			TypeGenerator: fromGroupEntityTypeGuessFunc,
+			Specs: []definition.Spec{
+				{Name: "containerName", ValueFunc: definition.FromRaw("containerName"), Type: metric.ATTRIBUTE},
+				{Name: "cpuUsedCores", ValueFunc: definition.FromRaw("cpuUsedCores"), Type: metric.GAUGE},
+				{Name: "memoryUsedBytes", ValueFunc: definition.FromRaw("memoryUsedBytes"), Type: metric.GAUGE},
+			},
+		},
+	}
+
+	populated, errs := IntegrationPopulator(populateConfig)
+	require.True(t, populated)
+	require.Empty(t, errs)
+
+	expected := map[string]map[string]interface{}{
+		"ns_web-app":             {"podName": "web-app", "label.ci": "false"},
+		"ns_web-app_istio-proxy": {"containerName": "istio-proxy", "cpuUsedCores": 0.1, "memoryUsedBytes": nil},
+		"ns_web-app_app":         {"containerName": "app", "cpuUsedCores": 0.5, "memoryUsedBytes": float64(2048)},
+		"ns_web-app_istio-init":  {"containerName": "istio-init", "cpuUsedCores": 0.2, "memoryUsedBytes": nil},
+	}
+
+	checked := 0
+	for _, e := range intgr.Entities {
+		assert.NotEqual(t, "ns_ci-job", e.Metadata.Name)
+
+		metrics, ok := expected[e.Metadata.Name]
+		if !ok {
+			continue // Cluster entity.
+		}
+
+		checked++
+		require.Len(t, e.Metrics, 1)
+		for name, value := range metrics {
+			assert.Equal(t, value, e.Metrics[0].Metrics[name], "%s of %s", name, e.Metadata.Name)
+		}
+	}
+	assert.Equal(t, len(expected), checked)
+}
+
+func TestIntegrationPopulator_ComputedSpecs(t *testing.T) {
+	intgr, err := integration.New("nr.test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+
+	filter, err := entities.NewFilter(config.Entities{Rules: []config.EntityRule{
+		{
+			Match:   []config.EntityMatcher{{Attribute: "containerName", Value: "sidecar"}},
+			Action:  config.EntityActionKeepMetrics,
+			Metrics: []string{"memoryRequestPercent"},
+		},
+	}})
+	require.NoError(t, err)
+
+	ratio := func(values map[string]float64) (definition.FetchedValue, error) {
+		used, ok := values["memoryUsedBytes"]
+		requested, ok2 := values["memoryRequestedBytes"]
+		if !ok || !ok2 {
+			return nil, nil
+		}
+		if requested == 0 {
+			return nil, errTestDivisionByZero
+		}
+		return used / requested, nil
+	}
+	percent := func(values map[string]float64) (definition.FetchedValue, error) {
+		r, ok := values["memoryRequestRatio"]
+		if !ok {
+			return nil, nil
+		}
+		return r * 100, nil
+	}
+
+	populateConfig := testConfig(intgr)
+	populateConfig.Entities = filter
+	populateConfig.Groups = definition.RawGroups{
+		"container": {
+			"app":       {"containerName": "app", "memoryUsedBytes": 50, "memoryRequestedBytes": uint64(200)},
+			"sidecar":   {"containerName": "sidecar", "memoryUsedBytes": 10, "memoryRequestedBytes": uint64(100)},
+			"unlimited": {"containerName": "unlimited", "memoryUsedBytes": 10},
+			"zero":      {"containerName": "zero", "memoryUsedBytes": 10, "memoryRequestedBytes": uint64(0)},
+		},
+	}
+	populateConfig.Specs = definition.SpecGroups{
+		"container": {
+			TypeGenerator: fromGroupEntityTypeGuessFunc,
+			Specs: []definition.Spec{
+				{Name: "containerName", ValueFunc: definition.FromRaw("containerName"), Type: metric.ATTRIBUTE},
+				{Name: "memoryUsedBytes", ValueFunc: definition.FromRaw("memoryUsedBytes"), Type: metric.GAUGE},
+				{Name: "memoryRequestedBytes", ValueFunc: definition.FromRaw("memoryRequestedBytes"), Type: metric.GAUGE, Optional: true},
+			},
+			Computed: []definition.ComputedSpec{
+				{Name: "memoryRequestRatio", ComputeFunc: ratio, Type: metric.GAUGE},
+				{Name: "memoryRequestPercent", ComputeFunc: percent, Type: metric.GAUGE},
+			},
+		},
+	}
+
+	populated, errs := IntegrationPopulator(populateConfig)
+	require.True(t, populated)
+	require.Len(t, errs, 1)
+
+	var entityErr *EntityError
+	require.ErrorAs(t, errs[0], &entityErr)
+	assert.Equal(t, "zero", entityErr.EntityID)
+	assert.Equal(t, "memoryRequestRatio", entityErr.Spec)
+
+	expected := map[string]map[string]interface{}{
+		"app":       {"memoryUsedBytes": float64(50), "memoryRequestRatio": 0.25, "memoryRequestPercent": float64(25)},
+		"sidecar":   {"memoryUsedBytes": nil, "memoryRequestRatio": nil, "memoryRequestPercent": float64(10)},
+		"unlimited": {"memoryUsedBytes": float64(10), "memoryRequestRatio": nil, "memoryRequestPercent": nil},
+	}
+
+	for _, e := range intgr.Entities {
+		metrics, ok := expected[e.Metadata.Name]
+		if !ok {
+			continue // Cluster and zero entities.
+		}
+
+		require.Len(t, e.Metrics, 1)
+		for name, value := range metrics {
+			assert.Equal(t, value, e.Metrics[0].Metrics[name], "%s of %s", name, e.Metadata.Name)
+		}
+	}
+}
+
 func TestIntegrationPopulator_WithCrossGroupDependency2(t *testing.T) {
 	// Spec for a "pod" that needs to look up its "service" to generate a full entity ID.
 	podSpecWithDependency := definition.SpecGroup{
@@ -1168,6 +1516,190 @@ func TestIntegrationPopulator_WithCrossGroupDependency2(t *testing.T) {
 	assert.Equal(t, "my-pod-123_service-is-my-service-abc", podEntity.Metadata.Name)
 }
 
+func TestIntegrationPopulator_Workers(t *testing.T) {
+	intgr, err := integration.New("nr.test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+
+	// Both groups generate the same entities, so workers populate them concurrently.
+	sharedType := func(_ string, _ string, _ definition.RawGroups, prefix string) (string, error) {
+		return prefix + ":pod", nil
+	}
+	spec := func(name string) definition.Spec {
+		return definition.Spec{Name: name, ValueFunc: definition.FromRaw("value"), Type: metric.GAUGE}
+	}
+
+	const entitiesPerGroup = 500
+	groups := definition.RawGroups{"test": {}, "other": {}}
+	for i := range entitiesPerGroup {
+		id := fmt.Sprintf("pod-%d", i)
+		groups["test"][id] = definition.RawMetrics{"value": i}
+		groups["other"][id] = definition.RawMetrics{"value": i}
+	}
+
+	populateConfig := testConfig(intgr)
+	populateConfig.Workers = 8
+	populateConfig.Groups = groups
+	populateConfig.Specs = definition.SpecGroups{
+		"test":  {TypeGenerator: sharedType, Specs: []definition.Spec{spec("test_value")}},
+		"other": {TypeGenerator: sharedType, Specs: []definition.Spec{spec("other_value")}},
+	}
+
+	populated, errs := IntegrationPopulator(populateConfig)
+	require.True(t, populated)
+	require.Empty(t, errs)
+	require.Len(t, intgr.Entities, entitiesPerGroup+1, "Expected one entity per pod and the cluster entity")
+
+	for _, e := range intgr.Entities {
+		if e.Metadata.Name == defaultNS {
+			continue // Cluster entity.
+		}
+
+		require.Len(t, e.Metrics, 2, "Expected a metric set per group in %q", e.Metadata.Name)
+	}
+}
+
+func TestIntegrationPopulator_OptOut(t *testing.T) {
+	intgr, err := integration.New("nr.test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+
+	optedOut := map[string]string{discovery.ScrapeAnnotation: "false"}
+	client := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "noisy", Annotations: optedOut}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "batch", Namespace: "default", Annotations: optedOut}},
+	)
+	nsFilter := discovery.NewNamespaceFilter(nil, client, logutil.Discard)
+	t.Cleanup(func() { _ = nsFilter.Close() })
+	optOut := discovery.NewOptOutFilter(nsFilter, client)
+	t.Cleanup(func() { _ = optOut.Close() })
+
+	raw := func(namespace, pod string) definition.RawMetrics {
+		return definition.RawMetrics{"namespace": namespace, "podName": pod, "value": 1}
+	}
+	group := func(metricName string) definition.SpecGroup {
+		return definition.SpecGroup{
+			TypeGenerator:   fromGroupEntityTypeGuessFunc,
+			NamespaceGetter: kubeletMetric.FromLabelGetNamespace,
+			PodGetter:       kubeletMetric.FromRawGetPod,
+			Specs:           []definition.Spec{{Name: metricName, ValueFunc: definition.FromRaw("value"), Type: metric.GAUGE}},
+		}
+	}
+
+	populateConfig := testConfig(intgr)
+	populateConfig.OptOut = optOut
+	populateConfig.Specs = definition.SpecGroups{
+		"pod":       group("podValue"),
+		"container": group("containerValue"),
+		"volume":    group("volumeValue"),
+	}
+	populateConfig.Groups = definition.RawGroups{
+		"pod": {
+			"default_web":   raw("default", "web"),
+			"default_batch": raw("default", "batch"),
+			"noisy_worker":  raw("noisy", "worker"),
+		},
+		"container": {
+			"default_web_nginx":   raw("default", "web"),
+			"default_batch_job":   raw("default", "batch"),
+			"noisy_worker_worker": raw("noisy", "worker"),
+		},
+		"volume": {
+			"default_web_data":   raw("default", "web"),
+			"default_batch_data": raw("default", "batch"),
+		},
+	}
+
+	populated, errs := IntegrationPopulator(populateConfig)
+	require.True(t, populated)
+	require.Empty(t, errs)
+
+	var names []string
+	for _, e := range intgr.Entities {
+		names = append(names, e.Metadata.Name)
+		if e.Metadata.Name == defaultNS {
+			assert.Equal(t, float64(5), e.Metrics[0].Metrics[definition.SkippedEntitiesMetric], "Expected the entities of the opted out pod and namespace to be counted")
+		}
+	}
+	assert.ElementsMatch(t, []string{"default_web", "default_web_nginx", "default_web_data", defaultNS}, names)
+	assert.Zero(t, optOut.TakeSkipped(), "Expected the skipped entities to be taken once reported")
+}
+
+// syntheticCluster returns the groups and specs of a cluster with the given number of pods, each of them with as many
+// specs as a pod reported by the kubelet, some of them derived from others.
+func syntheticCluster(pods int) (definition.RawGroups, definition.SpecGroups) {
+	const rawMetrics = 16
+
+	specs := make([]definition.Spec, 0, 2*rawMetrics)
+	for m := range rawMetrics {
+		raw := fmt.Sprintf("raw_metric_%d", m)
+		specs = append(specs,
+			definition.Spec{Name: fmt.Sprintf("metric_%d", m), ValueFunc: definition.FromRaw(raw), Type: metric.GAUGE},
+			definition.Spec{
+				Name: fmt.Sprintf("metric_%d_percent", m),
+				ValueFunc: definition.Transform(definition.FromRaw(raw), func(v definition.FetchedValue) (definition.FetchedValue, error) {
+					return float64(v.(int)) / rawMetrics * 100, nil
+				}),
+				Type: metric.GAUGE,
+			},
+		)
+	}
+	specs = append(specs, definition.Spec{Name: "namespace", ValueFunc: definition.FromRaw("namespace"), Type: metric.ATTRIBUTE})
+
+	pod := make(map[string]definition.RawMetrics, pods)
+	for p := range pods {
+		raw := definition.RawMetrics{"namespace": fmt.Sprintf("namespace-%d", p%100)}
+		for m := range rawMetrics {
+			raw[fmt.Sprintf("raw_metric_%d", m)] = p + m
+		}
+		pod[fmt.Sprintf("pod-%d", p)] = raw
+	}
+
+	return definition.RawGroups{"pod": pod}, definition.SpecGroups{
+		"pod": {TypeGenerator: fromGroupEntityTypeGuessFunc, Specs: specs},
+	}
+}
+
+func benchmarkIntegrationPopulator(b *testing.B, pods int) {
+	groups, specs := syntheticCluster(pods)
+
+	for _, workers := range []int{1, 0} {
+		name := "serial"
+		if workers == 0 {
+			name = "parallel"
+		}
+
+		b.Run(name, func(b *testing.B) {
+			for range b.N {
+				b.StopTimer()
+				intgr, err := integration.New("nr.test", "1.0.0", integration.InMemoryStore())
+				require.NoError(b, err)
+				populateConfig := testConfig(intgr)
+				populateConfig.Workers = workers
+				populateConfig.Groups = groups
+				populateConfig.Specs = specs
+				b.StartTimer()
+
+				if _, errs := IntegrationPopulator(populateConfig); len(errs) > 0 {
+					b.Fatal(errs[0])
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkIntegrationPopulator_10kPods(b *testing.B) {
+	benchmarkIntegrationPopulator(b, 10_000)
+}
+
+func BenchmarkIntegrationPopulator_50kPods(b *testing.B) {
+	benchmarkIntegrationPopulator(b, 50_000)
+}
+
+func BenchmarkIntegrationPopulator_100kPods(b *testing.B) {
+	benchmarkIntegrationPopulator(b, 100_000)
+}
+
 type NamespaceFilterMock struct{}
 
 func (nf NamespaceFilterMock) IsAllowed(namespace string) bool {
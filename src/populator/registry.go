@@ -0,0 +1,68 @@
+package populator
+
+import (
+	"sync"
+
+	"github.com/newrelic/infra-integrations-sdk/integration"
+)
+
+type entityKey struct {
+	name       string
+	entityType string
+}
+
+// registeredEntity is an entity along with the lock held by the worker populating it.
+type registeredEntity struct {
+	lock   sync.Mutex
+	entity *integration.Entity
+}
+
+// entityRegistry creates the entities of an integration.Integration for the workers of IntegrationPopulator.
+// integration.Integration.Entity is safe for concurrent use, but it returns the same integration.Entity to units
+// sharing a name and type, whose attributes and metric sets are not, so each entity is locked while populated.
+// Entities are also indexed, so the integration is only looked up the first time each of them is requested.
+type entityRegistry struct {
+	integration *integration.Integration
+
+	lock     sync.Mutex
+	entities map[entityKey]*registeredEntity
+}
+
+func newEntityRegistry(i *integration.Integration) *entityRegistry {
+	return &entityRegistry{
+		integration: i,
+		entities:    map[entityKey]*registeredEntity{},
+	}
+}
+
+// acquire creates or retrieves the entity with the given name and type, and locks it until the returned function is
+// called.
+func (r *entityRegistry) acquire(name, entityType string) (*integration.Entity, func(), error) {
+	re, err := r.register(name, entityType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	re.lock.Lock()
+	return re.entity, re.lock.Unlock, nil
+}
+
+func (r *entityRegistry) register(name, entityType string) (*registeredEntity, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	key := entityKey{name: name, entityType: entityType}
+	if re, ok := r.entities[key]; ok {
+		return re, nil
+	}
+
+	e, err := r.integration.Entity(name, entityType)
+	if err != nil {
+		return nil, err
+	}
+
+	re := &registeredEntity{entity: e}
+	r.entities[key] = re
+
+	return re, nil
+}
@@ -0,0 +1,199 @@
+package populator
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/newrelic/nri-kubernetes/v3/src/definition"
+)
+
+// maxCauses is the maximum number of distinct causes a Failure is logged with.
+const maxCauses = 3
+
+// EntityError is an error populating an entity of a group, or a single metric of it when Spec is not empty.
+type EntityError struct {
+	Group    string
+	EntityID string
+	Spec     string
+	Err      error
+}
+
+func (e *EntityError) Error() string {
+	if e.Spec != "" {
+		return fmt.Sprintf("error populating metric for entity ID %s: %v", e.EntityID, e.Err)
+	}
+
+	return fmt.Sprintf("error populating %s entity ID %s: %v", e.Group, e.EntityID, e.Err)
+}
+
+func (e *EntityError) Unwrap() error {
+	return e.Err
+}
+
+// entityErrors completes the errors returned by metricSetPopulate with the group and ID of the entity they refer to.
+func entityErrors(groupLabel, entityID string, errs []*EntityError) []error {
+	completed := make([]error, 0, len(errs))
+	for _, err := range errs {
+		err.Group = groupLabel
+		err.EntityID = entityID
+		completed = append(completed, err)
+	}
+
+	return completed
+}
+
+// Failure aggregates the errors of the entities of a group that failed the same spec, or failed to be populated at
+// all when Spec is empty.
+type Failure struct {
+	Group string
+	Spec  string
+	// Entities is the number of distinct entities failing.
+	Entities int
+	// Total is the number of entities of the group. Entities split by label are counted once, along with their
+	// sub-entities.
+	Total int
+	// Causes maps the message of each distinct cause to the number of errors it caused.
+	Causes map[string]int
+}
+
+// Ratio returns the ratio of the entities of the group failing, between 0 and 1.
+func (f Failure) Ratio() float64 {
+	if f.Total == 0 {
+		return 1
+	}
+
+	return math.Min(1, float64(f.Entities)/float64(f.Total))
+}
+
+// String describes the failure along with its most common causes.
+func (f Failure) String() string {
+	causes := slices.SortedFunc(maps.Keys(f.Causes), func(a, b string) int {
+		return cmp.Or(cmp.Compare(f.Causes[b], f.Causes[a]), strings.Compare(a, b))
+	})
+	if len(causes) > maxCauses {
+		causes = append(causes[:maxCauses], fmt.Sprintf("and %d more", len(causes)-maxCauses))
+	}
+
+	what := "populating entities"
+	if f.Spec != "" {
+		what = fmt.Sprintf("spec %q", f.Spec)
+	}
+
+	return fmt.Sprintf("%s %s failed for %d/%d entities: %s", f.Group, what, f.Entities, f.Total, strings.Join(causes, "; "))
+}
+
+func (f Failure) key() string {
+	return f.Group + "/" + f.Spec
+}
+
+// Report aggregates the errors of a populate cycle by group and spec.
+type Report struct {
+	// Failures are sorted by group and spec.
+	Failures []Failure
+	// Errors are those not related to a single entity, like failing to populate the cluster entity.
+	Errors []error
+}
+
+// NewReport aggregates errs, as returned by IntegrationPopulator for groups, into a Report.
+func NewReport(groups definition.RawGroups, errs []error) Report {
+	var report Report
+
+	failures := map[string]*Failure{}
+	failed := map[string]map[string]struct{}{}
+	for _, err := range errs {
+		var entityErr *EntityError
+		if !errors.As(err, &entityErr) {
+			report.Errors = append(report.Errors, err)
+			continue
+		}
+
+		f := &Failure{Group: entityErr.Group, Spec: entityErr.Spec}
+		if existing, ok := failures[f.key()]; ok {
+			f = existing
+		} else {
+			f.Total = len(groups[f.Group])
+			f.Causes = map[string]int{}
+			failures[f.key()] = f
+			failed[f.key()] = map[string]struct{}{}
+		}
+
+		f.Causes[entityErr.Err.Error()]++
+		failed[f.key()][entityErr.EntityID] = struct{}{}
+	}
+
+	for key, f := range failures {
+		f.Entities = len(failed[key])
+		report.Failures = append(report.Failures, *f)
+	}
+	slices.SortFunc(report.Failures, func(a, b Failure) int {
+		return cmp.Or(strings.Compare(a.Group, b.Group), strings.Compare(a.Spec, b.Spec))
+	})
+
+	return report
+}
+
+// ErrorReporter logs the Report of every populate cycle of one or more jobs. Each failure is logged at Warn level when
+// it first shows up, or when its ratio of failing entities changed by more than a threshold since it was last logged
+// at that level, and at Debug level otherwise, so failures expected in a cluster do not flood the logs.
+// An ErrorReporter is safe for concurrent use.
+type ErrorReporter struct {
+	warnRatioChange float64
+
+	lock sync.Mutex
+	// warned holds the ratio failures of each job were last logged at Warn level with.
+	warned map[string]map[string]float64
+}
+
+// NewErrorReporter returns an ErrorReporter logging failures at Warn level again when their ratio of failing entities
+// changes by more than warnRatioChange.
+func NewErrorReporter(warnRatioChange float64) *ErrorReporter {
+	return &ErrorReporter{
+		warnRatioChange: warnRatioChange,
+		warned:          map[string]map[string]float64{},
+	}
+}
+
+// Log logs report, the result of the last populate cycle of job. Failures of the previous cycles not in report are
+// logged as recovered. A nil ErrorReporter logs nothing.
+func (r *ErrorReporter) Log(logger *log.Logger, job string, report Report) {
+	if r == nil {
+		return
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	previous := r.warned[job]
+	current := make(map[string]float64, len(report.Failures))
+	for _, f := range report.Failures {
+		ratio := f.Ratio()
+
+		last, seen := previous[f.key()]
+		if !seen || math.Abs(ratio-last) > r.warnRatioChange {
+			logger.Warnf("Populating %s: %s", job, f)
+			last = ratio
+		} else {
+			logger.Debugf("Populating %s: %s", job, f)
+		}
+		current[f.key()] = last
+	}
+
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			logger.Infof("Populating %s: %s no longer failing", job, strings.TrimSuffix(key, "/"))
+		}
+	}
+	r.warned[job] = current
+
+	for _, err := range report.Errors {
+		logger.Warnf("Populating %s: %v", job, err)
+	}
+}
@@ -0,0 +1,103 @@
+package populator
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/src/definition"
+)
+
+func testReportGroups(entities int) definition.RawGroups {
+	group := map[string]definition.RawMetrics{}
+	for i := 0; i < entities; i++ {
+		group[fmt.Sprintf("pod-%d", i)] = definition.RawMetrics{}
+	}
+
+	return definition.RawGroups{"pod": group}
+}
+
+func specErrors(spec string, entities int) []error {
+	var errs []error
+	for i := 0; i < entities; i++ {
+		errs = append(errs, &EntityError{Group: "pod", EntityID: fmt.Sprintf("pod-%d", i), Spec: spec, Err: errors.New("metric not found")})
+	}
+
+	return errs
+}
+
+func TestNewReport(t *testing.T) {
+	t.Parallel()
+
+	errs := []error{
+		&EntityError{Group: "pod", EntityID: "pod-0", Spec: "cpuUsedCores", Err: errors.New("metric not found")},
+		&EntityError{Group: "pod", EntityID: "pod-1", Spec: "cpuUsedCores", Err: errors.New("metric not found")},
+		&EntityError{Group: "pod", EntityID: "pod-1", Spec: "cpuUsedCores", Err: errors.New("label not found")},
+		&EntityError{Group: "pod", EntityID: "pod-2", Err: errors.New("entity name and type are required")},
+		errors.New("could not create cluster entity"),
+	}
+
+	report := NewReport(testReportGroups(4), errs)
+
+	assert.Equal(t, []Failure{
+		{Group: "pod", Entities: 1, Total: 4, Causes: map[string]int{"entity name and type are required": 1}},
+		{Group: "pod", Spec: "cpuUsedCores", Entities: 2, Total: 4, Causes: map[string]int{"metric not found": 2, "label not found": 1}},
+	}, report.Failures)
+	assert.Equal(t, []error{errs[4]}, report.Errors)
+
+	assert.Equal(t, 0.5, report.Failures[1].Ratio())
+	assert.Equal(t,
+		`pod spec "cpuUsedCores" failed for 2/4 entities: metric not found; label not found`,
+		report.Failures[1].String(),
+	)
+}
+
+func TestErrorReporter(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	logger := log.New()
+	logger.SetOutput(buf)
+	logger.SetLevel(log.DebugLevel)
+	logger.SetFormatter(&log.TextFormatter{DisableTimestamp: true})
+
+	reporter := NewErrorReporter(0.25)
+	groups := testReportGroups(10)
+
+	for _, tc := range []struct {
+		name     string
+		failing  int
+		expected string
+	}{
+		{name: "new_failure", failing: 2, expected: "level=warning"},
+		{name: "same_ratio", failing: 2, expected: "level=debug"},
+		{name: "ratio_below_threshold", failing: 4, expected: "level=debug"},
+		{name: "ratio_above_threshold", failing: 5, expected: "level=warning"},
+		{name: "recovered", failing: 0, expected: "level=info"},
+		{name: "failing_again", failing: 5, expected: "level=warning"},
+	} {
+		buf.Reset()
+		reporter.Log(logger, "kubelet", NewReport(groups, specErrors("cpuUsedCores", tc.failing)))
+
+		require.Contains(t, buf.String(), tc.expected, tc.name)
+		assert.Equal(t, 1, bytes.Count(buf.Bytes(), []byte("\n")), tc.name)
+	}
+}
+
+func TestErrorReporter_Nil(t *testing.T) {
+	t.Parallel()
+
+	buf := &bytes.Buffer{}
+	logger := log.New()
+	logger.SetOutput(buf)
+
+	var reporter *ErrorReporter
+	reporter.Log(logger, "kubelet", NewReport(testReportGroups(1), specErrors("cpuUsedCores", 1)))
+
+	assert.Empty(t, buf.String())
+}
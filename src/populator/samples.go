@@ -0,0 +1,109 @@
+package populator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/newrelic/infra-integrations-sdk/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/integration"
+	"github.com/newrelic/infra-integrations-sdk/persist"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/storer"
+	"github.com/newrelic/nri-kubernetes/v3/src/definition"
+)
+
+// sample is the value of a TimestampedValue as kept between runs.
+type sample struct {
+	Value     float64
+	Timestamp time.Time
+}
+
+// sampleSet computes the RATE and DELTA metrics of a metric set populated from TimestampedValues, using the time
+// elapsed between samples instead of between collections. Previous samples are kept in store under prefix.
+// If store is nil, timestamps are ignored and those metrics are computed by the SDK as any other.
+type sampleSet struct {
+	store  storer.Storer
+	prefix string
+}
+
+func newSampleSet(store storer.Storer, e *integration.Entity, msType string) sampleSet {
+	return sampleSet{
+		store:  store,
+		prefix: fmt.Sprintf("%s:%s:%s", e.Metadata.Namespace, e.Metadata.Name, msType),
+	}
+}
+
+// populate sets a metric from a TimestampedValue. It returns false with no error if the sample is not newer than the
+// previous one, as it happens when an exporter serves a stale cache, so no metric is reported for it.
+func (s sampleSet) populate(ms *metric.Set, name string, v definition.TimestampedValue, sourceType metric.SourceType) (bool, error) {
+	if s.store == nil || !isDifference(sourceType) {
+		return populateSingleMetric(ms, name, v.Value, sourceType)
+	}
+
+	value, err := toFloat(v.Value)
+	if err != nil {
+		return false, fmt.Errorf("%w %q: %w", ErrSetMetric, name, err)
+	}
+
+	key := s.prefix + ":" + name
+
+	var previous sample
+	_, err = s.store.Get(key, &previous)
+	if err != nil && !errors.Is(err, persist.ErrNotFound) {
+		return false, fmt.Errorf("%w %q: getting previous sample: %w", ErrSetMetric, name, err)
+	}
+
+	if err == nil && !v.Timestamp.After(previous.Timestamp) {
+		return false, nil
+	}
+
+	s.store.Set(key, sample{Value: value, Timestamp: v.Timestamp})
+
+	// As the SDK does, the first sample is reported as 0.
+	var difference float64
+	if err == nil {
+		difference = value - previous.Value
+		if difference < 0 && sourceType.IsPositive() {
+			return false, fmt.Errorf("%w %q: %w", ErrSetMetric, name, metric.ErrNegativeDiff)
+		}
+
+		if sourceType == metric.RATE || sourceType == metric.PRATE {
+			difference /= v.Timestamp.Sub(previous.Timestamp).Seconds()
+		}
+	}
+
+	return populateSingleMetric(ms, name, difference, metric.GAUGE)
+}
+
+// toFloat converts a value to float64 as the SDK does for numeric metrics.
+func toFloat(value definition.FetchedValue) (float64, error) {
+	if b, ok := value.(bool); ok {
+		if b {
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	f, err := strconv.ParseFloat(fmt.Sprintf("%v", value), 64)
+	if err != nil {
+		return 0, fmt.Errorf("non-numeric value %v: %w", value, err)
+	}
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, metric.ErrNonNumeric
+	}
+
+	return f, nil
+}
+
+func isDifference(sourceType metric.SourceType) bool {
+	switch sourceType {
+	case metric.RATE, metric.DELTA, metric.PRATE, metric.PDELTA:
+		return true
+	default:
+		return false
+	}
+}
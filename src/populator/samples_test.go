@@ -0,0 +1,128 @@
+package populator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/newrelic/infra-integrations-sdk/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/integration"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/logutil"
+	"github.com/newrelic/nri-kubernetes/v3/internal/storer"
+	"github.com/newrelic/nri-kubernetes/v3/src/definition"
+)
+
+func TestSampleSet(t *testing.T) {
+	t.Parallel()
+
+	start := time.Unix(1700000000, 0)
+	at := func(seconds int) time.Time {
+		return start.Add(time.Duration(seconds) * time.Second)
+	}
+
+	type sampled struct {
+		value     float64
+		timestamp time.Time
+		populated bool
+		expected  float64
+	}
+
+	testCases := map[string]struct {
+		sourceType metric.SourceType
+		samples    []sampled
+	}{
+		"rate_uses_time_between_samples": {
+			sourceType: metric.RATE,
+			samples: []sampled{
+				{value: 10, timestamp: at(0), populated: true, expected: 0},
+				{value: 30, timestamp: at(4), populated: true, expected: 5},
+			},
+		},
+		"delta_ignores_time_between_samples": {
+			sourceType: metric.DELTA,
+			samples: []sampled{
+				{value: 10, timestamp: at(0), populated: true, expected: 0},
+				{value: 30, timestamp: at(4), populated: true, expected: 20},
+			},
+		},
+		"stale_samples_are_not_reported": {
+			sourceType: metric.RATE,
+			samples: []sampled{
+				{value: 10, timestamp: at(0), populated: true, expected: 0},
+				{value: 10, timestamp: at(0), populated: false},
+				{value: 20, timestamp: at(5), populated: true, expected: 2},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			store := storer.NewInMemoryStore(storer.DefaultTTL, storer.DefaultInterval, logutil.Discard)
+			t.Cleanup(store.StopVacuum)
+
+			i, err := integration.New("test", "test")
+			require.NoError(t, err)
+			e, err := i.Entity("entity", "test")
+			require.NoError(t, err)
+
+			samples := newSampleSet(store, e, "TestSample")
+
+			for n, s := range tc.samples {
+				ms := e.NewMetricSet("TestSample")
+				v := definition.TimestampedValue{Value: s.value, Timestamp: s.timestamp}
+
+				populated, err := samples.populate(ms, "metric", v, tc.sourceType)
+				require.NoError(t, err)
+				assert.Equal(t, s.populated, populated, "sample #%d", n)
+
+				if s.populated {
+					assert.Equal(t, s.expected, ms.Metrics["metric"], "sample #%d", n)
+				} else {
+					assert.NotContains(t, ms.Metrics, "metric", "sample #%d", n)
+				}
+			}
+		})
+	}
+}
+
+func TestSampleSet_NegativeDifference(t *testing.T) {
+	t.Parallel()
+
+	store := storer.NewInMemoryStore(storer.DefaultTTL, storer.DefaultInterval, logutil.Discard)
+	t.Cleanup(store.StopVacuum)
+
+	i, err := integration.New("test", "test")
+	require.NoError(t, err)
+	e, err := i.Entity("entity", "test")
+	require.NoError(t, err)
+
+	samples := newSampleSet(store, e, "TestSample")
+	ms := e.NewMetricSet("TestSample")
+
+	_, err = samples.populate(ms, "metric", definition.TimestampedValue{Value: 10, Timestamp: time.Unix(10, 0)}, metric.PRATE)
+	require.NoError(t, err)
+
+	_, err = samples.populate(ms, "metric", definition.TimestampedValue{Value: 5, Timestamp: time.Unix(20, 0)}, metric.PRATE)
+	assert.ErrorIs(t, err, ErrSetMetric)
+	assert.ErrorIs(t, err, metric.ErrNegativeDiff)
+}
+
+func TestSampleSet_WithoutStore(t *testing.T) {
+	t.Parallel()
+
+	i, err := integration.New("test", "test")
+	require.NoError(t, err)
+	e, err := i.Entity("entity", "test")
+	require.NoError(t, err)
+
+	ms := e.NewMetricSet("TestSample")
+
+	populated, err := sampleSet{}.populate(ms, "metric", definition.TimestampedValue{Value: 3, Timestamp: time.Unix(10, 0)}, metric.GAUGE)
+	require.NoError(t, err)
+	assert.True(t, populated)
+	assert.Equal(t, float64(3), ms.Metrics["metric"])
+}
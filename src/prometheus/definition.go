@@ -5,8 +5,8 @@ import (
 	"fmt"
 	"math"
 	"sort"
-	"strconv"
 	"strings"
+	"time"
 
 	model "github.com/prometheus/client_model/go"
 
@@ -171,6 +171,17 @@ func FromLabelGetNamespace(metrics definition.RawMetrics) string {
 	return ""
 }
 
+// FromLabelGetPod returns the value of the pod label of the metrics.
+func FromLabelGetPod(metrics definition.RawMetrics) string {
+	for _, metric := range metrics {
+		m, ok := metric.(Metric)
+		if ok && m.Labels["pod"] != "" {
+			return m.Labels["pod"]
+		}
+	}
+	return ""
+}
+
 // GroupEntityMetricsBySpec groups metrics coming from Prometheus by the
 // given rawEntityID and metric spec.
 //
@@ -425,7 +436,7 @@ func fetchedValuesFromRawMetrics(
 		aggregatedValue, ok := val[attrName]
 
 		if !ok {
-			val[attrName] = metric.Value
+			val[attrName] = fetchedValue(metric.Value)
 			continue
 		}
 
@@ -439,6 +450,34 @@ func fetchedValuesFromRawMetrics(
 	return val, nil
 }
 
+// fetchedValue returns the value of a metric as fetched by specs. Stateset values are returned as plain strings, so
+// they can be populated as attributes.
+func fetchedValue(v Value) definition.FetchedValue {
+	if state, ok := v.(StateSetValue); ok {
+		return string(state)
+	}
+
+	return v
+}
+
+// sampleTimestamps returns the timestamp of the values fetchedValuesFromRawMetrics generates for metrics, for the ones
+// having it. Aggregated values get the latest timestamp of the metrics generating them.
+func sampleTimestamps(metricName, nameOverride string, metrics []Metric, labelsFilter ...LabelsFilter) map[string]time.Time {
+	timestamps := make(map[string]time.Time)
+	for _, metric := range metrics {
+		if metric.Timestamp.IsZero() {
+			continue
+		}
+
+		attrName := attributeName(metricName, nameOverride, metric.Labels, labelsFilter...)
+		if metric.Timestamp.After(timestamps[attrName]) {
+			timestamps[attrName] = metric.Timestamp
+		}
+	}
+
+	return timestamps
+}
+
 // FromValue creates a FetchFunc that fetches values from prometheus metrics values.
 func FromValue(metricName string, labelsFilter ...LabelsFilter) definition.FetchFunc {
 	return FromValueWithOverriddenName(metricName, "", labelsFilter...)
@@ -446,6 +485,7 @@ func FromValue(metricName string, labelsFilter ...LabelsFilter) definition.Fetch
 
 // FromValueWithOverriddenName creates a FetchFunc that fetches values from prometheus metrics values.
 // If there are multiple values returned, and nameOverride is not empty, this name will be used as a prefix instead of the metricName.
+// Values of samples exposing a timestamp are returned as definition.TimestampedValue.
 func FromValueWithOverriddenName(metricName string, nameOverride string, labelsFilter ...LabelsFilter) definition.FetchFunc {
 	return func(groupLabel, entityID string, groups definition.RawGroups) (definition.FetchedValue, error) {
 		value, err := definition.FromRaw(metricName)(groupLabel, entityID, groups)
@@ -455,9 +495,18 @@ func FromValueWithOverriddenName(metricName string, nameOverride string, labelsF
 
 		switch m := value.(type) {
 		case Metric:
-			return m.Value, nil
+			return definition.WithTimestamp(fetchedValue(m.Value), m.Timestamp), nil
 		case []Metric:
-			return fetchedValuesFromRawMetrics(metricName, nameOverride, m, labelsFilter...)
+			values, err := fetchedValuesFromRawMetrics(metricName, nameOverride, m, labelsFilter...)
+			if err != nil {
+				return nil, err
+			}
+
+			for name, timestamp := range sampleTimestamps(metricName, nameOverride, m, labelsFilter...) {
+				values[name] = definition.WithTimestamp(values[name], timestamp)
+			}
+
+			return values, nil
 		}
 		return nil, fmt.Errorf(
 			"incompatible metric type for %s. Expected: Metric or []Metric. Got: %T",
@@ -467,6 +516,71 @@ func FromValueWithOverriddenName(metricName string, nameOverride string, labelsF
 	}
 }
 
+// FromMatchingMetrics creates a FetchFunc that fetches the values of all the counter and gauge metrics of an entity
+// whose name is matched by matcher. It is meant to be used along with queries using the same MetricNameMatcher, so
+// specs can pick up families that are not known in advance.
+//
+// Values are returned under their metric name, with the labels of each time-series suffixed as in
+// FromValueWithOverriddenName when a metric has several of them.
+func FromMatchingMetrics(matcher *MetricNameMatcher, labelsFilter ...LabelsFilter) definition.FetchFunc {
+	return func(groupLabel, entityID string, groups definition.RawGroups) (definition.FetchedValue, error) {
+		group, ok := groups[groupLabel]
+		if !ok {
+			return nil, fmt.Errorf("group %q not found", groupLabel)
+		}
+
+		entity, ok := group[entityID]
+		if !ok {
+			return nil, fmt.Errorf("entity %q not found", entityID)
+		}
+
+		val := make(definition.FetchedValues)
+		for name, raw := range entity {
+			if !matcher.Matches(name) {
+				continue
+			}
+
+			var metrics []Metric
+			switch m := raw.(type) {
+			case Metric:
+				metrics = []Metric{m}
+			case []Metric:
+				metrics = m
+			default:
+				continue
+			}
+
+			numeric := make([]Metric, 0, len(metrics))
+			for _, m := range metrics {
+				switch m.Value.(type) {
+				case CounterValue, GaugeValue:
+					numeric = append(numeric, m)
+				}
+			}
+
+			if _, single := raw.(Metric); single && len(numeric) == 1 {
+				val[name] = numeric[0].Value
+				continue
+			}
+
+			values, err := fetchedValuesFromRawMetrics(name, "", numeric, labelsFilter...)
+			if err != nil {
+				return nil, err
+			}
+
+			for k, v := range values {
+				val[k] = v
+			}
+		}
+
+		if len(val) == 0 {
+			return nil, fmt.Errorf("no metrics matching for entity %q", entityID)
+		}
+
+		return val, nil
+	}
+}
+
 // FromLabelValue creates a FetchFunc that fetches a value from a Prometheus metric's label.
 //
 // It is a higher-order function that takes the source metric name (`key`) and the desired
@@ -628,19 +742,13 @@ func suffixLabelsInOrder(metricName string, labels Labels) string {
 	return metricName
 }
 
-// FromSummary creates a FetchFunc that fetches values from prometheus
-// histogram.
+// FromSummary creates a FetchFunc that fetches the summaries of a prometheus summary metric as
+// definition.Summaries, indexed by the given key suffixed with the time-series labels:
 //
-// It will create one attribute for the count, one for the sum and one per
-// quantile. The attributes names will be generated by suffixing the
-// time-series labels to the given key, and by suffixing and identifier
-// for type of the time-series in relation to the summary (count, sum or quantile).
+// - <metric_name>_<label_1>_<label_1_value>_..._<label_n>_<label_n_value>
 //
-// - <metric_name>_<label_1>_<label_1_value>_..._<label_n>_<label_n_value>_sum
-// - <metric_name>_<label_1>_<label_1_value>_..._<label_n>_<label_n_value>_count
-// - <metric_name>_<label_1>_<label_1_value>_..._<label_n>_<label_n_value>_quantile_<quantile_dimension_1>
-// - ...
-// - <metric_name>_<label_1>_<label_1_value>_..._<label_n>_<label_n_value>_quantile_<quantile_dimension_n>
+// Outputs not supporting summaries report them flattened into one attribute for the count, one for the sum and one
+// per quantile, as definition.Summaries.Flatten does.
 //
 // Since it expects the RawValue to be of type []Metric it should be
 // used when grouping with GroupEntityMetricsBySpec.
@@ -660,7 +768,7 @@ func FromSummary(key string) definition.FetchFunc {
 			)
 		}
 
-		val := make(definition.FetchedValues)
+		val := make(definition.Summaries)
 		for _, metric := range metrics {
 			summary, ok := metric.Value.(*model.Summary)
 			if !ok {
@@ -670,26 +778,121 @@ func FromSummary(key string) definition.FetchFunc {
 					metric.Value,
 				)
 			}
-			name := suffixLabelsInOrder(key, metric.Labels)
-			val[fmt.Sprintf("%s_count", name)] = summary.GetSampleCount()
 
-			sumVal := summary.GetSampleSum()
-			if validNRValue(sumVal) {
-				val[fmt.Sprintf("%s_sum", name)] = sumVal
+			s := definition.Summary{
+				SampleCount: summary.GetSampleCount(),
+				SampleSum:   summary.GetSampleSum(),
 			}
-
 			for _, q := range summary.GetQuantile() {
-				quantileVal := q.GetValue()
-				if validNRValue(quantileVal) {
-					nameWithQuantileSuffix := fmt.Sprintf(
-						"%s_quantile_%s",
-						name,
-						strconv.FormatFloat(q.GetQuantile(), 'f', -1, 64),
-					)
-					val[nameWithQuantileSuffix] = quantileVal
-				}
+				s.Quantiles = append(s.Quantiles, definition.SummaryQuantile{Quantile: q.GetQuantile(), Value: q.GetValue()})
+			}
+
+			val[suffixLabelsInOrder(key, metric.Labels)] = s
+		}
+		return val, nil
+	}
+}
+
+// FromHistogramQuantile creates a FetchFunc that estimates the given quantile, between 0 and 1, from a prometheus
+// histogram. Quantiles are estimated as the histogram_quantile PromQL function does.
+//
+// If the histogram has several time-series, one attribute is generated per time-series by suffixing its labels to
+// the nameOverride, or to the metricName if nameOverride is empty, in the same way FromValueWithOverriddenName does.
+// Time-series generating the same attribute name after applying labelsFilter are merged before estimating the
+// quantile.
+func FromHistogramQuantile(metricName, nameOverride string, quantile float64, labelsFilter ...LabelsFilter) definition.FetchFunc {
+	return fromHistogram(metricName, nameOverride, func(h HistogramValue) (float64, error) {
+		return h.Quantile(quantile)
+	}, labelsFilter...)
+}
+
+// FromHistogramAverage creates a FetchFunc that computes the mean of the observations of a prometheus histogram,
+// generating attribute names as FromHistogramQuantile does.
+func FromHistogramAverage(metricName, nameOverride string, labelsFilter ...LabelsFilter) definition.FetchFunc {
+	return fromHistogram(metricName, nameOverride, HistogramValue.Average, labelsFilter...)
+}
+
+// FromHistogramCount creates a FetchFunc that fetches the number of observations of a prometheus histogram,
+// generating attribute names as FromHistogramQuantile does.
+// Specs using it with the DELTA or RATE source types get the number of observations per interval.
+func FromHistogramCount(metricName, nameOverride string, labelsFilter ...LabelsFilter) definition.FetchFunc {
+	return fromHistogram(metricName, nameOverride, func(h HistogramValue) (float64, error) {
+		return h.SampleCount, nil
+	}, labelsFilter...)
+}
+
+// FromHistogramSum creates a FetchFunc that fetches the sum of the observations of a prometheus histogram,
+// generating attribute names as FromHistogramQuantile does.
+// Specs using it with the DELTA or RATE source types get the sum of the observations per interval.
+func FromHistogramSum(metricName, nameOverride string, labelsFilter ...LabelsFilter) definition.FetchFunc {
+	return fromHistogram(metricName, nameOverride, func(h HistogramValue) (float64, error) {
+		return h.SampleSum, nil
+	}, labelsFilter...)
+}
+
+// fromHistogram creates a FetchFunc that derives a value from each histogram of metricName using compute.
+// Values compute fails for, or which are not supported by New Relic, are skipped.
+func fromHistogram(
+	metricName string,
+	nameOverride string,
+	compute func(HistogramValue) (float64, error),
+	labelsFilter ...LabelsFilter,
+) definition.FetchFunc {
+	return func(groupLabel, entityID string, groups definition.RawGroups) (definition.FetchedValue, error) {
+		value, err := definition.FromRaw(metricName)(groupLabel, entityID, groups)
+		if err != nil {
+			return nil, err
+		}
+
+		var metrics []Metric
+		switch m := value.(type) {
+		case Metric:
+			h, ok := m.Value.(HistogramValue)
+			if !ok {
+				return nil, fmt.Errorf("incompatible metric type for %s. Expected: HistogramValue. Got: %T", metricName, m.Value)
+			}
+
+			v, err := compute(h)
+			if err != nil {
+				return nil, fmt.Errorf("computing value for %s: %w", metricName, err)
+			}
+
+			if !validNRValue(v) {
+				return nil, fmt.Errorf("computed value for %s is not valid: %v", metricName, v)
 			}
+
+			return v, nil
+		case []Metric:
+			metrics = m
+		default:
+			return nil, fmt.Errorf(
+				"incompatible metric type for %s. Expected: Metric or []Metric. Got: %T",
+				metricName,
+				value,
+			)
+		}
+
+		for _, metric := range metrics {
+			if _, ok := metric.Value.(HistogramValue); !ok {
+				return nil, fmt.Errorf("incompatible metric type for %s. Expected: HistogramValue. Got: %T", metricName, metric.Value)
+			}
+		}
+
+		histograms, err := fetchedValuesFromRawMetrics(metricName, nameOverride, metrics, labelsFilter...)
+		if err != nil {
+			return nil, err
 		}
+
+		val := make(definition.FetchedValues)
+		for name, h := range histograms {
+			v, err := compute(h.(HistogramValue))
+			if err != nil || !validNRValue(v) {
+				continue
+			}
+
+			val[name] = v
+		}
+
 		return val, nil
 	}
 }
@@ -851,6 +1054,21 @@ func getMetricValue(metricName string, aggregatedValue definition.FetchedValue,
 			)
 		}
 		value = aggregatedCounter + metric.Value.(GaugeValue)
+	case HistogramValue:
+		aggregatedHistogram, ok := aggregatedValue.(HistogramValue)
+		if !ok {
+			return nil, fmt.Errorf(
+				"incompatible metric type for %s aggregation. Expected: HistogramValue. Got: %T",
+				metricName,
+				metric.Value,
+			)
+		}
+
+		merged, err := aggregatedHistogram.merge(metric.Value.(HistogramValue))
+		if err != nil {
+			return nil, fmt.Errorf("aggregating %s: %w", metricName, err)
+		}
+		value = merged
 	}
 
 	return value, nil
@@ -4,8 +4,8 @@ package prometheus
 import (
 	"errors"
 	"fmt"
-	"math"
 	"testing"
+	"time"
 
 	model "github.com/prometheus/client_model/go"
 
@@ -757,7 +757,7 @@ func TestFetchFuncs_CorrectValue(t *testing.T) {
 	testCases := []struct {
 		name                 string
 		rawGroups            definition.RawGroups
-		expectedFetchedValue definition.FetchedValues
+		expectedFetchedValue definition.FetchedValue
 		fetchFunc            definition.FetchFunc
 	}{
 		{
@@ -945,58 +945,21 @@ func TestFetchFuncs_CorrectValue(t *testing.T) {
 				"scheduler_pending_pods": CounterValue(3),
 			},
 		},
-		{
-			name: "FromSummary correct values with NaN and Infinite discarded",
-			rawGroups: definition.RawGroups{
-				"scheduler": {
-					"kube-scheduler-minikube": {
-						"http_request_duration_microseconds": []Metric{
-							{
-								Labels: Labels{"l2": "v2", "l1": "v1", "handler": "prometheus"},
-								Value: &model.Summary{
-									SampleCount: uint64Ptr(5),
-									SampleSum:   float64Ptr(math.Inf(1)),
-									Quantile: []*model.Quantile{
-										{
-											Quantile: float64Ptr(0.5),
-											Value:    float64Ptr(math.NaN()),
-										},
-										{
-											Quantile: float64Ptr(0.9),
-											Value:    float64Ptr(math.NaN()),
-										},
-										{
-											Quantile: float64Ptr(0.99),
-											Value:    float64Ptr(44),
-										},
-									},
-								},
-							},
-						},
-					},
-				},
-			},
-			fetchFunc: FromSummary("http_request_duration_microseconds"),
-			expectedFetchedValue: definition.FetchedValues{
-				"http_request_duration_microseconds_handler_prometheus_l1_v1_l2_v2_count":         uint64(5),
-				"http_request_duration_microseconds_handler_prometheus_l1_v1_l2_v2_quantile_0.99": float64(44),
-			},
-		},
 		{
 			name:      "FromSummary correct value",
 			rawGroups: summaryRawGroups,
 			fetchFunc: FromSummary("http_request_duration_microseconds"),
-			expectedFetchedValue: definition.FetchedValues{
-				"http_request_duration_microseconds_handler_prometheus_l1_v1_l2_v2_count":         uint64(5),
-				"http_request_duration_microseconds_handler_prometheus_l1_v1_l2_v2_quantile_0.5":  float64(42),
-				"http_request_duration_microseconds_handler_prometheus_l1_v1_l2_v2_quantile_0.9":  float64(43),
-				"http_request_duration_microseconds_handler_prometheus_l1_v1_l2_v2_quantile_0.99": float64(44),
-				"http_request_duration_microseconds_handler_prometheus_l1_v1_l2_v2_sum":           float64(45),
-				"http_request_duration_microseconds_handler_other_l1_v1_l2_v2_count":              uint64(5),
-				"http_request_duration_microseconds_handler_other_l1_v1_l2_v2_quantile_0.5":       float64(42),
-				"http_request_duration_microseconds_handler_other_l1_v1_l2_v2_quantile_0.9":       float64(43),
-				"http_request_duration_microseconds_handler_other_l1_v1_l2_v2_quantile_0.99":      float64(44),
-				"http_request_duration_microseconds_handler_other_l1_v1_l2_v2_sum":                float64(45),
+			expectedFetchedValue: definition.Summaries{
+				"http_request_duration_microseconds_handler_prometheus_l1_v1_l2_v2": {
+					SampleCount: 5,
+					SampleSum:   45,
+					Quantiles:   []definition.SummaryQuantile{{Quantile: 0.5, Value: 42}, {Quantile: 0.9, Value: 43}, {Quantile: 0.99, Value: 44}},
+				},
+				"http_request_duration_microseconds_handler_other_l1_v1_l2_v2": {
+					SampleCount: 5,
+					SampleSum:   45,
+					Quantiles:   []definition.SummaryQuantile{{Quantile: 0.5, Value: 42}, {Quantile: 0.9, Value: 43}, {Quantile: 0.99, Value: 44}},
+				},
 			},
 		},
 	}
@@ -1147,6 +1110,42 @@ func TestFromRawValue_CorrectValue(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestFromRawValue_Timestamped(t *testing.T) {
+	ts := time.UnixMilli(1700000000123)
+	groups := definition.RawGroups{
+		"container": {
+			"kube-system_kube-proxy": definition.RawMetrics{
+				"container_cpu_usage_seconds_total": Metric{
+					Value:     CounterValue(10),
+					Labels:    Labels{"container": "kube-proxy"},
+					Timestamp: ts,
+				},
+			},
+		},
+	}
+
+	fetchedValue, err := FromValue("container_cpu_usage_seconds_total")("container", "kube-system_kube-proxy", groups)
+	assert.NoError(t, err)
+	assert.Equal(t, definition.TimestampedValue{Value: CounterValue(10), Timestamp: ts}, fetchedValue)
+}
+
+func TestFromRawValue_StateSet(t *testing.T) {
+	groups := definition.RawGroups{
+		"elasticsearch": {
+			"logs": definition.RawMetrics{
+				"kube_custom_elasticsearch_health_status": Metric{
+					Value:  StateSetValue("green"),
+					Labels: Labels{"name": "logs"},
+				},
+			},
+		},
+	}
+
+	fetchedValue, err := FromValue("kube_custom_elasticsearch_health_status")("elasticsearch", "logs", groups)
+	assert.NoError(t, err)
+	assert.Equal(t, "green", fetchedValue)
+}
+
 func TestFromRawValue_RawMetricNotFound(t *testing.T) {
 	fetchedValue, err := FromValue("foo")("pod", "fluentd-elasticsearch-jnqb7", rawGroups)
 	assert.Nil(t, fetchedValue)
@@ -3172,3 +3171,34 @@ func TestCountFromValueWithLabelsFilter_PropagatesNonMetricErrors(t *testing.T)
 		assert.Nil(t, result)
 	})
 }
+
+func TestFromMatchingMetrics(t *testing.T) {
+	t.Parallel()
+
+	raw := definition.RawGroups{
+		"deployment": {
+			"default_nginx": {
+				"kube_deployment_status_replicas": Metric{Value: GaugeValue(3)},
+				"kube_deployment_status_condition": []Metric{
+					{Labels: Labels{"condition": "Available"}, Value: GaugeValue(1)},
+					{Labels: Labels{"condition": "Progressing"}, Value: GaugeValue(0)},
+				},
+				"kube_deployment_created": Metric{Value: GaugeValue(1620000000)},
+			},
+		},
+	}
+
+	value, err := FromMatchingMetrics(MetricNamePrefix("kube_deployment_status_"))("deployment", "default_nginx", raw)
+	require.NoError(t, err)
+	assert.Equal(t, definition.FetchedValues{
+		"kube_deployment_status_replicas":                        GaugeValue(3),
+		"kube_deployment_status_condition_condition_Available":   GaugeValue(1),
+		"kube_deployment_status_condition_condition_Progressing": GaugeValue(0),
+	}, value)
+
+	_, err = FromMatchingMetrics(MetricNamePrefix("kube_pod_"))("deployment", "default_nginx", raw)
+	assert.Error(t, err)
+
+	_, err = FromMatchingMetrics(MetricNamePrefix("kube_deployment_"))("deployment", "default_other", raw)
+	assert.Error(t, err)
+}
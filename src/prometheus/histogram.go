@@ -0,0 +1,162 @@
+package prometheus
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	model "github.com/prometheus/client_model/go"
+)
+
+var (
+	// ErrInvalidQuantile is returned when asking for a quantile outside the [0, 1] range.
+	ErrInvalidQuantile = errors.New("quantile must be between 0 and 1")
+	// ErrEmptyHistogram is returned when a value cannot be derived from a histogram because it has no observations.
+	ErrEmptyHistogram = errors.New("histogram has no observations")
+	// ErrIncompatibleBuckets is returned when merging histograms with different bucket boundaries.
+	ErrIncompatibleBuckets = errors.New("histograms have different buckets")
+)
+
+// HistogramBucket is a bucket of a histogram. Counts are cumulative, as in the Prometheus exposition formats.
+type HistogramBucket struct {
+	UpperBound      float64
+	CumulativeCount float64
+}
+
+// HistogramValue represents the value of a histogram type metric.
+// Buckets are sorted by upper bound, and the last one always has an upper bound of +Inf.
+type HistogramValue struct {
+	SampleCount float64
+	SampleSum   float64
+	Buckets     []HistogramBucket
+}
+
+// String implements the Stringer interface method.
+func (v HistogramValue) String() string {
+	buckets := make([]string, 0, len(v.Buckets))
+	for _, b := range v.Buckets {
+		buckets = append(buckets, fmt.Sprintf(
+			"%s:%s",
+			strconv.FormatFloat(b.UpperBound, 'f', -1, 64),
+			strconv.FormatFloat(b.CumulativeCount, 'f', -1, 64),
+		))
+	}
+
+	return fmt.Sprintf(
+		"count:%s sum:%s buckets:[%s]",
+		strconv.FormatFloat(v.SampleCount, 'f', -1, 64),
+		strconv.FormatFloat(v.SampleSum, 'f', -1, 64),
+		strings.Join(buckets, " "),
+	)
+}
+
+// Average returns the mean of the observations of the histogram.
+func (v HistogramValue) Average() (float64, error) {
+	if v.SampleCount == 0 {
+		return 0, ErrEmptyHistogram
+	}
+
+	return v.SampleSum / v.SampleCount, nil
+}
+
+// Quantile estimates the q-quantile of the observations of the histogram, assuming a linear distribution within
+// each bucket. It follows the same algorithm as the histogram_quantile PromQL function, so the upper bound of the
+// highest finite bucket is returned if the quantile falls in the +Inf bucket.
+func (v HistogramValue) Quantile(q float64) (float64, error) {
+	if q < 0 || q > 1 || math.IsNaN(q) {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidQuantile, q)
+	}
+
+	if len(v.Buckets) < 2 {
+		return 0, fmt.Errorf("at least two buckets are needed to estimate quantiles, got %d", len(v.Buckets))
+	}
+
+	observations := v.Buckets[len(v.Buckets)-1].CumulativeCount
+	if observations == 0 {
+		return 0, ErrEmptyHistogram
+	}
+
+	rank := q * observations
+	b := sort.Search(len(v.Buckets)-1, func(i int) bool { return v.Buckets[i].CumulativeCount >= rank })
+
+	if b == len(v.Buckets)-1 {
+		return v.Buckets[len(v.Buckets)-2].UpperBound, nil
+	}
+
+	if b == 0 && v.Buckets[0].UpperBound <= 0 {
+		return v.Buckets[0].UpperBound, nil
+	}
+
+	bucketStart := 0.0
+	bucketEnd := v.Buckets[b].UpperBound
+	count := v.Buckets[b].CumulativeCount
+	if b > 0 {
+		bucketStart = v.Buckets[b-1].UpperBound
+		count -= v.Buckets[b-1].CumulativeCount
+		rank -= v.Buckets[b-1].CumulativeCount
+	}
+
+	return bucketStart + (bucketEnd-bucketStart)*(rank/count), nil
+}
+
+// merge returns the sum of two histograms with the same buckets.
+func (v HistogramValue) merge(other HistogramValue) (HistogramValue, error) {
+	if len(v.Buckets) != len(other.Buckets) {
+		return HistogramValue{}, ErrIncompatibleBuckets
+	}
+
+	merged := HistogramValue{
+		SampleCount: v.SampleCount + other.SampleCount,
+		SampleSum:   v.SampleSum + other.SampleSum,
+		Buckets:     make([]HistogramBucket, len(v.Buckets)),
+	}
+
+	for i := range v.Buckets {
+		if v.Buckets[i].UpperBound != other.Buckets[i].UpperBound {
+			return HistogramValue{}, ErrIncompatibleBuckets
+		}
+
+		merged.Buckets[i] = HistogramBucket{
+			UpperBound:      v.Buckets[i].UpperBound,
+			CumulativeCount: v.Buckets[i].CumulativeCount + other.Buckets[i].CumulativeCount,
+		}
+	}
+
+	return merged, nil
+}
+
+// histogramFromPrometheus converts a Prometheus histogram into a HistogramValue. Integer and float counts are both
+// supported, and a +Inf bucket holding the sample count is added if the histogram does not expose it, which is the
+// case for the protobuf format.
+func histogramFromPrometheus(h *model.Histogram) HistogramValue {
+	sampleCount := float64(h.GetSampleCount())
+	if h.SampleCountFloat != nil {
+		sampleCount = h.GetSampleCountFloat()
+	}
+
+	value := HistogramValue{
+		SampleCount: sampleCount,
+		SampleSum:   h.GetSampleSum(),
+		Buckets:     make([]HistogramBucket, 0, len(h.GetBucket())+1),
+	}
+
+	for _, b := range h.GetBucket() {
+		count := float64(b.GetCumulativeCount())
+		if b.CumulativeCountFloat != nil {
+			count = b.GetCumulativeCountFloat()
+		}
+
+		value.Buckets = append(value.Buckets, HistogramBucket{UpperBound: b.GetUpperBound(), CumulativeCount: count})
+	}
+
+	sort.Slice(value.Buckets, func(i, j int) bool { return value.Buckets[i].UpperBound < value.Buckets[j].UpperBound })
+
+	if len(value.Buckets) == 0 || !math.IsInf(value.Buckets[len(value.Buckets)-1].UpperBound, 1) {
+		value.Buckets = append(value.Buckets, HistogramBucket{UpperBound: math.Inf(1), CumulativeCount: sampleCount})
+	}
+
+	return value
+}
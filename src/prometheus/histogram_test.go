@@ -0,0 +1,217 @@
+package prometheus
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/logutil"
+	"github.com/newrelic/nri-kubernetes/v3/src/definition"
+)
+
+const histogramPayload = `# HELP apiserver_request_duration_seconds Response latency distribution in seconds.
+# TYPE apiserver_request_duration_seconds histogram
+apiserver_request_duration_seconds_bucket{verb="GET",le="0.1"} 50
+apiserver_request_duration_seconds_bucket{verb="GET",le="0.5"} 90
+apiserver_request_duration_seconds_bucket{verb="GET",le="1"} 100
+apiserver_request_duration_seconds_bucket{verb="GET",le="+Inf"} 100
+apiserver_request_duration_seconds_sum{verb="GET"} 20
+apiserver_request_duration_seconds_count{verb="GET"} 100
+apiserver_request_duration_seconds_bucket{verb="LIST",le="0.1"} 0
+apiserver_request_duration_seconds_bucket{verb="LIST",le="0.5"} 0
+apiserver_request_duration_seconds_bucket{verb="LIST",le="1"} 0
+apiserver_request_duration_seconds_bucket{verb="LIST",le="+Inf"} 0
+apiserver_request_duration_seconds_sum{verb="LIST"} 0
+apiserver_request_duration_seconds_count{verb="LIST"} 0
+`
+
+func testHistogram() HistogramValue {
+	return HistogramValue{
+		SampleCount: 100,
+		SampleSum:   20,
+		Buckets: []HistogramBucket{
+			{UpperBound: 0.1, CumulativeCount: 50},
+			{UpperBound: 0.5, CumulativeCount: 90},
+			{UpperBound: 1, CumulativeCount: 100},
+			{UpperBound: math.Inf(1), CumulativeCount: 100},
+		},
+	}
+}
+
+func TestHistogramValue_Quantile(t *testing.T) {
+	t.Parallel()
+
+	h := testHistogram()
+
+	for _, tc := range []struct {
+		quantile float64
+		expected float64
+	}{
+		{quantile: 0, expected: 0},
+		{quantile: 0.25, expected: 0.05},
+		{quantile: 0.5, expected: 0.1},
+		{quantile: 0.7, expected: 0.3},
+		{quantile: 0.95, expected: 0.75},
+		{quantile: 1, expected: 1},
+	} {
+		v, err := h.Quantile(tc.quantile)
+		require.NoError(t, err)
+		assert.InDelta(t, tc.expected, v, 1e-9, "quantile %v", tc.quantile)
+	}
+}
+
+func TestHistogramValue_Quantile_in_inf_bucket_returns_highest_finite_bound(t *testing.T) {
+	t.Parallel()
+
+	h := testHistogram()
+	h.Buckets[3].CumulativeCount = 200
+
+	v, err := h.Quantile(0.99)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, v)
+}
+
+func TestHistogramValue_Quantile_fails(t *testing.T) {
+	t.Parallel()
+
+	_, err := testHistogram().Quantile(1.5)
+	assert.ErrorIs(t, err, ErrInvalidQuantile)
+
+	_, err = HistogramValue{Buckets: []HistogramBucket{{UpperBound: 1}, {UpperBound: math.Inf(1)}}}.Quantile(0.5)
+	assert.ErrorIs(t, err, ErrEmptyHistogram)
+
+	_, err = HistogramValue{Buckets: []HistogramBucket{{UpperBound: math.Inf(1), CumulativeCount: 1}}}.Quantile(0.5)
+	assert.Error(t, err)
+}
+
+func TestHistogramValue_Average(t *testing.T) {
+	t.Parallel()
+
+	v, err := testHistogram().Average()
+	require.NoError(t, err)
+	assert.Equal(t, 0.2, v)
+
+	_, err = HistogramValue{}.Average()
+	assert.ErrorIs(t, err, ErrEmptyHistogram)
+}
+
+func TestHistogramValue_merge(t *testing.T) {
+	t.Parallel()
+
+	merged, err := testHistogram().merge(testHistogram())
+	require.NoError(t, err)
+	assert.Equal(t, 200.0, merged.SampleCount)
+	assert.Equal(t, 40.0, merged.SampleSum)
+	assert.Equal(t, HistogramBucket{UpperBound: 0.5, CumulativeCount: 180}, merged.Buckets[1])
+
+	other := testHistogram()
+	other.Buckets[0].UpperBound = 0.2
+	_, err = testHistogram().merge(other)
+	assert.ErrorIs(t, err, ErrIncompatibleBuckets)
+}
+
+func TestGetFilteredMetricFamilies_parses_histograms(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, histogramPayload)
+	}))
+	defer server.Close()
+
+	families, err := GetFilteredMetricFamilies(context.Background(), server.Client(), server.URL, []Query{
+		{MetricName: "apiserver_request_duration_seconds"},
+	}, logutil.Discard)
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	require.Len(t, families[0].Metrics, 2)
+
+	assert.Equal(t, "HISTOGRAM", families[0].Type)
+	assert.Equal(t, testHistogram(), families[0].Metrics[0].Value)
+}
+
+func TestFromHistogram(t *testing.T) {
+	t.Parallel()
+
+	empty := testHistogram()
+	empty.SampleCount, empty.SampleSum = 0, 0
+	for i := range empty.Buckets {
+		empty.Buckets[i].CumulativeCount = 0
+	}
+
+	raw := definition.RawGroups{
+		"apiserver": {
+			"apiserver": {
+				"apiserver_request_duration_seconds": []Metric{
+					{Labels: Labels{"verb": "GET", "code": "200"}, Value: testHistogram()},
+					{Labels: Labels{"verb": "GET", "code": "500"}, Value: testHistogram()},
+					{Labels: Labels{"verb": "LIST", "code": "200"}, Value: empty},
+				},
+			},
+		},
+	}
+
+	t.Run("quantile_merging_series_with_the_same_attribute", func(t *testing.T) {
+		t.Parallel()
+
+		value, err := FromHistogramQuantile(
+			"apiserver_request_duration_seconds", "requestDurationP95", 0.95, IncludeOnlyLabelsFilter("verb"),
+		)("apiserver", "apiserver", raw)
+		require.NoError(t, err)
+		assert.Equal(t, definition.FetchedValues{"requestDurationP95_verb_GET": 0.75}, value)
+	})
+
+	t.Run("average", func(t *testing.T) {
+		t.Parallel()
+
+		value, err := FromHistogramAverage(
+			"apiserver_request_duration_seconds", "requestDurationAverage", IncludeOnlyLabelsFilter("verb"),
+		)("apiserver", "apiserver", raw)
+		require.NoError(t, err)
+		assert.Equal(t, definition.FetchedValues{"requestDurationAverage_verb_GET": 0.2}, value)
+	})
+
+	t.Run("count_and_sum", func(t *testing.T) {
+		t.Parallel()
+
+		count, err := FromHistogramCount(
+			"apiserver_request_duration_seconds", "requestCount", IncludeOnlyLabelsFilter("verb"),
+		)("apiserver", "apiserver", raw)
+		require.NoError(t, err)
+		assert.Equal(t, definition.FetchedValues{"requestCount_verb_GET": 200.0, "requestCount_verb_LIST": 0.0}, count)
+
+		sum, err := FromHistogramSum(
+			"apiserver_request_duration_seconds", "requestDurationSum", IncludeOnlyLabelsFilter("verb"),
+		)("apiserver", "apiserver", raw)
+		require.NoError(t, err)
+		assert.Equal(t, definition.FetchedValues{"requestDurationSum_verb_GET": 40.0, "requestDurationSum_verb_LIST": 0.0}, sum)
+	})
+
+	t.Run("single_metric", func(t *testing.T) {
+		t.Parallel()
+
+		single := definition.RawGroups{
+			"apiserver": {"apiserver": {"apiserver_request_duration_seconds": Metric{Value: testHistogram()}}},
+		}
+
+		value, err := FromHistogramQuantile("apiserver_request_duration_seconds", "", 0.5)("apiserver", "apiserver", single)
+		require.NoError(t, err)
+		assert.InDelta(t, 0.1, value, 1e-9)
+	})
+
+	t.Run("incompatible_type", func(t *testing.T) {
+		t.Parallel()
+
+		gauge := definition.RawGroups{
+			"apiserver": {"apiserver": {"apiserver_request_duration_seconds": Metric{Value: GaugeValue(1)}}},
+		}
+
+		_, err := FromHistogramAverage("apiserver_request_duration_seconds", "")("apiserver", "apiserver", gauge)
+		assert.Error(t, err)
+	})
+}
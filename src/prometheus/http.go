@@ -1,15 +1,21 @@
 package prometheus
 
 import (
+	"context"
 	"net/http"
 )
 
-// AcceptHeader starting with ksm 1.5 only plain text encoding is supported.
-const AcceptHeader = `text/plain`
+// AcceptHeader negotiates the delimited protobuf format first, as it is the cheapest to parse, then OpenMetrics, and
+// finally the text format, which is the only one supported by some exporters like ksm since 1.5.
+const AcceptHeader = `application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited;q=0.7,` +
+	`application/openmetrics-text;version=1.0.0;q=0.5,` +
+	`text/plain;version=0.0.4;q=0.3,` +
+	`*/*;q=0.1`
 
-// NewRequest returns a new Request given a method, URL, setting the required header for accepting protobuf.
-func NewRequest(url string) (*http.Request, error) {
-	r, err := http.NewRequest(http.MethodGet, url, nil)
+// NewRequest returns a new Request given a method, URL, setting the required header for content negotiation.
+// The request is bound to ctx, so cancelling it aborts the request.
+func NewRequest(ctx context.Context, url string) (*http.Request, error) {
+	r, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package prometheus
 
 import (
+	"context"
 	"net/http"
 	"testing"
 
@@ -9,7 +10,7 @@ import (
 )
 
 func TestNewRequest(t *testing.T) {
-	r, err := NewRequest("http://example.com")
+	r, err := NewRequest(context.Background(), "http://example.com")
 	require.NoError(t, err)
 
 	assert.Equal(t, AcceptHeader, r.Header.Get("Accept"))
@@ -0,0 +1,169 @@
+package prometheus
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	model "github.com/prometheus/client_model/go"
+)
+
+// ErrInvalidMatcher is returned when a LabelMatcher cannot be built from the given arguments.
+var ErrInvalidMatcher = errors.New("invalid label matcher")
+
+// LabelMatchType indicates how a LabelMatcher compares the value of a label.
+type LabelMatchType int
+
+const (
+	// LabelEqual matches when the label value is equal to the given one.
+	LabelEqual LabelMatchType = iota
+	// LabelNotEqual matches when the label value is not equal to the given one.
+	LabelNotEqual
+	// LabelRegexp matches when the label value matches the given regular expression, which is anchored at both ends.
+	LabelRegexp
+	// LabelNotRegexp matches when the label value does not match the given regular expression.
+	LabelNotRegexp
+	// LabelIn matches when the label value is one of the given ones.
+	LabelIn
+	// LabelNotIn matches when the label value is none of the given ones.
+	LabelNotIn
+	// LabelPresent matches when the label is set to a non-empty value.
+	LabelPresent
+	// LabelAbsent matches when the label is not set, or set to an empty value.
+	LabelAbsent
+)
+
+// LabelMatcher matches the value of a single label of a metric. As in PromQL, a label that is not set is considered
+// to have an empty value, so `LabelNotEqual` with an empty value is equivalent to `LabelPresent`.
+// LabelMatchers must be built with NewLabelMatcher or MustNewLabelMatcher.
+type LabelMatcher struct {
+	matchType LabelMatchType
+	name      string
+	values    map[string]bool
+	re        *regexp.Regexp
+}
+
+// NewLabelMatcher returns a LabelMatcher for the named label. LabelEqual, LabelNotEqual, LabelRegexp and
+// LabelNotRegexp take exactly one value, LabelIn and LabelNotIn at least one, and LabelPresent and LabelAbsent none.
+func NewLabelMatcher(matchType LabelMatchType, name string, values ...string) (LabelMatcher, error) {
+	m := LabelMatcher{matchType: matchType, name: name}
+
+	if name == "" {
+		return m, fmt.Errorf("%w: label name cannot be empty", ErrInvalidMatcher)
+	}
+
+	switch matchType {
+	case LabelEqual, LabelNotEqual, LabelRegexp, LabelNotRegexp:
+		if len(values) != 1 {
+			return m, fmt.Errorf("%w: expected one value for label %q, got %d", ErrInvalidMatcher, name, len(values))
+		}
+	case LabelIn, LabelNotIn:
+		if len(values) == 0 {
+			return m, fmt.Errorf("%w: expected at least one value for label %q", ErrInvalidMatcher, name)
+		}
+	case LabelPresent, LabelAbsent:
+		if len(values) != 0 {
+			return m, fmt.Errorf("%w: expected no values for label %q, got %d", ErrInvalidMatcher, name, len(values))
+		}
+	default:
+		return m, fmt.Errorf("%w: unknown match type %d", ErrInvalidMatcher, matchType)
+	}
+
+	if matchType == LabelRegexp || matchType == LabelNotRegexp {
+		re, err := regexp.Compile("^(?:" + values[0] + ")$")
+		if err != nil {
+			return m, fmt.Errorf("%w: compiling expression for label %q: %w", ErrInvalidMatcher, name, err)
+		}
+		m.re = re
+
+		return m, nil
+	}
+
+	m.values = make(map[string]bool, len(values))
+	for _, v := range values {
+		m.values[v] = true
+	}
+
+	return m, nil
+}
+
+// MustNewLabelMatcher is like NewLabelMatcher but panics if the matcher cannot be built. It simplifies the
+// initialization of global query definitions.
+func MustNewLabelMatcher(matchType LabelMatchType, name string, values ...string) LabelMatcher {
+	m, err := NewLabelMatcher(matchType, name, values...)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// Matches returns whether the given Prometheus label pairs satisfy the matcher.
+func (m LabelMatcher) Matches(pairs []*model.LabelPair) bool {
+	var value string
+	for _, p := range pairs {
+		if p.GetName() == m.name {
+			value = p.GetValue()
+			break
+		}
+	}
+
+	switch m.matchType {
+	case LabelEqual, LabelIn:
+		return m.values[value]
+	case LabelNotEqual, LabelNotIn:
+		return !m.values[value]
+	case LabelRegexp:
+		return m.re.MatchString(value)
+	case LabelNotRegexp:
+		return !m.re.MatchString(value)
+	case LabelPresent:
+		return value != ""
+	case LabelAbsent:
+		return value == ""
+	default:
+		return false
+	}
+}
+
+// MetricNameMatcher matches the names of metric families, either by prefix or by regular expression.
+// MetricNameMatchers must be built with MetricNamePrefix, NewMetricNameRegexp or MustNewMetricNameRegexp.
+type MetricNameMatcher struct {
+	prefix string
+	re     *regexp.Regexp
+}
+
+// MetricNamePrefix returns a MetricNameMatcher matching the names starting with prefix.
+func MetricNamePrefix(prefix string) *MetricNameMatcher {
+	return &MetricNameMatcher{prefix: prefix}
+}
+
+// NewMetricNameRegexp returns a MetricNameMatcher matching the names matching expr, which is anchored at both ends.
+func NewMetricNameRegexp(expr string) (*MetricNameMatcher, error) {
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("%w: compiling metric name expression: %w", ErrInvalidMatcher, err)
+	}
+
+	return &MetricNameMatcher{re: re}, nil
+}
+
+// MustNewMetricNameRegexp is like NewMetricNameRegexp but panics if the expression cannot be compiled.
+func MustNewMetricNameRegexp(expr string) *MetricNameMatcher {
+	m, err := NewMetricNameRegexp(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
+
+// Matches returns whether the metric name is matched.
+func (m *MetricNameMatcher) Matches(name string) bool {
+	if m.re != nil {
+		return m.re.MatchString(name)
+	}
+
+	return strings.HasPrefix(name, m.prefix)
+}
@@ -0,0 +1,198 @@
+package prometheus
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	model "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/logutil"
+)
+
+func labelPairs(labels Labels) []*model.LabelPair {
+	pairs := make([]*model.LabelPair, 0, len(labels))
+	for name, value := range labels {
+		pairs = append(pairs, &model.LabelPair{Name: proto.String(name), Value: proto.String(value)})
+	}
+
+	return pairs
+}
+
+func TestLabelMatcher_Matches(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		matcher  LabelMatcher
+		labels   Labels
+		expected bool
+	}{
+		"equal":                        {MustNewLabelMatcher(LabelEqual, "container", "nginx"), Labels{"container": "nginx"}, true},
+		"equal_fails":                  {MustNewLabelMatcher(LabelEqual, "container", "nginx"), Labels{"container": "POD"}, false},
+		"equal_empty_matches_missing":  {MustNewLabelMatcher(LabelEqual, "container", ""), Labels{"pod": "nginx"}, true},
+		"not_equal":                    {MustNewLabelMatcher(LabelNotEqual, "container", "POD"), Labels{"container": "nginx"}, true},
+		"not_equal_fails":              {MustNewLabelMatcher(LabelNotEqual, "container", "POD"), Labels{"container": "POD"}, false},
+		"not_equal_matches_missing":    {MustNewLabelMatcher(LabelNotEqual, "container", "POD"), Labels{}, true},
+		"regexp":                       {MustNewLabelMatcher(LabelRegexp, "resource", "cpu|memory"), Labels{"resource": "memory"}, true},
+		"regexp_is_anchored":           {MustNewLabelMatcher(LabelRegexp, "resource", "cpu"), Labels{"resource": "cpu_shares"}, false},
+		"not_regexp":                   {MustNewLabelMatcher(LabelNotRegexp, "resource", "nvidia.*"), Labels{"resource": "cpu"}, true},
+		"not_regexp_fails":             {MustNewLabelMatcher(LabelNotRegexp, "resource", "nvidia.*"), Labels{"resource": "nvidia_gpu"}, false},
+		"in":                           {MustNewLabelMatcher(LabelIn, "phase", "Pending", "Running"), Labels{"phase": "Running"}, true},
+		"in_fails":                     {MustNewLabelMatcher(LabelIn, "phase", "Pending", "Running"), Labels{"phase": "Failed"}, false},
+		"not_in":                       {MustNewLabelMatcher(LabelNotIn, "phase", "Pending", "Running"), Labels{"phase": "Failed"}, true},
+		"not_in_fails":                 {MustNewLabelMatcher(LabelNotIn, "phase", "Pending", "Running"), Labels{"phase": "Pending"}, false},
+		"present":                      {MustNewLabelMatcher(LabelPresent, "container"), Labels{"container": "nginx"}, true},
+		"present_fails_on_empty_value": {MustNewLabelMatcher(LabelPresent, "container"), Labels{"container": ""}, false},
+		"absent":                       {MustNewLabelMatcher(LabelAbsent, "container"), Labels{"pod": "nginx"}, true},
+		"absent_fails":                 {MustNewLabelMatcher(LabelAbsent, "container"), Labels{"container": "nginx"}, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, tc.matcher.Matches(labelPairs(tc.labels)))
+		})
+	}
+}
+
+func TestNewLabelMatcher_fails(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		matchType LabelMatchType
+		label     string
+		values    []string
+	}{
+		"empty_name":             {LabelEqual, "", []string{"a"}},
+		"equal_without_value":    {LabelEqual, "container", nil},
+		"regexp_with_two_values": {LabelRegexp, "container", []string{"a", "b"}},
+		"invalid_regexp":         {LabelRegexp, "container", []string{"("}},
+		"in_without_values":      {LabelIn, "container", nil},
+		"present_with_value":     {LabelPresent, "container", []string{"a"}},
+		"unknown_type":           {LabelMatchType(42), "container", nil},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewLabelMatcher(tc.matchType, tc.label, tc.values...)
+			assert.ErrorIs(t, err, ErrInvalidMatcher)
+		})
+	}
+
+	assert.Panics(t, func() { MustNewLabelMatcher(LabelRegexp, "container", "(") })
+}
+
+func TestQueryMatch_Matchers(t *testing.T) {
+	t.Parallel()
+
+	q := Query{
+		MetricName: "container_memory_usage_bytes",
+		Labels: QueryLabels{
+			Matchers: []LabelMatcher{
+				MustNewLabelMatcher(LabelPresent, "container"),
+				MustNewLabelMatcher(LabelNotEqual, "container", "POD"),
+				MustNewLabelMatcher(LabelRegexp, "namespace", "kube-.*"),
+			},
+		},
+	}
+
+	metricType := model.MetricType_GAUGE
+	family := &model.MetricFamily{
+		Name: proto.String(q.MetricName),
+		Type: &metricType,
+		Metric: []*model.Metric{
+			{Gauge: &model.Gauge{Value: proto.Float64(1)}, Label: labelPairs(Labels{"container": "dns", "namespace": "kube-system"})},
+			{Gauge: &model.Gauge{Value: proto.Float64(2)}, Label: labelPairs(Labels{"container": "POD", "namespace": "kube-system"})},
+			{Gauge: &model.Gauge{Value: proto.Float64(3)}, Label: labelPairs(Labels{"namespace": "kube-system"})},
+			{Gauge: &model.Gauge{Value: proto.Float64(4)}, Label: labelPairs(Labels{"container": "nginx", "namespace": "default"})},
+		},
+	}
+
+	result := q.Execute(family)
+	require.Len(t, result.Metrics, 1)
+	assert.Equal(t, GaugeValue(1), result.Metrics[0].Value)
+}
+
+func TestMetricNameMatcher(t *testing.T) {
+	t.Parallel()
+
+	prefix := MetricNamePrefix("kube_deployment_status_")
+	assert.True(t, prefix.Matches("kube_deployment_status_replicas"))
+	assert.False(t, prefix.Matches("kube_deployment_created"))
+
+	re := MustNewMetricNameRegexp("kube_(deployment|daemonset)_status_.*")
+	assert.True(t, re.Matches("kube_daemonset_status_number_ready"))
+	assert.False(t, re.Matches("kube_statefulset_status_replicas"))
+	assert.False(t, re.Matches("prefixed_kube_deployment_status_replicas"))
+
+	_, err := NewMetricNameRegexp("(")
+	assert.ErrorIs(t, err, ErrInvalidMatcher)
+}
+
+func TestGetFilteredMetricFamilies_with_metric_name_matcher(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `# TYPE kube_deployment_status_replicas gauge
+kube_deployment_status_replicas{namespace="default",deployment="nginx"} 3
+# TYPE kube_deployment_status_replicas_available gauge
+kube_deployment_status_replicas_available{namespace="default",deployment="nginx"} 2
+# TYPE kube_deployment_created gauge
+kube_deployment_created{namespace="default",deployment="nginx"} 1620000000
+`)
+	}))
+	defer server.Close()
+
+	families, err := GetFilteredMetricFamilies(context.Background(), server.Client(), server.URL, []Query{
+		{CustomName: "ignored", MetricNameMatcher: MetricNamePrefix("kube_deployment_status_")},
+	}, logutil.Discard)
+	require.NoError(t, err)
+
+	names := make([]string, 0, len(families))
+	for _, f := range families {
+		names = append(names, f.Name)
+	}
+	assert.ElementsMatch(t, []string{"kube_deployment_status_replicas", "kube_deployment_status_replicas_available"}, names)
+}
+
+func TestGetFilteredMetricFamilies_emits_each_family_once(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `# TYPE kube_deployment_status_replicas gauge
+kube_deployment_status_replicas{namespace="default",deployment="nginx"} 3
+kube_deployment_status_replicas{namespace="default",deployment="redis"} 1
+# TYPE kube_deployment_status_replicas_available gauge
+kube_deployment_status_replicas_available{namespace="default",deployment="nginx"} 2
+`)
+	}))
+	defer server.Close()
+
+	families, err := GetFilteredMetricFamilies(context.Background(), server.Client(), server.URL, []Query{
+		{MetricNameMatcher: MetricNamePrefix("kube_deployment_")},
+		{MetricNameMatcher: MustNewMetricNameRegexp("kube_deployment_status_.*")},
+		{
+			MetricName: "kube_deployment_status_replicas",
+			Labels:     QueryLabels{Labels: Labels{"deployment": "nginx"}},
+		},
+	}, logutil.Discard)
+	require.NoError(t, err)
+
+	byName := map[string][]MetricFamily{}
+	for _, f := range families {
+		byName[f.Name] = append(byName[f.Name], f)
+	}
+
+	require.Len(t, byName, 2)
+	require.Len(t, byName["kube_deployment_status_replicas"], 1)
+	require.Len(t, byName["kube_deployment_status_replicas_available"], 1)
+
+	// The query for the exact name takes precedence over the matchers, even if it comes after them.
+	replicas := byName["kube_deployment_status_replicas"][0]
+	require.Len(t, replicas.Metrics, 1)
+	assert.Equal(t, "nginx", replicas.Metrics[0].Labels["deployment"])
+}
@@ -3,6 +3,7 @@ package prometheus
 import (
 	"fmt"
 	"strconv"
+	"time"
 
 	model "github.com/prometheus/client_model/go"
 )
@@ -51,8 +52,19 @@ func (l Labels) Has(name string) bool {
 type Metric struct {
 	Labels Labels
 	Value  Value
+	// Timestamp is the time the sample was taken at, if exposed by the endpoint. It is zero otherwise.
+	Timestamp time.Time
 }
 
+// Types of the families decoded from the OpenMetrics info and stateset types, which have no equivalent in the
+// Prometheus data model. The Type of other families is the name of their Prometheus type, like GAUGE.
+const (
+	// InfoType families hold the labels of an info metric. Their value is always 1.
+	InfoType = "INFO"
+	// StateSetType families hold a StateSetValue for each label set.
+	StateSetType = "STATESET"
+)
+
 // MetricFamily is an aggregation of metrics with same name.
 type MetricFamily struct {
 	Name    string
@@ -80,3 +92,12 @@ type GaugeValue float64
 func (v GaugeValue) String() string {
 	return strconv.FormatFloat(float64(v), 'f', -1, 64)
 }
+
+// StateSetValue is the value of a stateset metric: the name of its active state, or the sorted and comma-separated
+// names of all of them if several states are active at once.
+type StateSetValue string
+
+// String implements the Stringer interface method.
+func (v StateSetValue) String() string {
+	return string(v)
+}
@@ -0,0 +1,481 @@
+package prometheus
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	model "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/proto"
+)
+
+// OpenMetrics family types, as they appear in TYPE declarations.
+const (
+	omCounter        = "counter"
+	omGauge          = "gauge"
+	omHistogram      = "histogram"
+	omGaugeHistogram = "gaugehistogram"
+	omSummary        = "summary"
+	omInfo           = "info"
+	omStateset       = "stateset"
+	omUnknown        = "unknown"
+)
+
+// omSuffixes holds the suffixes samples of each family type can have, besides the family name itself.
+var omSuffixes = map[string][]string{
+	omCounter:        {"_total", "_created"},
+	omHistogram:      {"_bucket", "_count", "_sum", "_created"},
+	omGaugeHistogram: {"_bucket", "_gcount", "_gsum"},
+	omSummary:        {"_count", "_sum", "_created"},
+	omInfo:           {"_info"},
+}
+
+// openMetricsDecoder decodes the OpenMetrics text format into metric families, one family at a time as the body is
+// read. It satisfies the expfmt.Decoder interface, so it can be used interchangeably with the decoders of that
+// package.
+//
+// Family types with no equivalent in the Prometheus data model are mapped as follows:
+//   - info families are named after their `_info` samples.
+//   - stateset families have one metric per state, labeled with the family name.
+//
+// Both hold their samples as gauges and are typed as metricTypeInfo and metricTypeStateSet respectively.
+//   - unknown families are exposed as untyped.
+//
+// Counters are named after their `_total` samples, so their names match the ones in the Prometheus text format.
+// Exemplars and `_created` samples are ignored, as are the samples of families not accepted by wanted.
+type openMetricsDecoder struct {
+	scanner *bufio.Scanner
+	wanted  func(name string) bool
+	line    int
+	current *omFamily
+	done    bool
+}
+
+func newOpenMetricsDecoder(r io.Reader, wanted func(name string) bool) *openMetricsDecoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), math.MaxInt32)
+
+	return &openMetricsDecoder{scanner: scanner, wanted: wanted}
+}
+
+// Decode reads the next metric family into v. It returns io.EOF when there are no more families.
+func (d *openMetricsDecoder) Decode(v *model.MetricFamily) error {
+	for !d.done {
+		finished, err := d.readLine()
+		if err != nil {
+			return fmt.Errorf("line %d: %w", d.line, err)
+		}
+
+		if finished != nil && len(finished.metrics) > 0 {
+			finished.writeTo(v)
+			return nil
+		}
+	}
+
+	if d.current != nil {
+		finished := d.current
+		d.current = nil
+		if len(finished.metrics) > 0 {
+			finished.writeTo(v)
+			return nil
+		}
+	}
+
+	return io.EOF
+}
+
+// readLine processes the next line of the body, and returns the family being read if the line belongs to a new one.
+func (d *openMetricsDecoder) readLine() (*omFamily, error) {
+	if !d.scanner.Scan() {
+		d.done = true
+		return nil, d.scanner.Err()
+	}
+	d.line++
+
+	line := strings.TrimSpace(d.scanner.Text())
+	switch {
+	case line == "":
+		return nil, nil
+	case line == "# EOF":
+		d.done = true
+		return nil, nil
+	case strings.HasPrefix(line, "#"):
+		return d.readMetadata(line)
+	default:
+		return d.readSample(line)
+	}
+}
+
+// readMetadata processes HELP, TYPE and UNIT lines. Other comments are ignored.
+func (d *openMetricsDecoder) readMetadata(line string) (*omFamily, error) {
+	parts := strings.SplitN(line, " ", 4)
+	if len(parts) < 3 {
+		return nil, nil
+	}
+
+	keyword, name := parts[1], parts[2]
+	if keyword != "HELP" && keyword != "TYPE" && keyword != "UNIT" {
+		return nil, nil
+	}
+
+	var finished *omFamily
+	if d.current == nil || d.current.name != name {
+		finished = d.current
+		d.current = newOMFamily(name, omUnknown)
+	}
+
+	var text string
+	if len(parts) == 4 {
+		text = parts[3]
+	}
+
+	switch keyword {
+	case "HELP":
+		d.current.help = omHelpReplacer.Replace(text)
+	case "TYPE":
+		if _, ok := omModelTypes[text]; !ok {
+			return nil, fmt.Errorf("unknown type %q for family %q", text, name)
+		}
+		d.current.typ = text
+	}
+
+	return finished, nil
+}
+
+// readSample adds the sample in line to the family being read, or to a new one if it does not belong to it.
+func (d *openMetricsDecoder) readSample(line string) (*omFamily, error) {
+	s, err := parseOMSample(line)
+	if err != nil {
+		return nil, err
+	}
+
+	var finished *omFamily
+	if d.current == nil || !d.current.owns(s.name) {
+		finished = d.current
+		d.current = newOMFamily(s.name, omUnknown)
+	}
+
+	// The type of the family is known once its first sample is read, and so is the name it will be exposed with.
+	if d.current.wanted == nil {
+		wanted := d.wanted(d.current.exposedName())
+		d.current.wanted = &wanted
+	}
+
+	if !*d.current.wanted {
+		return finished, nil
+	}
+
+	return finished, d.current.add(s)
+}
+
+// metricTypeInfo and metricTypeStateSet extend model.MetricType with the OpenMetrics info and stateset types, so
+// decoded families can be told apart from gauges, whose metrics they use, until queries are executed on them.
+// They are never marshalled.
+const (
+	metricTypeInfo model.MetricType = iota + 100
+	metricTypeStateSet
+)
+
+// omModelTypes maps OpenMetrics family types to Prometheus metric types.
+var omModelTypes = map[string]model.MetricType{
+	omCounter:        model.MetricType_COUNTER,
+	omGauge:          model.MetricType_GAUGE,
+	omHistogram:      model.MetricType_HISTOGRAM,
+	omGaugeHistogram: model.MetricType_GAUGE_HISTOGRAM,
+	omSummary:        model.MetricType_SUMMARY,
+	omInfo:           metricTypeInfo,
+	omStateset:       metricTypeStateSet,
+	omUnknown:        model.MetricType_UNTYPED,
+}
+
+var omHelpReplacer = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\"`, `"`)
+
+// omFamily holds the metrics of a family while it is being read.
+type omFamily struct {
+	name    string
+	help    string
+	typ     string
+	metrics []*model.Metric
+	// index holds the metrics of the family by their label signature, to group samples of the same metric.
+	index map[string]*model.Metric
+	// wanted is nil until the first sample of the family is read.
+	wanted *bool
+}
+
+func newOMFamily(name, typ string) *omFamily {
+	return &omFamily{name: name, typ: typ, index: map[string]*model.Metric{}}
+}
+
+// owns returns whether a sample with the given name belongs to the family.
+func (f *omFamily) owns(sampleName string) bool {
+	if sampleName == f.name {
+		return true
+	}
+
+	for _, suffix := range omSuffixes[f.typ] {
+		if sampleName == f.name+suffix {
+			return true
+		}
+	}
+
+	return false
+}
+
+// add adds a sample to the metric of the family it belongs to.
+func (f *omFamily) add(s omSample) error {
+	suffix := strings.TrimPrefix(s.name, f.name)
+	if suffix == "_created" {
+		return nil
+	}
+
+	switch f.typ {
+	case omCounter:
+		f.metric(s, "").Counter = &model.Counter{Value: proto.Float64(s.value)}
+	case omGauge, omInfo, omStateset:
+		f.metric(s, "").Gauge = &model.Gauge{Value: proto.Float64(s.value)}
+	case omUnknown:
+		f.metric(s, "").Untyped = &model.Untyped{Value: proto.Float64(s.value)}
+	case omHistogram, omGaugeHistogram:
+		return f.addHistogramSample(s, suffix)
+	case omSummary:
+		return f.addSummarySample(s, suffix)
+	}
+
+	return nil
+}
+
+func (f *omFamily) addHistogramSample(s omSample, suffix string) error {
+	m := f.metric(s, "le")
+	if m.Histogram == nil {
+		m.Histogram = &model.Histogram{}
+	}
+
+	switch suffix {
+	case "_bucket":
+		le, ok := s.label("le")
+		if !ok {
+			return fmt.Errorf("bucket of %q has no le label", f.name)
+		}
+
+		upperBound, err := strconv.ParseFloat(le, 64)
+		if err != nil {
+			return fmt.Errorf("parsing le label of %q: %w", f.name, err)
+		}
+
+		m.Histogram.Bucket = append(m.Histogram.Bucket, &model.Bucket{
+			UpperBound:      proto.Float64(upperBound),
+			CumulativeCount: proto.Uint64(uint64(s.value)),
+		})
+	case "_count", "_gcount":
+		m.Histogram.SampleCount = proto.Uint64(uint64(s.value))
+	case "_sum", "_gsum":
+		m.Histogram.SampleSum = proto.Float64(s.value)
+	}
+
+	return nil
+}
+
+func (f *omFamily) addSummarySample(s omSample, suffix string) error {
+	m := f.metric(s, "quantile")
+	if m.Summary == nil {
+		m.Summary = &model.Summary{}
+	}
+
+	switch suffix {
+	case "":
+		q, ok := s.label("quantile")
+		if !ok {
+			return fmt.Errorf("sample of %q has no quantile label", f.name)
+		}
+
+		quantile, err := strconv.ParseFloat(q, 64)
+		if err != nil {
+			return fmt.Errorf("parsing quantile label of %q: %w", f.name, err)
+		}
+
+		m.Summary.Quantile = append(m.Summary.Quantile, &model.Quantile{
+			Quantile: proto.Float64(quantile),
+			Value:    proto.Float64(s.value),
+		})
+	case "_count":
+		m.Summary.SampleCount = proto.Uint64(uint64(s.value))
+	case "_sum":
+		m.Summary.SampleSum = proto.Float64(s.value)
+	}
+
+	return nil
+}
+
+// metric returns the metric of the family the sample belongs to, creating it if needed. The label named ignoredLabel,
+// which identifies a sample within a metric, is not part of the metric labels.
+func (f *omFamily) metric(s omSample, ignoredLabel string) *model.Metric {
+	labels := make([]*model.LabelPair, 0, len(s.labels))
+	for _, l := range s.labels {
+		if l.GetName() != ignoredLabel {
+			labels = append(labels, l)
+		}
+	}
+
+	sort.Slice(labels, func(i, j int) bool { return labels[i].GetName() < labels[j].GetName() })
+
+	signature := strings.Builder{}
+	for _, l := range labels {
+		signature.WriteString(l.GetName())
+		signature.WriteByte(0)
+		signature.WriteString(l.GetValue())
+		signature.WriteByte(0)
+	}
+
+	if m, ok := f.index[signature.String()]; ok {
+		return m
+	}
+
+	m := &model.Metric{Label: labels, TimestampMs: s.timestampMs}
+	f.index[signature.String()] = m
+	f.metrics = append(f.metrics, m)
+
+	return m
+}
+
+// exposedName returns the name of the family once converted to the Prometheus data model.
+func (f *omFamily) exposedName() string {
+	switch {
+	case f.typ == omCounter && !strings.HasSuffix(f.name, "_total"):
+		return f.name + "_total"
+	case f.typ == omInfo && !strings.HasSuffix(f.name, "_info"):
+		return f.name + "_info"
+	default:
+		return f.name
+	}
+}
+
+// writeTo fills v with the contents of the family.
+func (f *omFamily) writeTo(v *model.MetricFamily) {
+	v.Reset()
+	v.Name = proto.String(f.exposedName())
+	v.Type = omModelTypes[f.typ].Enum()
+	v.Metric = f.metrics
+	if f.help != "" {
+		v.Help = proto.String(f.help)
+	}
+}
+
+// omSample is a sample line of the OpenMetrics format.
+type omSample struct {
+	name        string
+	labels      []*model.LabelPair
+	value       float64
+	timestampMs *int64
+}
+
+func (s omSample) label(name string) (string, bool) {
+	for _, l := range s.labels {
+		if l.GetName() == name {
+			return l.GetValue(), true
+		}
+	}
+
+	return "", false
+}
+
+// parseOMSample parses a sample line with the form `name{label="value",...} value [timestamp] [# exemplar]`.
+func parseOMSample(line string) (omSample, error) {
+	var s omSample
+
+	end := strings.IndexAny(line, "{ ")
+	if end <= 0 {
+		return s, fmt.Errorf("invalid sample %q", line)
+	}
+	s.name = line[:end]
+	rest := line[end:]
+
+	if rest[0] == '{' {
+		labels, n, err := parseOMLabels(rest[1:])
+		if err != nil {
+			return s, fmt.Errorf("parsing labels of %q: %w", s.name, err)
+		}
+		s.labels = labels
+		rest = rest[1+n:]
+	}
+
+	if i := strings.Index(rest, "#"); i >= 0 {
+		rest = rest[:i]
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 || len(fields) > 2 {
+		return s, fmt.Errorf("invalid value for %q: %q", s.name, rest)
+	}
+
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return s, fmt.Errorf("parsing value of %q: %w", s.name, err)
+	}
+	s.value = value
+
+	if len(fields) == 2 {
+		seconds, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return s, fmt.Errorf("parsing timestamp of %q: %w", s.name, err)
+		}
+		s.timestampMs = proto.Int64(int64(math.Round(seconds * 1000)))
+	}
+
+	return s, nil
+}
+
+// parseOMLabels parses a label set, right after its opening brace, and returns the labels and the number of bytes
+// read, including the closing brace.
+func parseOMLabels(s string) ([]*model.LabelPair, int, error) {
+	var labels []*model.LabelPair
+
+	i := 0
+	for {
+		for i < len(s) && (s[i] == ' ' || s[i] == ',') {
+			i++
+		}
+
+		if i >= len(s) {
+			return nil, 0, fmt.Errorf("unterminated label set")
+		}
+
+		if s[i] == '}' {
+			return labels, i + 1, nil
+		}
+
+		eq := strings.IndexByte(s[i:], '=')
+		if eq <= 0 || i+eq+1 >= len(s) || s[i+eq+1] != '"' {
+			return nil, 0, fmt.Errorf("invalid label at %q", s[i:])
+		}
+
+		name := strings.TrimSpace(s[i : i+eq])
+		i += eq + 2
+
+		value := strings.Builder{}
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] != '\\' || i+1 >= len(s) {
+				value.WriteByte(s[i])
+				continue
+			}
+
+			i++
+			switch s[i] {
+			case 'n':
+				value.WriteByte('\n')
+			default:
+				value.WriteByte(s[i])
+			}
+		}
+
+		if i >= len(s) {
+			return nil, 0, fmt.Errorf("unterminated value for label %q", name)
+		}
+		i++
+
+		labels = append(labels, &model.LabelPair{Name: proto.String(name), Value: proto.String(value.String())})
+	}
+}
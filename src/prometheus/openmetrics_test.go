@@ -0,0 +1,198 @@
+package prometheus
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	model "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/logutil"
+)
+
+const openMetricsPayload = `# HELP kube_pod_container_status_restarts Number of restarts.
+# TYPE kube_pod_container_status_restarts counter
+kube_pod_container_status_restarts_total{namespace="default",pod="nginx",container="nginx"} 3 # {trace_id="abc"} 1 1620000000
+kube_pod_container_status_restarts_created{namespace="default",pod="nginx",container="nginx"} 1620000000
+# TYPE kube_gitrepository_resource info
+kube_gitrepository_resource_info{name="podinfo",exported_namespace="flux-system"} 1
+# TYPE kube_custom_elasticsearch_health_status stateset
+kube_custom_elasticsearch_health_status{kube_custom_elasticsearch_health_status="green"} 1
+kube_custom_elasticsearch_health_status{kube_custom_elasticsearch_health_status="red"} 0
+# TYPE kube_replicaset_status_replicas gauge
+# UNIT kube_replicaset_status_replicas replicas
+kube_replicaset_status_replicas{namespace="default",replicaset="nginx-123",description="a \"quoted\" \\ value"} 3 1620000000.5
+# TYPE apiserver_request_duration_seconds histogram
+apiserver_request_duration_seconds_bucket{verb="GET",le="0.1"} 50
+apiserver_request_duration_seconds_bucket{verb="GET",le="0.5"} 90
+apiserver_request_duration_seconds_bucket{verb="GET",le="1"} 100
+apiserver_request_duration_seconds_bucket{verb="GET",le="+Inf"} 100
+apiserver_request_duration_seconds_count{verb="GET"} 100
+apiserver_request_duration_seconds_sum{verb="GET"} 20
+# TYPE scheduler_e2e_scheduling_duration_seconds summary
+scheduler_e2e_scheduling_duration_seconds{quantile="0.5"} 0.01
+scheduler_e2e_scheduling_duration_seconds{quantile="0.99"} 0.05
+scheduler_e2e_scheduling_duration_seconds_count 10
+scheduler_e2e_scheduling_duration_seconds_sum 0.2
+untyped_metric 7
+# EOF
+`
+
+func decodeAll(t *testing.T, decoder expfmt.Decoder) map[string]*model.MetricFamily {
+	t.Helper()
+
+	families := map[string]*model.MetricFamily{}
+	for {
+		mf := &model.MetricFamily{}
+		err := decoder.Decode(mf)
+		if err == io.EOF {
+			return families
+		}
+
+		require.NoError(t, err)
+		families[mf.GetName()] = mf
+	}
+}
+
+func TestOpenMetricsDecoder(t *testing.T) {
+	t.Parallel()
+
+	families := decodeAll(t, newOpenMetricsDecoder(strings.NewReader(openMetricsPayload), acceptAll))
+	require.Len(t, families, 7)
+
+	restarts := families["kube_pod_container_status_restarts_total"]
+	require.NotNil(t, restarts)
+	assert.Equal(t, model.MetricType_COUNTER, restarts.GetType())
+	assert.Equal(t, "Number of restarts.", restarts.GetHelp())
+	require.Len(t, restarts.Metric, 1)
+	assert.Equal(t, 3.0, restarts.Metric[0].GetCounter().GetValue())
+	assert.Equal(t, Labels{"namespace": "default", "pod": "nginx", "container": "nginx"}, labelsFromPrometheus(restarts.Metric[0].Label))
+
+	info := families["kube_gitrepository_resource_info"]
+	require.NotNil(t, info)
+	assert.Equal(t, metricTypeInfo, info.GetType())
+	assert.Equal(t, Labels{"name": "podinfo", "exported_namespace": "flux-system"}, labelsFromPrometheus(info.Metric[0].Label))
+
+	stateset := families["kube_custom_elasticsearch_health_status"]
+	require.NotNil(t, stateset)
+	assert.Equal(t, metricTypeStateSet, stateset.GetType())
+	assert.Len(t, stateset.Metric, 2)
+
+	replicas := families["kube_replicaset_status_replicas"]
+	require.NotNil(t, replicas)
+	assert.Equal(t, `a "quoted" \ value`, labelsFromPrometheus(replicas.Metric[0].Label)["description"])
+	assert.Equal(t, int64(1620000000500), replicas.Metric[0].GetTimestampMs())
+
+	histogram := families["apiserver_request_duration_seconds"]
+	require.NotNil(t, histogram)
+	assert.Equal(t, model.MetricType_HISTOGRAM, histogram.GetType())
+	require.Len(t, histogram.Metric, 1)
+	assert.Equal(t, testHistogram(), histogramFromPrometheus(histogram.Metric[0].GetHistogram()))
+
+	summary := families["scheduler_e2e_scheduling_duration_seconds"]
+	require.NotNil(t, summary)
+	assert.Equal(t, model.MetricType_SUMMARY, summary.GetType())
+	assert.Equal(t, uint64(10), summary.Metric[0].GetSummary().GetSampleCount())
+	assert.Len(t, summary.Metric[0].GetSummary().GetQuantile(), 2)
+
+	untyped := families["untyped_metric"]
+	require.NotNil(t, untyped)
+	assert.Equal(t, model.MetricType_UNTYPED, untyped.GetType())
+	assert.Equal(t, 7.0, untyped.Metric[0].GetUntyped().GetValue())
+}
+
+func TestOpenMetricsDecoder_fails_on_invalid_lines(t *testing.T) {
+	t.Parallel()
+
+	for name, payload := range map[string]string{
+		"unknown_type":       "# TYPE foo bar\nfoo 1\n",
+		"missing_value":      "foo{a=\"b\"}\n",
+		"invalid_value":      "foo abc\n",
+		"unterminated_label": "foo{a=\"b} 1\n",
+		"bucket_without_le":  "# TYPE foo histogram\nfoo_bucket 1\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			err := newOpenMetricsDecoder(strings.NewReader(payload), acceptAll).Decode(&model.MetricFamily{})
+			assert.Error(t, err)
+			assert.NotErrorIs(t, err, io.EOF)
+		})
+	}
+}
+
+func TestGetFilteredMetricFamilies_negotiates_format(t *testing.T) {
+	t.Parallel()
+
+	protoBody := &bytes.Buffer{}
+	encoder := expfmt.NewEncoder(protoBody, expfmt.NewFormat(expfmt.TypeProtoDelim))
+	require.NoError(t, encoder.Encode(&model.MetricFamily{
+		Name: proto.String("kube_replicaset_status_replicas"),
+		Type: model.MetricType_GAUGE.Enum(),
+		Metric: []*model.Metric{{
+			Label: []*model.LabelPair{{Name: proto.String("replicaset"), Value: proto.String("nginx-123")}},
+			Gauge: &model.Gauge{Value: proto.Float64(3)},
+		}},
+	}))
+
+	for name, tc := range map[string]struct {
+		contentType string
+		body        string
+	}{
+		"protobuf":    {contentType: string(expfmt.NewFormat(expfmt.TypeProtoDelim)), body: protoBody.String()},
+		"openmetrics": {contentType: string(expfmt.NewFormat(expfmt.TypeOpenMetrics)), body: openMetricsPayload},
+		"text": {
+			contentType: string(expfmt.NewFormat(expfmt.TypeTextPlain)),
+			body:        "# TYPE kube_replicaset_status_replicas gauge\nkube_replicaset_status_replicas{replicaset=\"nginx-123\"} 3\n",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, AcceptHeader, r.Header.Get("Accept"))
+				w.Header().Set("Content-Type", tc.contentType)
+				_, _ = io.WriteString(w, tc.body)
+			}))
+			defer server.Close()
+
+			families, err := GetFilteredMetricFamilies(context.Background(), server.Client(), server.URL, []Query{
+				{MetricName: "kube_replicaset_status_replicas"},
+			}, logutil.Discard)
+			require.NoError(t, err)
+			require.Len(t, families, 1)
+			require.Len(t, families[0].Metrics, 1)
+			assert.Equal(t, "nginx-123", families[0].Metrics[0].Labels["replicaset"])
+			assert.Equal(t, GaugeValue(3), families[0].Metrics[0].Value)
+		})
+	}
+}
+
+func TestGetFilteredMetricFamilies_keeps_info_and_stateset_in_openmetrics(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", string(expfmt.NewFormat(expfmt.TypeOpenMetrics)))
+		_, _ = io.WriteString(w, openMetricsPayload)
+	}))
+	defer server.Close()
+
+	families, err := GetFilteredMetricFamilies(context.Background(), server.Client(), server.URL, []Query{
+		{MetricName: "kube_gitrepository_resource_info"},
+		{MetricName: "kube_custom_elasticsearch_health_status"},
+	}, logutil.Discard)
+	require.NoError(t, err)
+	assert.Len(t, families, 2)
+}
+
+func acceptAll(string) bool {
+	return true
+}
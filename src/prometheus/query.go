@@ -1,12 +1,15 @@
 package prometheus
 
 import (
-	"bufio"
-	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"sort"
 	"strings"
+	"time"
 
 	model "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
@@ -33,8 +36,12 @@ const (
 type Query struct {
 	CustomName string
 	MetricName string
-	Labels     QueryLabels
-	Value      QueryValue // TODO Only supported Counter and Gauge
+	// MetricNameMatcher, if set, makes the query run against every metric family whose name it matches instead of
+	// the one named MetricName. Resulting families keep their original names, so CustomName is ignored. Families also
+	// selected by a query for their exact name, or by an earlier matcher, are not emitted again.
+	MetricNameMatcher *MetricNameMatcher
+	Labels            QueryLabels
+	Value             QueryValue // TODO Only supported Counter and Gauge
 }
 
 // QueryValue represents the query for a value.
@@ -44,14 +51,37 @@ type QueryValue struct {
 }
 
 // QueryLabels represents the query for labels.
+// Metrics must match Labels according to Operator, and also all the Matchers.
 type QueryLabels struct {
 	Operator QueryOperator
 	Labels   Labels
+	Matchers []LabelMatcher
 }
 
-// Execute runs the query.
+// matchMatchers returns whether the label pairs satisfy all the matchers.
+func (l QueryLabels) matchMatchers(pairs []*model.LabelPair) bool {
+	for _, m := range l.Matchers {
+		if !m.Matches(pairs) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesName returns whether the query applies to metric families with the given name.
+func (q Query) matchesName(name string) bool {
+	if q.MetricNameMatcher != nil {
+		return q.MetricNameMatcher.Matches(name)
+	}
+
+	return name == q.MetricName
+}
+
+// Execute runs the query. The metrics of stateset families, one per state, are collapsed into one metric per label
+// set holding a StateSetValue.
 func (q Query) Execute(promMetricFamily *model.MetricFamily) (metricFamily MetricFamily) {
-	if promMetricFamily.GetName() != q.MetricName {
+	if !q.matchesName(promMetricFamily.GetName()) {
 		return
 	}
 
@@ -75,6 +105,10 @@ func (q Query) Execute(promMetricFamily *model.MetricFamily) (metricFamily Metri
 			}
 		}
 
+		if !q.Labels.matchMatchers(promMetric.Label) {
+			continue
+		}
+
 		value := valueFromPrometheus(promMetricFamily.GetType(), promMetric)
 
 		if q.Value.Value != nil {
@@ -95,11 +129,19 @@ func (q Query) Execute(promMetricFamily *model.MetricFamily) (metricFamily Metri
 			Value:  value,
 		}
 
+		if promMetric.TimestampMs != nil {
+			m.Timestamp = time.UnixMilli(promMetric.GetTimestampMs())
+		}
+
 		matches = append(matches, m)
 	}
 
+	if promMetricFamily.GetType() == metricTypeStateSet {
+		matches = activeStates(promMetricFamily.GetName(), matches)
+	}
+
 	var name string
-	if q.CustomName != "" {
+	if q.CustomName != "" && q.MetricNameMatcher == nil {
 		name = q.CustomName
 	} else {
 		name = promMetricFamily.GetName()
@@ -107,7 +149,7 @@ func (q Query) Execute(promMetricFamily *model.MetricFamily) (metricFamily Metri
 
 	metricFamily = MetricFamily{
 		Name:    name,
-		Type:    promMetricFamily.GetType().String(),
+		Type:    familyType(promMetricFamily.GetType()),
 		Metrics: matches,
 	}
 
@@ -118,11 +160,10 @@ func valueFromPrometheus(metricType model.MetricType, metric *model.Metric) Valu
 	switch metricType {
 	case model.MetricType_COUNTER:
 		return CounterValue(metric.Counter.GetValue())
-	case model.MetricType_GAUGE:
+	case model.MetricType_GAUGE, metricTypeInfo, metricTypeStateSet:
 		return GaugeValue(metric.Gauge.GetValue())
-	case model.MetricType_HISTOGRAM:
-		// Not supported yet
-		fallthrough
+	case model.MetricType_HISTOGRAM, model.MetricType_GAUGE_HISTOGRAM:
+		return histogramFromPrometheus(metric.Histogram)
 	case model.MetricType_SUMMARY:
 		return metric.Summary
 	case model.MetricType_UNTYPED:
@@ -133,163 +174,132 @@ func valueFromPrometheus(metricType model.MetricType, metric *model.Metric) Valu
 	}
 }
 
-const (
-	// Minimum number of fields in a TYPE declaration: "# TYPE metric_name metric_type".
-	minTypeFields = 4
-	// Minimum number of fields in a HELP declaration: "# HELP metric_name description".
-	minHelpFields = 3
-)
-
-// isUnsupportedMetricType checks if a metric type is unsupported by prometheus/client_model.
-// OpenMetrics 1.0 types "info" and "stateset" are not supported.
-func isUnsupportedMetricType(metricType string) bool {
-	return metricType == "info" || metricType == "stateset"
-}
-
-// handleTypeDeclaration processes a TYPE declaration line and determines if the metric family
-// should be skipped. Returns (shouldSkip, metricName).
-func handleTypeDeclaration(line string, logger *log.Logger) (bool, string) {
-	parts := strings.Fields(line)
-	if len(parts) < minTypeFields {
-		return false, ""
-	}
-
-	metricName := parts[2]
-	metricType := parts[3]
-
-	if isUnsupportedMetricType(metricType) {
-		logger.Debugf("Skipping unsupported metric type '%s' for metric '%s'", metricType, metricName)
-		return true, metricName
-	}
-
-	return false, ""
-}
-
-// handleHelpDeclaration processes a HELP declaration line and updates skip state if we
-// encounter a different metric family. Returns (shouldSkip, currentMetricName).
-func handleHelpDeclaration(line string, currentlySkipping bool, currentMetricName string) (bool, string) {
-	parts := strings.Fields(line)
-	if len(parts) < minHelpFields {
-		return currentlySkipping, currentMetricName
-	}
-
-	metricName := parts[2]
-	// If we were skipping and now see a different metric, stop skipping
-	if currentlySkipping && currentMetricName != "" && metricName != currentMetricName {
-		return false, ""
-	}
-
-	return currentlySkipping, currentMetricName
-}
-
-// shouldSkipLine determines if a line should be skipped when filtering an unsupported metric family.
-func shouldSkipLine(line string, currentMetricName string) bool {
-	// Skip HELP line for the current unsupported metric
-	if strings.HasPrefix(line, "# HELP ") {
-		parts := strings.Fields(line)
-		if len(parts) >= minHelpFields && parts[2] == currentMetricName {
-			return true
-		}
+// familyType returns the name of the type of a family, as held by MetricFamily.
+func familyType(metricType model.MetricType) string {
+	switch metricType {
+	case metricTypeInfo:
+		return InfoType
+	case metricTypeStateSet:
+		return StateSetType
+	default:
+		return metricType.String()
 	}
-	// Skip metric data lines (non-comment lines)
-	return !strings.HasPrefix(line, "#")
 }
 
-// filterUnsupportedMetrics preprocesses Prometheus exposition format text to remove
-// metric families with unsupported types (e.g., OpenMetrics "info", "stateset").
-//
-// The Prometheus TextParser (expfmt.TextParser) fails when it encounters unknown metric types,
-// stopping parsing and losing all subsequent metrics. This function removes those problematic
-// metric families before parsing.
-//
-// Returns a new io.Reader with filtered content and a list of skipped metric names.
-func filterUnsupportedMetrics(body io.Reader, logger *log.Logger) (io.Reader, []string, error) {
-	scanner := bufio.NewScanner(body)
-	var filteredLines []string
-	var skippedMetrics []string
-	var skipUntilNextFamily bool
-	var currentMetricName string
-
-	for scanner.Scan() {
-		line := scanner.Text() // Preserve original formatting
-
-		trimmedLine := strings.TrimSpace(line)
-
-		// Preserve empty lines
-		if trimmedLine == "" {
-			if !skipUntilNextFamily {
-				filteredLines = append(filteredLines, line)
-			}
+// activeStates collapses the metrics of a stateset family into one metric per label set, whose value holds the states
+// active for it. The state of each metric is held in the label named after the family. Label sets with no active
+// state are dropped.
+func activeStates(stateLabel string, metrics []Metric) []Metric {
+	collapsed := make([]Metric, 0, len(metrics))
+	states := make([][]string, 0, len(metrics))
+	index := map[string]int{}
+
+	for _, m := range metrics {
+		state, ok := m.Labels[stateLabel]
+		if !ok {
 			continue
 		}
 
-		// Check if this is a TYPE declaration
-		if strings.HasPrefix(trimmedLine, "# TYPE ") {
-			skipUntilNextFamily, currentMetricName = handleTypeDeclaration(trimmedLine, logger)
-			if skipUntilNextFamily {
-				skippedMetrics = append(skippedMetrics, currentMetricName)
-				continue
+		labels := make(Labels, len(m.Labels)-1)
+		for k, v := range m.Labels {
+			if k != stateLabel {
+				labels[k] = v
 			}
 		}
 
-		// Check if this is a HELP declaration for a new metric family
-		if strings.HasPrefix(trimmedLine, "# HELP ") {
-			skipUntilNextFamily, currentMetricName = handleHelpDeclaration(
-				trimmedLine, skipUntilNextFamily, currentMetricName,
-			)
+		signature := suffixLabelsInOrder("", labels)
+		i, ok := index[signature]
+		if !ok {
+			i = len(collapsed)
+			index[signature] = i
+			collapsed = append(collapsed, Metric{Labels: labels})
+			states = append(states, nil)
 		}
 
-		// Skip metric data lines if we're in an unsupported family
-		if skipUntilNextFamily && shouldSkipLine(trimmedLine, currentMetricName) {
-			continue
+		if m.Timestamp.After(collapsed[i].Timestamp) {
+			collapsed[i].Timestamp = m.Timestamp
 		}
 
-		// Keep this line
-		filteredLines = append(filteredLines, line)
+		if value, ok := m.Value.(GaugeValue); ok && value != 0 {
+			states[i] = append(states[i], state)
+		}
 	}
 
-	if err := scanner.Err(); err != nil {
-		return nil, skippedMetrics, fmt.Errorf("reading metrics body: %w", err)
+	active := collapsed[:0]
+	for i, m := range collapsed {
+		if len(states[i]) == 0 {
+			continue
+		}
+
+		sort.Strings(states[i])
+		m.Value = StateSetValue(strings.Join(states[i], ","))
+		active = append(active, m)
 	}
 
-	// Reconstruct the filtered text
-	filtered := strings.Join(filteredLines, "\n") + "\n" // Add trailing newline
-	return bytes.NewBufferString(filtered), skippedMetrics, nil
+	return active
 }
 
 /**
- * Try our best to parse a response. Even if an error is encountered
- * midway through parsing we will put into the receiving channel any
- * metric families found along the way. We also return any error that
- * we did come along. Fail-fast, best attempt behavior.
+ * Try our best to parse a response. Metric families are sent to the
+ * receiving channel as soon as they are decoded, so only the family being
+ * decoded is held in memory. Families whose name is not accepted by wanted,
+ * if set, are discarded as early as the format allows. Even if an error is
+ * encountered midway through parsing, the families found along the way have
+ * already been sent. Fail-fast, best attempt behavior.
  */
-func parseResponse(resp *http.Response, ch chan<- *model.MetricFamily, logger *log.Logger) error {
+func parseResponse(resp *http.Response, ch chan<- *model.MetricFamily, logger *log.Logger, wanted func(name string) bool) error {
 	defer close(ch)
 
-	// Filter out unsupported metric types before parsing to prevent parser from failing.
-	// This solves issue #1293 where OpenMetrics "info" types cause complete data loss.
-	filtered, skippedMetrics, err := filterUnsupportedMetrics(resp.Body, logger)
-	if err != nil {
-		return fmt.Errorf("filtering unsupported metrics: %w", err)
+	if wanted == nil {
+		wanted = func(string) bool { return true }
 	}
 
-	if len(skippedMetrics) > 0 {
-		logger.Infof("Skipped %d metric families with unsupported OpenMetrics types: %v", len(skippedMetrics), skippedMetrics)
+	var decoder expfmt.Decoder
+	switch format := responseFormat(resp.Header); format {
+	case expfmt.TypeProtoDelim:
+		protoFormat := expfmt.NewFormat(expfmt.TypeProtoDelim).WithEscapingScheme(prometheusmodel.NoEscaping)
+		decoder = expfmt.NewDecoder(resp.Body, protoFormat)
+	case expfmt.TypeOpenMetrics:
+		decoder = newOpenMetricsDecoder(resp.Body, wanted)
+	default:
+		decoder = newTextDecoder(resp.Body, wanted, logger)
 	}
 
-	// Use NewTextParser with UTF8 validation scheme instead of zero-value TextParser.
-	// The zero-value TextParser has an unset validation scheme which causes a panic.
-	parser := expfmt.NewTextParser(prometheusmodel.UTF8Validation)
-	metricFamilies, err := parser.TextToMetricFamilies(filtered)
+	for {
+		mf := &model.MetricFamily{}
+		err := decoder.Decode(mf)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("decoding metric families: %w", err)
+		}
+
+		if wanted(mf.GetName()) {
+			ch <- mf
+		}
+	}
+}
+
+// responseFormat returns the exposition format of a response from its Content-Type header. The text format is
+// assumed if the header is missing or unknown.
+func responseFormat(header http.Header) expfmt.FormatType {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
 	if err != nil {
-		err = fmt.Errorf("reading text format failed: %w", err)
+		return expfmt.TypeTextPlain
 	}
 
-	for _, mf := range metricFamilies {
-		ch <- mf
+	switch mediaType {
+	case expfmt.ProtoType:
+		if params["proto"] == expfmt.ProtoProtocol && params["encoding"] == "delimited" {
+			return expfmt.TypeProtoDelim
+		}
+	case expfmt.OpenMetricsType:
+		return expfmt.TypeOpenMetrics
 	}
 
-	return err
+	return expfmt.TypeTextPlain
 }
 
 func handleResponseWithFilter(resp *http.Response, queries []Query, logger *log.Logger) ([]MetricFamily, error) {
@@ -306,20 +316,38 @@ func handleResponseWithFilter(resp *http.Response, queries []Query, logger *log.
 	metrics := make([]MetricFamily, 0)
 	ch := make(chan *model.MetricFamily)
 
-	var err error
+	wanted := func(name string) bool {
+		for _, q := range queries {
+			if q.matchesName(name) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	// parseResponse closes ch before returning, so its error is only read once ch is drained.
+	errCh := make(chan error, 1)
 	go func() {
-		err = parseResponse(resp, ch, logger)
+		errCh <- parseResponse(resp, ch, logger, wanted)
 	}()
 
+	ordered := byPrecedence(queries)
 	for promMetricFamily := range ch {
-		for _, q := range queries {
+		emitted := map[string]bool{}
+		for _, q := range ordered {
 			f := q.Execute(promMetricFamily)
-			if f.valid() {
-				metrics = append(metrics, f)
+			if !f.valid() || emitted[f.Name] {
+				continue
 			}
+
+			emitted[f.Name] = true
+			metrics = append(metrics, f)
 		}
 	}
 
+	err := <-errCh
+
 	// parseResponse does some lenient parsing so metrics may be non-empty
 	// even when err is non-nil. We handle the cases here
 	if err != nil && len(metrics) > 0 {
@@ -335,6 +363,27 @@ func handleResponseWithFilter(resp *http.Response, queries []Query, logger *log.
 	return metrics, nil
 }
 
+// byPrecedence returns the queries in the order they are executed against each family: queries for an exact metric
+// name go before the ones using a MetricNameMatcher, keeping the relative order otherwise. As a family is emitted at
+// most once under each name, a query by exact name always wins over a matcher selecting the same family, and the first
+// of several matchers selecting the same family wins over the rest.
+func byPrecedence(queries []Query) []Query {
+	ordered := make([]Query, 0, len(queries))
+	for _, q := range queries {
+		if q.MetricNameMatcher == nil {
+			ordered = append(ordered, q)
+		}
+	}
+
+	for _, q := range queries {
+		if q.MetricNameMatcher != nil {
+			ordered = append(ordered, q)
+		}
+	}
+
+	return ordered
+}
+
 // MetricFamiliesGetFunc is the interface satisfied by prometheus Client.
 // TODO: This whole flow is too convoluted, we should refactor and rename this.
 type MetricFamiliesGetFunc interface {
@@ -343,13 +392,14 @@ type MetricFamiliesGetFunc interface {
 	MetricFamiliesGetFunc(url string) FetchAndFilterMetricsFamilies
 }
 
-type FetchAndFilterMetricsFamilies func([]Query) ([]MetricFamily, error)
+type FetchAndFilterMetricsFamilies func(context.Context, []Query) ([]MetricFamily, error)
 
-func GetFilteredMetricFamilies(httpClient client.HTTPDoer, url string, queries []Query, logger *log.Logger) ([]MetricFamily, error) {
+// GetFilteredMetricFamilies fetches the metrics exposed in url and returns the families matching queries. The request
+// is bound to ctx, so cancelling it aborts the request and any pending retries.
+func GetFilteredMetricFamilies(ctx context.Context, httpClient client.HTTPDoer, url string, queries []Query, logger *log.Logger) ([]MetricFamily, error) {
 	logger.Debugf("Calling a prometheus endpoint: %s", url)
 
-	// todo it would be nice to have context with deadline
-	req, err := NewRequest(url)
+	req, err := NewRequest(ctx, url)
 	if err != nil {
 		return nil, fmt.Errorf("building request: %w", err)
 	}
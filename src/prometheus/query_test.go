@@ -4,7 +4,9 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/newrelic/nri-kubernetes/v3/internal/logutil"
 	model "github.com/prometheus/client_model/go"
@@ -190,6 +192,66 @@ func TestQueryMatch_CustomName(t *testing.T) {
 	assert.Equal(t, expectedMetrics, q.Execute(&r))
 }
 
+func TestQueryExecute_KeepsTimestamp(t *testing.T) {
+	q := Query{MetricName: "container_cpu_usage_seconds_total"}
+
+	metricType := model.MetricType_COUNTER
+	r := model.MetricFamily{
+		Name: proto.String(q.MetricName),
+		Type: &metricType,
+		Metric: []*model.Metric{
+			{
+				Counter:     &model.Counter{Value: proto.Float64(10)},
+				TimestampMs: proto.Int64(1700000000123),
+			},
+			{
+				Counter: &model.Counter{Value: proto.Float64(20)},
+			},
+		},
+	}
+
+	mf := q.Execute(&r)
+	assert.Len(t, mf.Metrics, 2)
+	assert.Equal(t, time.UnixMilli(1700000000123), mf.Metrics[0].Timestamp)
+	assert.True(t, mf.Metrics[1].Timestamp.IsZero())
+}
+
+func TestQueryExecute_OpenMetricsTypes(t *testing.T) {
+	t.Parallel()
+
+	payload := `# TYPE kube_customresource_ready info
+kube_customresource_ready_info{name="podinfo",ready="True"} 1
+# TYPE kube_custom_elasticsearch_health_status stateset
+kube_custom_elasticsearch_health_status{name="logs",kube_custom_elasticsearch_health_status="green"} 1
+kube_custom_elasticsearch_health_status{name="logs",kube_custom_elasticsearch_health_status="red"} 0
+kube_custom_elasticsearch_health_status{name="metrics",kube_custom_elasticsearch_health_status="green"} 0
+kube_custom_elasticsearch_health_status{name="metrics",kube_custom_elasticsearch_health_status="red"} 1
+kube_custom_elasticsearch_health_status{name="idle",kube_custom_elasticsearch_health_status="green"} 0
+kube_custom_elasticsearch_health_status{name="idle",kube_custom_elasticsearch_health_status="red"} 0
+`
+
+	families := decodeAll(t, newTextDecoder(strings.NewReader(payload), acceptAll, logutil.Discard))
+
+	info := Query{MetricName: "kube_customresource_ready_info"}.Execute(families["kube_customresource_ready_info"])
+	assert.Equal(t, MetricFamily{
+		Name: "kube_customresource_ready_info",
+		Type: InfoType,
+		Metrics: []Metric{
+			{Labels: Labels{"name": "podinfo", "ready": "True"}, Value: GaugeValue(1)},
+		},
+	}, info)
+
+	stateset := Query{MetricName: "kube_custom_elasticsearch_health_status"}.Execute(families["kube_custom_elasticsearch_health_status"])
+	assert.Equal(t, MetricFamily{
+		Name: "kube_custom_elasticsearch_health_status",
+		Type: StateSetType,
+		Metrics: []Metric{
+			{Labels: Labels{"name": "logs"}, Value: StateSetValue("green")},
+			{Labels: Labels{"name": "metrics"}, Value: StateSetValue("red")},
+		},
+	}, stateset)
+}
+
 //nolint:bodyclose
 func TestParseResponse(t *testing.T) {
 	t.Parallel()
@@ -233,13 +295,13 @@ func TestParseResponse(t *testing.T) {
 
 	logger := logutil.Discard
 
-	var errOne error
-	var errTwo error
+	errChOne := make(chan error, 1)
+	errChTwo := make(chan error, 1)
 	go func() {
-		errOne = parseResponse(responseOne, chOne, logger)
+		errChOne <- parseResponse(responseOne, chOne, logger, nil)
 	}()
 	go func() {
-		errTwo = parseResponse(responseTwo, chTwo, logger)
+		errChTwo <- parseResponse(responseTwo, chTwo, logger, nil)
 	}()
 
 	var oneFamilies int
@@ -253,25 +315,27 @@ func TestParseResponse(t *testing.T) {
 		twoFamilies++
 	}
 
-	// With the new filtering logic, unsupported metric types are filtered out,
-	// so parsing should succeed and we should get all supported metrics regardless of position.
-	assert.Equal(t, 1, oneFamilies, "Should parse gauge metric before stateset")
-	assert.Equal(t, 1, twoFamilies, "Should filter out stateset and parse gauge metric that comes after")
+	// Statesets are parsed along with the other families regardless of their position.
+	assert.Equal(t, 2, oneFamilies, "Should parse gauge metric and stateset")
+	assert.Equal(t, 2, twoFamilies, "Should parse stateset and gauge metric that comes after")
+	errOne, errTwo := <-errChOne, <-errChTwo
 	assert.Nil(t, errOne, "Should not error when stateset comes after supported types")
-	assert.Nil(t, errTwo, "Should not error when stateset is filtered out")
+	assert.Nil(t, errTwo, "Should not error when stateset comes before supported types")
 }
 
 // verifyReplicaSetMetrics is a helper function that verifies metric families contain
-// the expected ReplicaSet metrics with correct names, values, and no info metrics.
+// the expected ReplicaSet and info metrics with correct names, types and values.
 func verifyReplicaSetMetrics(t *testing.T, metricFamilies []*model.MetricFamily, expectedMetricNames map[string]bool) {
 	t.Helper()
 
 	for _, mf := range metricFamilies {
 		assert.True(t, expectedMetricNames[mf.GetName()], "Unexpected metric family: %s", mf.GetName())
-		assert.NotEqual(t, "kube_gitrepository_resource_info", mf.GetName(), "Info metric should have been filtered out")
 
 		// Verify metrics have the expected labels and values
-		if mf.GetName() == "kube_replicaset_created" {
+		if mf.GetName() == "kube_gitrepository_resource_info" {
+			assert.Equal(t, metricTypeInfo, mf.GetType())
+			assert.Equal(t, "podinfo", labelsFromPrometheus(mf.GetMetric()[0].Label)["name"])
+		} else if mf.GetName() == "kube_replicaset_created" {
 			assert.Len(t, mf.GetMetric(), 1, "Should have 1 metric")
 			assert.Equal(t, float64(1620000000), mf.GetMetric()[0].GetGauge().GetValue())
 		} else if mf.GetName() == "kube_replicaset_status_replicas" {
@@ -281,8 +345,18 @@ func verifyReplicaSetMetrics(t *testing.T, metricFamilies []*model.MetricFamily,
 	}
 }
 
+func TestHandleResponseWithFilter_ReturnsDecodeErrors(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	_, _ = io.WriteString(w, "# TYPE broken gauge\nbroken{ 1\n")
+
+	metrics, err := handleResponseWithFilter(w.Result(), []Query{{MetricName: "broken"}}, logutil.Discard)
+	assert.Error(t, err, "Decode errors must be returned once every family is read")
+	assert.Empty(t, metrics)
+}
+
 // TestParseResponseWithInfoMetric tests that "info" type metrics (OpenMetrics 1.0)
-// are filtered out gracefully without losing subsequent metrics.
+// are parsed without losing subsequent metrics.
 // This test reproduces and validates the fix for issue #1293 where FluxCD info metrics
 // appear before ReplicaSet metrics, causing complete data loss.
 func TestParseResponseWithInfoMetric(t *testing.T) {
@@ -336,18 +410,18 @@ func TestParseResponseWithInfoMetric(t *testing.T) {
 
 	logger := logutil.Discard
 
-	var errOne error
-	var errTwo error
+	errChOne := make(chan error, 1)
+	errChTwo := make(chan error, 1)
 	go func() {
-		errOne = parseResponse(responseOne, chOne, logger)
+		errChOne <- parseResponse(responseOne, chOne, logger, nil)
 	}()
 	go func() {
-		errTwo = parseResponse(responseTwo, chTwo, logger)
+		errChTwo <- parseResponse(responseTwo, chTwo, logger, nil)
 	}()
 
 	// Pre-allocate slices with expected capacity
-	metricFamiliesOne := make([]*model.MetricFamily, 0, 2)
-	metricFamiliesTwo := make([]*model.MetricFamily, 0, 2)
+	metricFamiliesOne := make([]*model.MetricFamily, 0, 3)
+	metricFamiliesTwo := make([]*model.MetricFamily, 0, 3)
 
 	for mf := range chOne {
 		metricFamiliesOne = append(metricFamiliesOne, mf)
@@ -356,20 +430,21 @@ func TestParseResponseWithInfoMetric(t *testing.T) {
 		metricFamiliesTwo = append(metricFamiliesTwo, mf)
 	}
 
-	// Both scenarios should succeed and return ReplicaSet metrics.
-	// The info metrics should be filtered out transparently before parsing.
+	// Both scenarios should succeed and return ReplicaSet metrics along with the info one.
 	// This validates the fix for issue #1293.
-	assert.Nil(t, errOne, "Should not error when info type is filtered out")
-	assert.Nil(t, errTwo, "Should not error when info type is filtered out")
+	errOne, errTwo := <-errChOne, <-errChTwo
+	assert.Nil(t, errOne, "Should not error when info type comes first")
+	assert.Nil(t, errTwo, "Should not error when info type comes last")
 
-	// Verify we got exactly 2 metric families (ReplicaSet metrics only, info filtered out)
-	assert.Len(t, metricFamiliesOne, 2, "Should parse both ReplicaSet metrics even when info comes first (issue #1293)")
-	assert.Len(t, metricFamiliesTwo, 2, "Should parse both ReplicaSet metrics when info comes last")
+	// Verify we got exactly 3 metric families (ReplicaSet metrics and info)
+	assert.Len(t, metricFamiliesOne, 3, "Should parse both ReplicaSet metrics even when info comes first (issue #1293)")
+	assert.Len(t, metricFamiliesTwo, 3, "Should parse both ReplicaSet metrics when info comes last")
 
-	// Verify the metric families are the expected ReplicaSet metrics
+	// Verify the metric families are the expected ReplicaSet and info metrics
 	expectedMetricNames := map[string]bool{
-		"kube_replicaset_created":         true,
-		"kube_replicaset_status_replicas": true,
+		"kube_gitrepository_resource_info": true,
+		"kube_replicaset_created":          true,
+		"kube_replicaset_status_replicas":  true,
 	}
 
 	verifyReplicaSetMetrics(t, metricFamiliesOne, expectedMetricNames)
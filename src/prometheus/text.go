@@ -0,0 +1,224 @@
+package prometheus
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	model "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	prometheusmodel "github.com/prometheus/common/model"
+	log "github.com/sirupsen/logrus"
+)
+
+// textDecoder decodes the Prometheus text format one metric family at a time. It splits the body in the lines of
+// each family, and only parses the ones of families accepted by wanted, so the memory needed is bounded by the size
+// of the largest wanted family instead of the whole body.
+//
+// The OpenMetrics info and stateset types, which the text parser does not support, are parsed as gauges and typed as
+// in openMetricsDecoder afterwards.
+type textDecoder struct {
+	scanner *bufio.Scanner
+	wanted  func(name string) bool
+	logger  *log.Logger
+
+	current *textFamily
+	parsed  []*model.MetricFamily
+	done    bool
+}
+
+// textFamily holds the lines of a family while it is being read.
+type textFamily struct {
+	name string
+	typ  string
+	// modelType is the type of the parsed family, for types the text parser does not support.
+	modelType *model.MetricType
+	// skip is true if the lines of the family do not need to be kept.
+	skip  bool
+	lines bytes.Buffer
+}
+
+func newTextDecoder(r io.Reader, wanted func(name string) bool, logger *log.Logger) *textDecoder {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), math.MaxInt32)
+
+	return &textDecoder{
+		scanner: scanner,
+		wanted:  wanted,
+		logger:  logger,
+	}
+}
+
+// Decode reads the next wanted metric family into v. It returns io.EOF when there are no more families.
+func (d *textDecoder) Decode(v *model.MetricFamily) error {
+	for len(d.parsed) == 0 {
+		if d.done {
+			return io.EOF
+		}
+
+		finished, err := d.readFamily()
+		if err != nil {
+			return err
+		}
+
+		if err := d.parse(finished); err != nil {
+			return err
+		}
+	}
+
+	next := d.parsed[0]
+	d.parsed = d.parsed[1:]
+
+	v.Reset()
+	v.Name = next.Name
+	v.Help = next.Help
+	v.Type = next.Type
+	v.Metric = next.Metric
+
+	return nil
+}
+
+// readFamily reads lines until a line of a different family, or the end of the body, is found, and returns the
+// family that has been completed.
+func (d *textDecoder) readFamily() (*textFamily, error) {
+	for d.scanner.Scan() {
+		line := strings.TrimSpace(d.scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		name, typ, isMetadata := textLineFamily(line)
+		if name == "" {
+			continue
+		}
+
+		var finished *textFamily
+		if d.current == nil || !d.current.owns(name, isMetadata) {
+			finished = d.current
+			d.current = &textFamily{name: name, skip: !d.wanted(name)}
+		}
+
+		if typ != "" {
+			d.current.typ = typ
+			if modelType, ok := textOpenMetricsTypes[typ]; ok {
+				d.logger.Tracef("Parsing %s metric %q as gauge", typ, name)
+				d.current.modelType = modelType.Enum()
+				d.current.skip = !d.wanted(d.current.exposedName())
+				line = "# TYPE " + d.current.exposedName() + " gauge"
+			}
+		}
+
+		if !d.current.skip {
+			d.current.lines.WriteString(line)
+			d.current.lines.WriteByte('\n')
+		}
+
+		if finished != nil {
+			return finished, nil
+		}
+	}
+
+	d.done = true
+	if err := d.scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading metrics body: %w", err)
+	}
+
+	finished := d.current
+	d.current = nil
+
+	return finished, nil
+}
+
+// parse parses the lines of a family, if they were kept, and queues the result to be returned by Decode.
+func (d *textDecoder) parse(family *textFamily) error {
+	if family == nil || family.skip || family.lines.Len() == 0 {
+		return nil
+	}
+
+	// Use NewTextParser with UTF8 validation scheme instead of zero-value TextParser.
+	// The zero-value TextParser has an unset validation scheme which causes a panic.
+	parser := expfmt.NewTextParser(prometheusmodel.UTF8Validation)
+	families, err := parser.TextToMetricFamilies(&family.lines)
+	if err != nil {
+		return fmt.Errorf("reading text format failed: %w", err)
+	}
+
+	for _, mf := range families {
+		if len(mf.Metric) == 0 {
+			continue
+		}
+
+		if family.modelType != nil && mf.GetName() == family.exposedName() {
+			mf.Type = family.modelType
+		}
+
+		d.parsed = append(d.parsed, mf)
+	}
+
+	return nil
+}
+
+// owns returns whether a line referring to the given name belongs to the family. Samples of histograms and summaries
+// have suffixes added to the family name.
+func (f *textFamily) owns(name string, isMetadata bool) bool {
+	if name == f.name {
+		return true
+	}
+
+	if isMetadata {
+		return false
+	}
+
+	switch f.typ {
+	case "histogram", "summary":
+		return name == f.name+"_bucket" || name == f.name+"_count" || name == f.name+"_sum"
+	case omInfo:
+		return name == f.exposedName()
+	default:
+		return false
+	}
+}
+
+// exposedName returns the name of the samples of the family. Info families declared without the `_info` suffix, as
+// OpenMetrics does, have it in their samples.
+func (f *textFamily) exposedName() string {
+	if f.typ == omInfo && !strings.HasSuffix(f.name, "_info") {
+		return f.name + "_info"
+	}
+
+	return f.name
+}
+
+// textOpenMetricsTypes maps the OpenMetrics types the text parser does not support to the types of the families
+// parsed from them.
+var textOpenMetricsTypes = map[string]model.MetricType{
+	omInfo:     metricTypeInfo,
+	omStateset: metricTypeStateSet,
+}
+
+// textLineFamily returns the name a line refers to: the family name for HELP and TYPE lines, or the sample name
+// for samples. typ holds the declared type for TYPE lines. Other comments return an empty name.
+func textLineFamily(line string) (name, typ string, isMetadata bool) {
+	if strings.HasPrefix(line, "#") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 || (fields[1] != "HELP" && fields[1] != "TYPE") {
+			return "", "", false
+		}
+
+		if fields[1] == "TYPE" && len(fields) > 3 {
+			typ = fields[3]
+		}
+
+		return fields[2], typ, true
+	}
+
+	end := strings.IndexAny(line, "{ \t")
+	if end < 0 {
+		return line, "", false
+	}
+
+	return line[:end], "", false
+}
@@ -0,0 +1,128 @@
+package prometheus
+
+import (
+	"strings"
+	"testing"
+
+	model "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/newrelic/nri-kubernetes/v3/internal/logutil"
+)
+
+const textPayload = `# HELP kube_pod_status_phase The pods current phase.
+# TYPE kube_pod_status_phase gauge
+kube_pod_status_phase{namespace="default",pod="nginx",phase="Running"} 1
+kube_pod_status_phase{namespace="default",pod="nginx",phase="Pending"} 0
+# HELP kube_gitrepository_resource_info The current state of a GitOps Toolkit resource
+# TYPE kube_gitrepository_resource_info info
+kube_gitrepository_resource_info{name="podinfo"} 1
+# TYPE apiserver_request_duration_seconds histogram
+apiserver_request_duration_seconds_bucket{verb="GET",le="0.1"} 50
+apiserver_request_duration_seconds_bucket{verb="GET",le="0.5"} 90
+apiserver_request_duration_seconds_bucket{verb="GET",le="1"} 100
+apiserver_request_duration_seconds_bucket{verb="GET",le="+Inf"} 100
+apiserver_request_duration_seconds_sum{verb="GET"} 20
+apiserver_request_duration_seconds_count{verb="GET"} 100
+# A comment that does not belong to any family
+untyped_metric 7
+`
+
+func TestTextDecoder(t *testing.T) {
+	t.Parallel()
+
+	decoder := newTextDecoder(strings.NewReader(textPayload), acceptAll, logutil.Discard)
+	families := decodeAll(t, decoder)
+	require.Len(t, families, 4)
+
+	assert.Len(t, families["kube_pod_status_phase"].Metric, 2)
+	assert.Equal(t, model.MetricType_GAUGE, families["kube_pod_status_phase"].GetType())
+	assert.Equal(t, "The pods current phase.", families["kube_pod_status_phase"].GetHelp())
+
+	histogram := families["apiserver_request_duration_seconds"]
+	require.NotNil(t, histogram)
+	require.Len(t, histogram.Metric, 1)
+	assert.Equal(t, testHistogram(), histogramFromPrometheus(histogram.Metric[0].GetHistogram()))
+
+	assert.Equal(t, model.MetricType_UNTYPED, families["untyped_metric"].GetType())
+
+	info := families["kube_gitrepository_resource_info"]
+	require.NotNil(t, info)
+	assert.Equal(t, metricTypeInfo, info.GetType())
+	assert.Equal(t, Labels{"name": "podinfo"}, labelsFromPrometheus(info.Metric[0].Label))
+}
+
+func TestTextDecoder_parses_openmetrics_types(t *testing.T) {
+	t.Parallel()
+
+	payload := `# TYPE kube_customresource_ready info
+kube_customresource_ready_info{name="podinfo"} 1
+# HELP kube_custom_elasticsearch_health_status Health of the cluster.
+# TYPE kube_custom_elasticsearch_health_status stateset
+kube_custom_elasticsearch_health_status{name="logs",kube_custom_elasticsearch_health_status="green"} 1
+kube_custom_elasticsearch_health_status{name="logs",kube_custom_elasticsearch_health_status="red"} 0
+# TYPE kube_pod_status_phase gauge
+kube_pod_status_phase{namespace="default",pod="nginx",phase="Running"} 1
+`
+
+	families := decodeAll(t, newTextDecoder(strings.NewReader(payload), acceptAll, logutil.Discard))
+	require.Len(t, families, 3)
+
+	info := families["kube_customresource_ready_info"]
+	require.NotNil(t, info)
+	assert.Equal(t, metricTypeInfo, info.GetType())
+
+	stateset := families["kube_custom_elasticsearch_health_status"]
+	require.NotNil(t, stateset)
+	assert.Equal(t, metricTypeStateSet, stateset.GetType())
+	assert.Equal(t, "Health of the cluster.", stateset.GetHelp())
+	assert.Len(t, stateset.Metric, 2)
+
+	assert.Equal(t, model.MetricType_GAUGE, families["kube_pod_status_phase"].GetType())
+}
+
+func TestTextDecoder_does_not_parse_unwanted_families(t *testing.T) {
+	t.Parallel()
+
+	payload := `# TYPE unwanted_metric gauge
+unwanted_metric{label="value"} not-a-number
+# TYPE kube_pod_status_phase gauge
+kube_pod_status_phase{namespace="default",pod="nginx",phase="Running"} 1
+`
+
+	decoder := newTextDecoder(strings.NewReader(payload), func(name string) bool {
+		return name == "kube_pod_status_phase"
+	}, logutil.Discard)
+
+	families := decodeAll(t, decoder)
+	require.Len(t, families, 1)
+	assert.Contains(t, families, "kube_pod_status_phase")
+}
+
+func TestTextDecoder_fails_on_invalid_wanted_family(t *testing.T) {
+	t.Parallel()
+
+	payload := `# TYPE kube_pod_status_phase gauge
+kube_pod_status_phase{namespace="default",pod="nginx",phase="Running"} not-a-number
+`
+
+	decoder := newTextDecoder(strings.NewReader(payload), acceptAll, logutil.Discard)
+	assert.Error(t, decoder.Decode(&model.MetricFamily{}))
+}
+
+func TestTextDecoder_skips_families_not_matching_queries(t *testing.T) {
+	t.Parallel()
+
+	w := strings.Builder{}
+	w.WriteString("# TYPE unwanted_metric gauge\n")
+	for i := 0; i < 1000; i++ {
+		w.WriteString("unwanted_metric{label=\"value\"} not-a-number\n")
+	}
+	w.WriteString(textPayload)
+
+	decoder := newTextDecoder(strings.NewReader(w.String()), Query{MetricName: "untyped_metric"}.matchesName, logutil.Discard)
+	families := decodeAll(t, decoder)
+	require.Len(t, families, 1)
+	assert.Contains(t, families, "untyped_metric")
+}
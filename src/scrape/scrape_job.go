@@ -1,6 +1,7 @@
 package scrape
 
 import (
+	"context"
 	"errors"
 
 	"github.com/newrelic/infra-integrations-sdk/integration"
@@ -8,9 +9,14 @@ import (
 	log "github.com/sirupsen/logrus"
 	"k8s.io/apimachinery/pkg/version"
 
+	"github.com/newrelic/nri-kubernetes/v3/internal/attributes"
 	"github.com/newrelic/nri-kubernetes/v3/internal/discovery"
+	"github.com/newrelic/nri-kubernetes/v3/internal/entities"
+	"github.com/newrelic/nri-kubernetes/v3/internal/labels"
+	"github.com/newrelic/nri-kubernetes/v3/internal/storer"
 	"github.com/newrelic/nri-kubernetes/v3/src/data"
 	"github.com/newrelic/nri-kubernetes/v3/src/definition"
+	"github.com/newrelic/nri-kubernetes/v3/src/dimensional"
 )
 
 // JobOpt are options that can be used to configure the ScrapeJob
@@ -37,6 +43,20 @@ type Job struct {
 	Grouper  data.Grouper
 	Specs    definition.SpecGroups
 	Filterer discovery.NamespaceFilterer
+	Samples  storer.Storer
+	Labels   *labels.Guard
+	// Attributes adds custom attributes to the entities populated by the job.
+	Attributes *attributes.Decorator
+	// Entities drops, reduces or samples the entities populated by the job.
+	Entities *entities.Filter
+	// OptOut skips the entities of namespaces and pods opting out of being scraped, and counts them.
+	OptOut *discovery.OptOutFilter
+	// Dimensional, if set, receives the metrics of the job as dimensional metrics instead of the integration.
+	Dimensional *dimensional.Emitter
+	// Errors, if set, logs a report of the errors of every populate cycle of the job.
+	Errors *populator.ErrorReporter
+	// Workers is the number of entities populated concurrently. If not positive, GOMAXPROCS is used.
+	Workers int
 }
 
 // JobWithFilterer returns an OptionFunc to add a Filterer.
@@ -46,14 +66,79 @@ func JobWithFilterer(filterer discovery.NamespaceFilterer) JobOpt {
 	}
 }
 
-// Populate will get the data using the given Group, transform it, and push it to the given Integration
+// JobWithOptOutFilter returns an OptionFunc to skip the entities of namespaces and pods opting out of being scraped,
+// as told by filter.
+func JobWithOptOutFilter(filter *discovery.OptOutFilter) JobOpt {
+	return func(j *Job) {
+		j.OptOut = filter
+	}
+}
+
+// JobWithSampleStore returns an OptionFunc to keep the timestamped samples of the job in store, so RATE and DELTA
+// metrics are computed over the time elapsed between samples. store must outlive the job to be of any use.
+func JobWithSampleStore(store storer.Storer) JobOpt {
+	return func(j *Job) {
+		j.Samples = store
+	}
+}
+
+// JobWithLabelGuard returns an OptionFunc to limit the label and annotation attributes of the entities populated by
+// the job.
+func JobWithLabelGuard(guard *labels.Guard) JobOpt {
+	return func(j *Job) {
+		j.Labels = guard
+	}
+}
+
+// JobWithCustomAttributes returns an OptionFunc to add the custom attributes computed by decorator to the entities
+// populated by the job.
+func JobWithCustomAttributes(decorator *attributes.Decorator) JobOpt {
+	return func(j *Job) {
+		j.Attributes = decorator
+	}
+}
+
+// JobWithEntityFilter returns an OptionFunc to drop, reduce or sample the entities populated by the job as decided by
+// filter.
+func JobWithEntityFilter(filter *entities.Filter) JobOpt {
+	return func(j *Job) {
+		j.Entities = filter
+	}
+}
+
+// JobWithDimensionalEmitter returns an OptionFunc to report the metrics of the job as dimensional metrics collected by
+// emitter, instead of metric sets of the integration.
+func JobWithDimensionalEmitter(emitter *dimensional.Emitter) JobOpt {
+	return func(j *Job) {
+		j.Dimensional = emitter
+	}
+}
+
+// JobWithErrorReporter returns an OptionFunc to log a report of the errors of every populate cycle of the job with
+// reporter, which must outlive the job to tell which failures changed between cycles.
+func JobWithErrorReporter(reporter *populator.ErrorReporter) JobOpt {
+	return func(j *Job) {
+		j.Errors = reporter
+	}
+}
+
+// JobWithWorkers returns an OptionFunc to populate up to workers entities of the job concurrently.
+func JobWithWorkers(workers int) JobOpt {
+	return func(j *Job) {
+		j.Workers = workers
+	}
+}
+
+// Populate will get the data using the given Group, transform it, and push it to the given Integration.
+// Cancelling ctx aborts any fetch the Grouper has in flight.
 func (s *Job) Populate(
+	ctx context.Context,
 	i *integration.Integration,
 	clusterName string,
 	logger *log.Logger,
 	k8sVersion *version.Info,
 ) data.PopulateResult {
-	groups, errs := s.Grouper.Group(s.Specs)
+	groups, errs := s.Grouper.Group(ctx, s.Specs)
 	if errs != nil {
 		if !errs.Recoverable {
 			return data.PopulateResult{
@@ -72,8 +157,16 @@ func (s *Job) Populate(
 		MsTypeGuesser: definition.K8sMetricSetTypeGuesser,
 		Groups:        groups,
 		Filterer:      s.Filterer,
+		OptOut:        s.OptOut,
+		Samples:       s.Samples,
+		Labels:        s.Labels,
+		Attributes:    s.Attributes,
+		Entities:      s.Entities,
+		Dimensional:   s.Dimensional,
+		Workers:       s.Workers,
 	}
 	ok, populateErrs := populator.IntegrationPopulator(config)
+	s.Errors.Log(logger, s.Name, populator.NewReport(groups, populateErrs))
 
 	if len(populateErrs) > 0 {
 		return data.PopulateResult{Errors: populateErrs, Populated: ok}
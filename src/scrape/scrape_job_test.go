@@ -1,6 +1,7 @@
 package scrape
 
 import (
+	"context"
 	"sort"
 	"strings"
 	"testing"
@@ -164,7 +165,7 @@ type grouperMock struct {
 	groupCallsCount int
 }
 
-func (g *grouperMock) Group(definition.SpecGroups) (definition.RawGroups, *data.ErrorGroup) {
+func (g *grouperMock) Group(context.Context, definition.SpecGroups) (definition.RawGroups, *data.ErrorGroup) {
 	// We reduce the test fixtures in order to simplify testing.
 	groupsDefinition := map[string]string{
 		"pod":       "kube-system_newrelic-infra-rz225",
@@ -208,7 +209,7 @@ func TestPopulateK8s(t *testing.T) {
 	testJob := NewScrapeJob("test", &grouperMock{}, kubeletSpecs)
 
 	k8sVersion := &version.Info{GitVersion: "v1.15.42"}
-	errPopulate := testJob.Populate(intgr, "test-cluster", logutil.Debug, k8sVersion)
+	errPopulate := testJob.Populate(context.Background(), intgr, "test-cluster", logutil.Debug, k8sVersion)
 	assert.Empty(t, errPopulate.Errors)
 
 	expectedInventory := inventory.New()
@@ -257,7 +258,7 @@ func TestRestartCountDeltaValues(t *testing.T) {
 	k8sVersion := &version.Info{GitVersion: "v1.15.42"}
 	// Populate data several times to check expected deltas
 	for i := 0; i < len(expectedRestartCountDeltas); i++ {
-		errPopulate := testJob.Populate(intgr, "test-cluster", logutil.Debug, k8sVersion)
+		errPopulate := testJob.Populate(context.Background(), intgr, "test-cluster", logutil.Debug, k8sVersion)
 		assert.Empty(t, errPopulate.Errors)
 		time.Sleep(time.Second)
 	}